@@ -0,0 +1,111 @@
+// Command unsplash-proxy serves a caching reverse proxy for the Unsplash API, so that apps
+// on a private network share one Client-ID without embedding it.
+//
+// Usage:
+//
+//	unsplash-proxy [flags]
+//
+// The Client-ID is read from the UNSPLASH_CLIENT_ID environment variable, so that it doesn't
+// show up in process listings. Consumers are read from a JSON file passed with --consumers:
+//
+//	[
+//	  {"name": "gallery", "key": "k3y-for-gallery", "limit": 500},
+//	  {"name": "batch", "key": "k3y-for-batch"}
+//	]
+//
+// Without consumers, any request is forwarded. Metrics are served at /_proxy/metrics,
+// in the Prometheus text format.
+//
+// With --images-dir, resized images are served at /images/{photoID}?w=&h=&fm=, cached on disk
+// in that directory. When consumers are configured, image requests need a consumer's key, e.g.
+// a client_id query parameter in HTML, and count against that consumer's quota.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/eddogola/unsplash-go/unsplash/client"
+	"github.com/eddogola/unsplash-go/unsplash/proxy"
+)
+
+func main() {
+	addr := flag.String("addr", ":8080", "address to listen on")
+	consumersFile := flag.String("consumers", "", "JSON file listing the consumers, their API keys and hourly quotas")
+	ttl := flag.Duration("cache-ttl", proxy.DefaultCacheTTL, "how long responses are cached, 0 to disable caching")
+	size := flag.Int("cache-size", proxy.DefaultCacheSize, "most responses cached")
+	timeout := flag.Duration("timeout", proxy.DefaultTimeout, "timeout of requests to the API")
+	imagesDir := flag.String("images-dir", "", "serve resized images at /images/, caching them in this directory")
+	imagesMax := flag.Int64("images-max-mb", 1024, "most megabytes of images cached")
+	track := flag.Bool("track-downloads", false, "track a download whenever an image is served")
+	flag.Parse()
+
+	images := imageOptions{*imagesDir, *imagesMax << 20, *track}
+	if err := serve(*addr, *consumersFile, *ttl, *size, *timeout, images); err != nil {
+		fmt.Fprintf(os.Stderr, "unsplash-proxy: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// imageOptions configures the image handler, which is disabled if dir is empty
+type imageOptions struct {
+	dir            string
+	maxBytes       int64
+	trackDownloads bool
+}
+
+func serve(addr, consumersFile string, ttl time.Duration, size int, timeout time.Duration, images imageOptions) error {
+	clientID := os.Getenv("UNSPLASH_CLIENT_ID")
+	if clientID == "" {
+		return fmt.Errorf("set UNSPLASH_CLIENT_ID")
+	}
+	var consumers []proxy.Consumer
+	if consumersFile != "" {
+		var err error
+		if consumers, err = proxy.LoadConsumers(consumersFile); err != nil {
+			return err
+		}
+	} else {
+		log.Print("no consumers configured, requests don't need an API key")
+	}
+
+	c := client.New(clientID, &http.Client{Timeout: timeout}, client.NewConfig())
+	s := proxy.New(c, consumers)
+	s.CacheTTL, s.CacheSize, s.Timeout = ttl, size, timeout
+	mux := http.NewServeMux()
+	mux.Handle("/", s)
+	if images.dir != "" {
+		h, err := proxy.NewImageHandler(c, images.dir, images.maxBytes)
+		if err != nil {
+			return err
+		}
+		h.TrackDownloads = images.trackDownloads
+		// images cost API calls too, so they need an API key and count against quotas
+		mux.Handle("/images/", s.Protect(http.StripPrefix("/images", h)))
+	}
+	srv := &http.Server{Addr: addr, Handler: mux}
+
+	// shut down gracefully on interrupt, letting requests in flight finish
+	stop := make(chan os.Signal, 1)
+	signal.Notify(stop, os.Interrupt, syscall.SIGTERM)
+	shutdown := make(chan error, 1)
+	go func() {
+		<-stop
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+		shutdown <- srv.Shutdown(ctx)
+	}()
+
+	log.Printf("listening on %s, %d consumers", addr, len(consumers))
+	if err := srv.ListenAndServe(); err != http.ErrServerClosed {
+		return err
+	}
+	return <-shutdown
+}
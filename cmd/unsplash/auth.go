@@ -0,0 +1,388 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/eddogola/unsplash-go/unsplash/client"
+	"golang.org/x/oauth2"
+)
+
+// oobRedirectURI makes Unsplash display the authorization code instead of redirecting,
+// for logging in where no browser can reach a loopback server
+const oobRedirectURI = "urn:ietf:wg:oauth:2.0:oob"
+
+// defaultRedirectURI is the loopback address the login callback is served on by default.
+// It must be listed among the application's redirect URIs on unsplash.com.
+const defaultRedirectURI = "http://localhost:8085/callback"
+
+var (
+	// stdin is read when prompting for an authorization code. It's replaced in tests.
+	stdin io.Reader = os.Stdin
+	// openBrowser opens link in the user's browser. It's replaced in tests.
+	openBrowser = func(link string) error {
+		var cmd *exec.Cmd
+		switch runtime.GOOS {
+		case "darwin":
+			cmd = exec.Command("open", link)
+		case "windows":
+			cmd = exec.Command("rundll32", "url.dll,FileProtocolHandler", link)
+		default:
+			cmd = exec.Command("xdg-open", link)
+		}
+		return cmd.Start()
+	}
+)
+
+var authCommands = map[string]command{
+	"login":  {"", "authorize the CLI to act on your behalf, storing the access token in a profile", authLogin},
+	"logout": {"", "remove a profile's stored access token", authLogout},
+	"status": {"", "show profiles, granted scopes and rate limit state", authStatus},
+}
+
+func authLogin(e *env, args []string) error {
+	clientSecret := e.fs.String("client-secret", "", "application secret key, defaults to $UNSPLASH_CLIENT_SECRET")
+	scopes := e.fs.String("scopes", strings.Join(append([]string{"public"}, allScopes...), ","), "comma separated scopes to request")
+	redirectURI := e.fs.String("redirect-uri", defaultRedirectURI, "redirect URI registered for the application, "+oobRedirectURI+" to paste the code")
+	noBrowser := e.fs.Bool("no-browser", false, "print the authorization link and prompt for the code instead of opening a browser")
+	timeout := e.fs.Duration("timeout", 5*time.Minute, "how long to wait for authorization")
+	if _, err := e.parse(args, "", 0); err != nil {
+		return err
+	}
+
+	conf, err := loadConfig()
+	if err != nil {
+		return err
+	}
+	name := e.profileName()
+	p := conf.profile(name)
+	clientID := firstNonEmpty(e.clientID, os.Getenv("UNSPLASH_CLIENT_ID"), os.Getenv("CLIENT_ID"), p.ClientID)
+	secret := firstNonEmpty(*clientSecret, os.Getenv("UNSPLASH_CLIENT_SECRET"), p.ClientSecret)
+	if clientID == "" || secret == "" {
+		e.fs.Usage()
+		return usageError("login requires the application's client ID and secret: use --client-id and --client-secret")
+	}
+
+	requested := strings.Split(*scopes, ",")
+	as := client.AuthScopes(requested)
+	oauthConf := client.NewUnsplashOauthConfig(clientID, secret, *redirectURI, &as)
+	state, err := randomState()
+	if err != nil {
+		return err
+	}
+	link := oauthConf.AuthCodeURL(state)
+
+	ctx, cancel := context.WithTimeout(context.Background(), *timeout)
+	defer cancel()
+	code, err := e.authorize(ctx, link, *redirectURI, state, *noBrowser)
+	if err != nil {
+		return err
+	}
+
+	if httpClient != nil {
+		ctx = context.WithValue(ctx, oauth2.HTTPClient, httpClient)
+	}
+	tok, err := oauthConf.Exchange(ctx, code)
+	if err != nil {
+		return fmt.Errorf("error exchanging authorization code: %w", err)
+	}
+	granted := requested
+	if scope, ok := tok.Extra("scope").(string); ok && scope != "" {
+		granted = strings.Fields(scope)
+	}
+
+	p.ClientID, p.ClientSecret = clientID, secret
+	p.AccessToken, p.RefreshToken, p.TokenType, p.Expiry = tok.AccessToken, tok.RefreshToken, tok.TokenType, tok.Expiry
+	p.Scopes = granted
+	p.Username = ""
+	p.CreatedAt = time.Now().UTC()
+	conf.Profiles[name] = p
+
+	// record who logged in, if the token allows it
+	if client.AuthScopes(granted).Contains(client.ReadUserScope) {
+		c := client.NewPrivateClient(clientID, tok, client.NewAuthScopes(granted...), client.NewConfig())
+		if httpClient != nil {
+			c.HTTPClient.Transport.(*oauth2.Transport).Base = httpClient.Transport
+		}
+		if me, err := c.GetUserPrivateProfile(ctx); err == nil {
+			p.Username = me.Username
+		} else {
+			fmt.Fprintf(e.stderr, "warning: could not get the logged in user's profile: %v\n", err)
+		}
+	}
+	if err := conf.save(); err != nil {
+		return err
+	}
+
+	who := "Logged in"
+	if p.Username != "" {
+		who += " as @" + p.Username
+	}
+	return e.printOK(fmt.Sprintf("%s, profile %q, scopes %s", who, name, strings.Join(granted, ",")))
+}
+
+// authorize sends the user to link, returning the authorization code Unsplash redirects back with.
+// The code is received by a loopback server at redirectURI, or pasted by the user if the redirect URI
+// is not a loopback address, noBrowser is set, or the loopback server cannot be started.
+func (e *env) authorize(ctx context.Context, link, redirectURI, state string, noBrowser bool) (string, error) {
+	u, err := url.Parse(redirectURI)
+	if err != nil {
+		return "", usageError(fmt.Sprintf("invalid redirect URI %q: %v", redirectURI, err))
+	}
+	if noBrowser || !isLoopback(u) {
+		return e.promptCode(ctx, link, state)
+	}
+	ln, err := net.Listen("tcp", u.Host)
+	if err != nil {
+		fmt.Fprintf(e.stderr, "warning: cannot listen for the login callback on %s: %v\n", u.Host, err)
+		return e.promptCode(ctx, link, state)
+	}
+
+	type result struct {
+		code string
+		err  error
+	}
+	results := make(chan result, 1)
+	mux := http.NewServeMux()
+	path := u.Path
+	if path == "" {
+		path = "/"
+	}
+	mux.HandleFunc(path, func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		if query.Get("code") == "" && query.Get("error") == "" {
+			// not the redirect, e.g. a browser requesting a favicon
+			http.NotFound(w, r)
+			return
+		}
+		code, err := callbackCode(query, state)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		} else {
+			fmt.Fprintln(w, "Logged in to Unsplash. You can close this window.")
+		}
+		select {
+		case results <- result{code, err}:
+		default:
+		}
+	})
+	srv := &http.Server{Handler: mux}
+	go srv.Serve(ln)
+	defer srv.Close()
+
+	fmt.Fprintf(e.stderr, "Opening your browser to authorize access. If it does not open, navigate to:\n%s\n\n", link)
+	if err := openBrowser(link); err != nil {
+		fmt.Fprintf(e.stderr, "warning: could not open a browser: %v\n", err)
+	}
+	select {
+	case res := <-results:
+		return res.code, res.err
+	case <-ctx.Done():
+		return "", fmt.Errorf("timed out waiting for authorization: %w", ctx.Err())
+	}
+}
+
+// promptCode asks the user to authorize access at link, then paste the authorization code,
+// or the whole URL they were redirected to
+func (e *env) promptCode(ctx context.Context, link, state string) (string, error) {
+	fmt.Fprintf(e.stderr, "Navigate to:\n%s\n\nAfter authorizing access, paste the authorization code, or the URL you were redirected to: ", link)
+	lines := make(chan string, 1)
+	go func() {
+		line, _ := bufio.NewReader(stdin).ReadString('\n')
+		lines <- strings.TrimSpace(line)
+	}()
+	var input string
+	select {
+	case input = <-lines:
+	case <-ctx.Done():
+		return "", fmt.Errorf("timed out waiting for authorization: %w", ctx.Err())
+	}
+	if input == "" {
+		return "", usageError("no authorization code entered")
+	}
+	if u, err := url.Parse(input); err == nil && u.RawQuery != "" {
+		return callbackCode(u.Query(), state)
+	}
+	return input, nil
+}
+
+// callbackCode returns the authorization code in the query of a login redirect,
+// checking the state matches the one sent. A redirect without the state is refused,
+// as it may have been forged to log in to another account.
+func callbackCode(query url.Values, state string) (string, error) {
+	if reason := query.Get("error"); reason != "" {
+		return "", fmt.Errorf("authorization denied: %s %s", reason, query.Get("error_description"))
+	}
+	if query.Get("state") != state {
+		return "", fmt.Errorf("authorization state mismatch, try logging in again")
+	}
+	code := query.Get("code")
+	if code == "" {
+		return "", fmt.Errorf("no authorization code in the redirect")
+	}
+	return code, nil
+}
+
+func isLoopback(u *url.URL) bool {
+	if u.Scheme != "http" {
+		return false
+	}
+	host := u.Hostname()
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+// randomState returns a random value sent with the authorization request,
+// to check the redirect was started by this login
+func randomState() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+func authLogout(e *env, args []string) error {
+	forget := e.fs.Bool("forget", false, "also remove the profile's client ID and secret")
+	if _, err := e.parse(args, "", 0); err != nil {
+		return err
+	}
+	conf, err := loadConfig()
+	if err != nil {
+		return err
+	}
+	name := e.profileName()
+	p, ok := conf.Profiles[name]
+	if !ok {
+		return fmt.Errorf("no profile named %q", name)
+	}
+	if *forget {
+		delete(conf.Profiles, name)
+	} else {
+		p.AccessToken, p.RefreshToken, p.TokenType, p.Expiry = "", "", "", time.Time{}
+		p.Scopes, p.Username = nil, ""
+	}
+	if err := conf.save(); err != nil {
+		return err
+	}
+	return e.printOK(fmt.Sprintf("Logged out of profile %q", name))
+}
+
+// authState defines the status reported for a profile
+type authState struct {
+	Profile   string            `json:"profile"`
+	Active    bool              `json:"active"`
+	ClientID  string            `json:"client_id,omitempty"`
+	LoggedIn  bool              `json:"logged_in"`
+	Username  string            `json:"username,omitempty"`
+	Scopes    []string          `json:"scopes,omitempty"`
+	RateLimit *client.RateLimit `json:"rate_limit,omitempty"`
+}
+
+func authStatus(e *env, args []string) error {
+	offline := e.fs.Bool("offline", false, "do not make a request to check the token and rate limit")
+	if _, err := e.parse(args, "", 0); err != nil {
+		return err
+	}
+	conf, err := loadConfig()
+	if err != nil {
+		return err
+	}
+	active := e.profileName()
+	names := conf.profileNames()
+	if _, ok := conf.Profiles[active]; !ok {
+		names = append(names, active)
+	}
+
+	var states []authState
+	for _, name := range names {
+		p := conf.profile(name)
+		states = append(states, authState{
+			Profile:  name,
+			Active:   name == active,
+			ClientID: maskSecret(p.ClientID),
+			LoggedIn: p.AccessToken != "",
+			Username: p.Username,
+			Scopes:   p.Scopes,
+		})
+	}
+	if !*offline {
+		for i := range states {
+			if states[i].Active {
+				if err := e.checkAuth(&states[i]); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	t := &table{headers: []string{"", "PROFILE", "CLIENT ID", "USER", "SCOPES", "RATE LIMIT"}}
+	for _, s := range states {
+		marker, user, rateLimit := "", "-", "-"
+		if s.Active {
+			marker = "*"
+		}
+		if s.LoggedIn {
+			user = "(logged in)"
+			if s.Username != "" {
+				user = "@" + s.Username
+			}
+		}
+		if s.RateLimit != nil {
+			rateLimit = fmt.Sprintf("%d/%d remaining", s.RateLimit.Remaining, s.RateLimit.Limit)
+		}
+		t.rows = append(t.rows, []string{marker, s.Profile, s.ClientID, user, strings.Join(s.Scopes, ","), rateLimit})
+	}
+	return e.print(states, t)
+}
+
+// checkAuth makes a request using the active profile's credentials, recording the rate limit
+// state, and the username if the profile is logged in
+func (e *env) checkAuth(s *authState) error {
+	loggedIn := s.LoggedIn || os.Getenv("UNSPLASH_ACCESS_TOKEN") != ""
+	c, err := e.client(loggedIn)
+	if err == errNoClientID {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	s.ClientID = maskSecret(c.ClientID)
+	ctx := context.Background()
+	if loggedIn && c.AuthScopes.Contains(client.ReadUserScope) {
+		me, err := c.GetUserPrivateProfile(ctx)
+		if err != nil {
+			return err
+		}
+		s.LoggedIn, s.Username = true, me.Username
+	} else if _, err := c.GetStatsTotal(ctx); err != nil {
+		return err
+	}
+	if rl, ok := c.RateLimit(); ok {
+		s.RateLimit = &rl
+	}
+	return nil
+}
+
+// maskSecret hides all but the first few characters of a credential
+func maskSecret(s string) string {
+	if len(s) <= 8 {
+		return s
+	}
+	return s[:6] + strings.Repeat("*", 6)
+}
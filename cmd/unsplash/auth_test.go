@@ -0,0 +1,173 @@
+package main
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"testing"
+)
+
+// authHandler serves the token exchange, the logged in user's profile and stats,
+// reporting rate limit headers on API responses
+func authHandler(t *testing.T) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/oauth/token":
+			r.ParseForm()
+			if r.Form.Get("code") != "the-code" {
+				t.Errorf("expected code %q to be exchanged, got %q", "the-code", r.Form.Get("code"))
+			}
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"access_token": "the-token", "token_type": "Bearer", "scope": "public read_user write_likes"}`))
+		case "/me":
+			if r.Header.Get("Authorization") != "Bearer the-token" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			w.Header().Set("X-Ratelimit-Limit", "50")
+			w.Header().Set("X-Ratelimit-Remaining", "48")
+			w.Write([]byte(`{"username": "jane"}`))
+		case "/stats/total":
+			w.Header().Set("X-Ratelimit-Limit", "50")
+			w.Header().Set("X-Ratelimit-Remaining", "49")
+			w.Write([]byte(`{}`))
+		default:
+			t.Errorf("unexpected request to %s", r.URL.Path)
+		}
+	}
+}
+
+func freePort(t *testing.T) int {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+	return ln.Addr().(*net.TCPAddr).Port
+}
+
+func TestAuthLoginLoopback(t *testing.T) {
+	cli := setup(t, authHandler(t))
+	redirectURI := fmt.Sprintf("http://127.0.0.1:%d/callback", freePort(t))
+	openBrowser = func(link string) error {
+		u, _ := url.Parse(link)
+		q := u.Query()
+		if q.Get("redirect_uri") != redirectURI || q.Get("client_id") != "test-client-id" {
+			t.Errorf("unexpected authorization link %s", link)
+		}
+		// the user authorizes access, being redirected to the callback
+		go http.Get(q.Get("redirect_uri") + "?code=the-code&state=" + q.Get("state"))
+		return nil
+	}
+	defer func() { openBrowser = nil }()
+
+	code, stdout, stderr := cli("auth", "login", "--profile", "work", "--client-secret", "secret", "--redirect-uri", redirectURI)
+	if code != exitOK {
+		t.Fatalf("expected exit code %d, got %d: %s", exitOK, code, stderr)
+	}
+	if !strings.Contains(stdout, "@jane") {
+		t.Errorf("expected the logged in user to be reported, got %q", stdout)
+	}
+
+	conf, err := loadConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := conf.Profiles["work"]
+	if p == nil || p.AccessToken != "the-token" || p.Username != "jane" || strings.Join(p.Scopes, ",") != "public,read_user,write_likes" {
+		t.Fatalf("unexpected stored profile %+v", p)
+	}
+	path, _ := configPath()
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if perm := info.Mode().Perm(); perm != 0600 {
+		t.Errorf("expected config file permissions 0600, got %o", perm)
+	}
+
+	// other commands pick up the profile
+	t.Setenv("UNSPLASH_CLIENT_ID", "")
+	code, stdout, stderr = cli("auth", "status", "--profile", "work")
+	if code != exitOK {
+		t.Fatalf("expected exit code %d, got %d: %s", exitOK, code, stderr)
+	}
+	for _, s := range []string{"work", "@jane", "48/50 remaining", "write_likes"} {
+		if !strings.Contains(stdout, s) {
+			t.Errorf("expected status to contain %q, got %q", s, stdout)
+		}
+	}
+
+	code, _, stderr = cli("auth", "logout", "--profile", "work")
+	if code != exitOK {
+		t.Fatalf("expected exit code %d, got %d: %s", exitOK, code, stderr)
+	}
+	conf, _ = loadConfig()
+	if p := conf.Profiles["work"]; p == nil || p.AccessToken != "" || p.ClientID != "test-client-id" {
+		t.Errorf("expected logout to keep only the client credentials, got %+v", p)
+	}
+	if code, _, _ = cli("photos", "like", "abc", "--profile", "work"); code != exitAuth {
+		t.Errorf("expected exit code %d after logging out, got %d", exitAuth, code)
+	}
+}
+
+func TestAuthLoginPaste(t *testing.T) {
+	cli := setup(t, authHandler(t))
+	defer func() { stdin = os.Stdin }()
+
+	// a pasted redirect must carry the state sent
+	stdin = strings.NewReader("http://localhost/callback?code=the-code\n")
+	code, _, stderr := cli("auth", "login", "--client-secret", "secret", "--redirect-uri", oobRedirectURI)
+	if code == exitOK || !strings.Contains(stderr, "state mismatch") {
+		t.Errorf("expected a redirect without the state to be refused, got exit code %d: %s", code, stderr)
+	}
+
+	stdin = strings.NewReader("the-code\n")
+	code, _, stderr = cli("auth", "login", "--client-secret", "secret", "--redirect-uri", oobRedirectURI)
+	if code != exitOK {
+		t.Fatalf("expected exit code %d, got %d: %s", exitOK, code, stderr)
+	}
+	if !strings.Contains(stderr, "/oauth/authorize?") {
+		t.Errorf("expected the authorization link to be printed, got %q", stderr)
+	}
+	conf, _ := loadConfig()
+	if p := conf.Profiles[defaultProfile]; p == nil || p.AccessToken != "the-token" {
+		t.Errorf("expected the token to be stored in the default profile, got %+v", p)
+	}
+}
+
+func TestAuthStatusNotLoggedIn(t *testing.T) {
+	cli := setup(t, authHandler(t))
+
+	code, stdout, stderr := cli("auth", "status", "--json")
+	if code != exitOK {
+		t.Fatalf("expected exit code %d, got %d: %s", exitOK, code, stderr)
+	}
+	if !strings.Contains(stdout, `"logged_in": false`) || !strings.Contains(stdout, `"remaining": 49`) {
+		t.Errorf("expected status with the app's rate limit, got %q", stdout)
+	}
+}
+
+func TestCallbackCode(t *testing.T) {
+	tt := []struct {
+		query string
+		code  string
+		fails bool
+	}{
+		{"code=abc&state=s", "abc", false},
+		{"code=abc", "", true},
+		{"code=abc&state=other", "", true},
+		{"error=access_denied&state=s", "", true},
+		{"state=s", "", true},
+	}
+	for _, tc := range tt {
+		q, _ := url.ParseQuery(tc.query)
+		code, err := callbackCode(q, "s")
+		if (err != nil) != tc.fails || code != tc.code {
+			t.Errorf("%s: expected code %q, failing %v, got %q, %v", tc.query, tc.code, tc.fails, code, err)
+		}
+	}
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"github.com/eddogola/unsplash-go/unsplash"
+	"github.com/eddogola/unsplash-go/unsplash/tui"
+)
+
+var browseCommands = map[string]command{
+	"editorial":   {"", "browse the editorial feed interactively", browseStart((*tui.Browser).Editorial)},
+	"topics":      {"", "browse topics interactively", browseStart((*tui.Browser).Topics)},
+	"collections": {"", "browse collections interactively", browseStart((*tui.Browser).Collections)},
+	"search":      {"<query>...", "browse photo search results interactively", browseSearch},
+}
+
+// browseStart returns a command opening the browser on the list opened by start
+func browseStart(start func(*tui.Browser) error) func(*env, []string) error {
+	return func(e *env, args []string) error {
+		b, _, err := e.browser(args, "", 0)
+		if err != nil {
+			return err
+		}
+		if err := start(b); err != nil {
+			return err
+		}
+		return b.Run()
+	}
+}
+
+func browseSearch(e *env, args []string) error {
+	b, pos, err := e.browser(args, "<query>...", -1)
+	if err != nil {
+		return err
+	}
+	if err := b.Search(joinQuery(pos)); err != nil {
+		return err
+	}
+	return b.Run()
+}
+
+// browser parses the browse flags, then constructs the browser, returning it with the positional arguments.
+// Private actions are enabled if the profile is logged in.
+func (e *env) browser(args []string, usage string, n int) (*tui.Browser, []string, error) {
+	dir := e.fs.String("dir", ".", "directory photos are downloaded to")
+	perPage := e.fs.Int("per-page", 20, "number of results per page")
+	pos, err := e.parse(args, usage, n)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	c, err := e.client(true)
+	private := err == nil
+	if _, ok := err.(errNotLoggedIn); ok {
+		c, err = e.client(false)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	b := tui.New(unsplash.New(c), private)
+	b.DownloadDir, b.PerPage = *dir, *perPage
+	return b, pos, nil
+}
@@ -0,0 +1,413 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"strings"
+
+	"github.com/eddogola/unsplash-go/unsplash/backup"
+	"github.com/eddogola/unsplash-go/unsplash/client"
+	"github.com/eddogola/unsplash-go/unsplash/curate"
+)
+
+var collectionsCommands = map[string]command{
+	"list":    {"", "list a page of all collections", collectionsList},
+	"get":     {"<collection-id>", "get a collection", collectionsGet},
+	"photos":  {"<collection-id>", "list a collection's photos", collectionsPhotos},
+	"related": {"<collection-id>", "list collections related to a collection", collectionsRelated},
+	"search":  {"<query>...", "search collections", collectionsSearch},
+	"create":  {"", "create a collection (private)", collectionsCreate},
+	"update":  {"<collection-id>", "update a collection (private)", collectionsUpdate},
+	"delete":  {"<collection-id>", "delete a collection (private)", collectionsDelete},
+	"add":     {"<collection-id> <photo-id>", "add a photo to a collection (private)", collectionsAdd},
+	"remove":  {"<collection-id> <photo-id>", "remove a photo from a collection (private)", collectionsRemove},
+	"backup":  {"[<collection-id>...]", "back up collections, or a user's collections, to an archive", collectionsBackup},
+	"restore": {"<archive>", "recreate backed up collections in your account (private)", collectionsRestore},
+	"plan":    {"<manifest>", "show the changes that make your collections match a manifest (private)", collectionsPlan},
+	"apply":   {"<manifest>", "make your collections match a manifest (private)", collectionsApply},
+}
+
+func collectionsList(e *env, args []string) error {
+	e.paginate()
+	if _, err := e.parse(args, "", 0); err != nil {
+		return err
+	}
+	u, err := e.unsplash(false)
+	if err != nil {
+		return err
+	}
+	collections, err := u.Collections.All(e.query(nil))
+	if err != nil {
+		return err
+	}
+	return e.print(collections, collectionsTable(collections))
+}
+
+func collectionsGet(e *env, args []string) error {
+	pos, err := e.parse(args, "<collection-id>", 1)
+	if err != nil {
+		return err
+	}
+	u, err := e.unsplash(false)
+	if err != nil {
+		return err
+	}
+	collection, err := u.Collections.Get(pos[0])
+	if err != nil {
+		return err
+	}
+	return e.print(collection, collectionsTable([]client.Collection{*collection}))
+}
+
+func collectionsPhotos(e *env, args []string) error {
+	e.paginate()
+	orientation := e.fs.String("orientation", "", "landscape, portrait or squarish")
+	pos, err := e.parse(args, "<collection-id>", 1)
+	if err != nil {
+		return err
+	}
+	u, err := e.unsplash(false)
+	if err != nil {
+		return err
+	}
+	pics, err := u.Collections.Photos(pos[0], e.query(map[string]string{"orientation": *orientation}))
+	if err != nil {
+		return err
+	}
+	return e.print(pics, photosTable(pics))
+}
+
+func collectionsRelated(e *env, args []string) error {
+	pos, err := e.parse(args, "<collection-id>", 1)
+	if err != nil {
+		return err
+	}
+	u, err := e.unsplash(false)
+	if err != nil {
+		return err
+	}
+	collections, err := u.Collections.Related(pos[0])
+	if err != nil {
+		return err
+	}
+	return e.print(collections, collectionsTable(collections))
+}
+
+func collectionsSearch(e *env, args []string) error {
+	e.paginate()
+	pos, err := e.parse(args, "<query>...", -1)
+	if err != nil {
+		return err
+	}
+	u, err := e.unsplash(false)
+	if err != nil {
+		return err
+	}
+	res, err := u.Collections.Search(joinQuery(pos), e.query(nil))
+	if err != nil {
+		return err
+	}
+	return e.print(res, collectionsTable(res.Results))
+}
+
+// collectionFlags adds flags setting a collection's details, returning a function
+// that collects the flags that were set into the data sent to the API
+func collectionFlags(e *env) func() map[string]string {
+	title := e.fs.String("title", "", "collection title")
+	description := e.fs.String("description", "", "collection description")
+	private := e.fs.Bool("private", false, "make the collection private")
+	return func() map[string]string {
+		data := make(map[string]string)
+		e.fs.Visit(func(f *flag.Flag) {
+			switch f.Name {
+			case "title":
+				data["title"] = *title
+			case "description":
+				data["description"] = *description
+			case "private":
+				data["private"] = fmt.Sprint(*private)
+			}
+		})
+		return data
+	}
+}
+
+func collectionsCreate(e *env, args []string) error {
+	data := collectionFlags(e)
+	if _, err := e.parse(args, "--title <title>", 0); err != nil {
+		return err
+	}
+	fields := data()
+	if fields["title"] == "" {
+		e.fs.Usage()
+		return usageError("--title is required")
+	}
+	u, err := e.unsplash(true)
+	if err != nil {
+		return err
+	}
+	collection, err := u.Collections.Create(fields)
+	if err != nil {
+		return err
+	}
+	return e.print(collection, collectionsTable([]client.Collection{*collection}))
+}
+
+func collectionsUpdate(e *env, args []string) error {
+	data := collectionFlags(e)
+	pos, err := e.parse(args, "<collection-id>", 1)
+	if err != nil {
+		return err
+	}
+	fields := data()
+	if len(fields) == 0 {
+		e.fs.Usage()
+		return usageError("nothing to update, set --title, --description or --private")
+	}
+	u, err := e.unsplash(true)
+	if err != nil {
+		return err
+	}
+	collection, err := u.Collections.Update(pos[0], fields)
+	if err != nil {
+		return err
+	}
+	return e.print(collection, collectionsTable([]client.Collection{*collection}))
+}
+
+func collectionsDelete(e *env, args []string) error {
+	pos, err := e.parse(args, "<collection-id>", 1)
+	if err != nil {
+		return err
+	}
+	u, err := e.unsplash(true)
+	if err != nil {
+		return err
+	}
+	if err := u.Collections.Delete(pos[0]); err != nil {
+		return err
+	}
+	return e.printOK("deleted collection " + pos[0])
+}
+
+func collectionsAdd(e *env, args []string) error {
+	pos, err := e.parse(args, "<collection-id> <photo-id>", 2)
+	if err != nil {
+		return err
+	}
+	u, err := e.unsplash(true)
+	if err != nil {
+		return err
+	}
+	res, err := u.Collections.AddPhoto(pos[0], map[string]string{"collection_id": pos[0], "photo_id": pos[1]})
+	if err != nil {
+		return err
+	}
+	return e.print(res, collectionsTable([]client.Collection{res.Collection}))
+}
+
+func collectionsRemove(e *env, args []string) error {
+	pos, err := e.parse(args, "<collection-id> <photo-id>", 2)
+	if err != nil {
+		return err
+	}
+	u, err := e.unsplash(true)
+	if err != nil {
+		return err
+	}
+	res, err := u.Collections.RemovePhoto(pos[0], map[string]string{"collection_id": pos[0], "photo_id": pos[1]})
+	if err != nil {
+		return err
+	}
+	return e.print(res, collectionsTable([]client.Collection{res.Collection}))
+}
+
+func collectionsBackup(e *env, args []string) error {
+	out := e.fs.String("out", "", "archive to write: a directory, or a `.tar.gz` file")
+	username := e.fs.String("user", "", "back up all of this user's collections")
+	images := e.fs.Bool("images", false, "also download every photo's image file")
+	private := e.fs.Bool("private", false, "use the logged in profile, including private collections")
+	ids, err := e.parse(args, "--out <archive> (--user <username> | <collection-id>...)", anyArgs)
+	if err != nil {
+		return err
+	}
+	if *out == "" || (*username == "") == (len(ids) == 0) {
+		e.fs.Usage()
+		return usageError("set --out, and either --user or collection IDs")
+	}
+	c, err := e.client(*private)
+	if err != nil {
+		return err
+	}
+	ctx := context.Background()
+	exporter := backup.NewExporter(c)
+	exporter.Images = *images
+
+	var collections []backup.ArchivedCollection
+	if *username != "" {
+		collections, err = exporter.UserCollections(ctx, *username)
+		if err != nil {
+			return err
+		}
+	}
+	for _, id := range ids {
+		ac, err := exporter.Collection(ctx, id)
+		if err != nil {
+			return err
+		}
+		collections = append(collections, *ac)
+	}
+	m, failures, err := exporter.Export(ctx, *out, collections)
+	if err != nil {
+		return err
+	}
+	for _, f := range failures {
+		fmt.Fprintf(e.stderr, "warning: %v\n", f)
+	}
+	t := &table{headers: []string{"ID", "TITLE", "PHOTOS", "PRIVATE"}}
+	for _, ac := range m.Collections {
+		t.rows = append(t.rows, []string{ac.Collection.ID, truncate(ac.Collection.Title, 50),
+			fmt.Sprint(len(ac.Photos)), fmt.Sprint(ac.Collection.Private)})
+	}
+	return e.print(m, t)
+}
+
+func collectionsRestore(e *env, args []string) error {
+	dryRun := e.fs.Bool("dry-run", false, "report the changes without making them")
+	username := e.fs.String("user", "", "username of the account restored into, requested from the API if not set")
+	pos, err := e.parse(args, "<archive>", 1)
+	if err != nil {
+		return err
+	}
+	m, err := backup.ReadManifest(pos[0])
+	if err != nil {
+		return err
+	}
+	c, err := e.client(true)
+	if err != nil {
+		return err
+	}
+	r := backup.NewRestorer(c)
+	r.Username, r.DryRun = *username, *dryRun
+	report, err := r.Restore(context.Background(), m)
+	if report != nil {
+		for _, f := range report.Failures {
+			fmt.Fprintf(e.stderr, "warning: %v\n", f)
+		}
+	}
+	if err != nil {
+		return err
+	}
+	t := &table{headers: []string{"SOURCE", "ID", "TITLE", "CREATED", "ADDED", "PRESENT"}}
+	for _, rc := range report.Collections {
+		t.rows = append(t.rows, []string{rc.SourceID, rc.ID, truncate(rc.Title, 50),
+			fmt.Sprint(rc.Created), fmt.Sprint(rc.Added), fmt.Sprint(rc.Present)})
+	}
+	return e.print(report, t)
+}
+
+// planManifest adds the flags shared by plan and apply, then plans the changes making
+// the logged in account's collections match the manifest named in args
+func planManifest(e *env, args []string) (*curate.Planner, *curate.Plan, error) {
+	prune := e.fs.Bool("prune", false, "delete collections that aren't in the manifest")
+	username := e.fs.String("user", "", "username of the account managed, requested from the API if not set")
+	pos, err := e.parse(args, "<manifest>", 1)
+	if err != nil {
+		return nil, nil, err
+	}
+	m, err := curate.Load(pos[0])
+	if err != nil {
+		return nil, nil, err
+	}
+	c, err := e.client(true)
+	if err != nil {
+		return nil, nil, err
+	}
+	planner := curate.NewPlanner(c)
+	planner.Username, planner.Prune = *username, *prune
+	plan, err := planner.Plan(context.Background(), m)
+	if err != nil {
+		return nil, nil, err
+	}
+	return planner, plan, nil
+}
+
+func collectionsPlan(e *env, args []string) error {
+	_, plan, err := planManifest(e, args)
+	if err != nil {
+		return err
+	}
+	if e.format == "table" {
+		_, err := fmt.Fprint(e.stdout, plan)
+		return err
+	}
+	t := &table{headers: []string{"ACTION", "COLLECTION", "ID", "PHOTO", "REORDER"}}
+	for _, a := range plan.Actions {
+		t.rows = append(t.rows, []string{string(a.Type), a.Collection, a.CollectionID, a.PhotoID, fmt.Sprint(a.Reorder)})
+	}
+	return e.print(plan, t)
+}
+
+// appliedAction reports the outcome of an action
+type appliedAction struct {
+	curate.Action
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+func collectionsApply(e *env, args []string) error {
+	yes := e.fs.Bool("yes", false, "apply the changes without asking for confirmation")
+	planner, plan, err := planManifest(e, args)
+	if err != nil {
+		return err
+	}
+	if e.format == "table" {
+		fmt.Fprint(e.stdout, plan)
+	}
+	if plan.Empty() {
+		if e.format == "table" {
+			return nil
+		}
+		return e.print([]appliedAction{}, &table{headers: []string{"STATUS", "ACTION", "ERROR"}})
+	}
+	if !*yes {
+		fmt.Fprint(e.stderr, "Apply these changes? [y/N] ")
+		line, _ := bufio.NewReader(stdin).ReadString('\n')
+		if answer := strings.ToLower(strings.TrimSpace(line)); answer != "y" && answer != "yes" {
+			return errors.New("apply cancelled, no changes were made")
+		}
+	}
+
+	res, err := planner.Apply(context.Background(), plan)
+	if err != nil {
+		return fmt.Errorf("apply stopped after %d changes: %w", len(res.Applied), err)
+	}
+	var outcomes []appliedAction
+	for _, a := range res.Applied {
+		outcomes = append(outcomes, appliedAction{a, "applied", ""})
+	}
+	for _, f := range res.Failed {
+		fmt.Fprintf(e.stderr, "warning: %v\n", f)
+		outcomes = append(outcomes, appliedAction{f.Action, "failed", f.Err.Error()})
+	}
+	for _, a := range res.Skipped {
+		outcomes = append(outcomes, appliedAction{a, "skipped", "collection not created"})
+	}
+	if e.format == "table" {
+		fmt.Fprintln(e.stdout)
+	}
+	t := &table{headers: []string{"STATUS", "ACTION", "ERROR"}}
+	for _, o := range outcomes {
+		t.rows = append(t.rows, []string{o.Status, o.Action.String(), o.Error})
+	}
+	if err := e.print(outcomes, t); err != nil {
+		return err
+	}
+	if !res.OK() {
+		return fmt.Errorf("%d of %d changes failed and %d were skipped, run apply again once the failures are fixed", len(res.Failed), len(plan.Actions), len(res.Skipped))
+	}
+	return nil
+}
@@ -0,0 +1,120 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// defaultProfile is the profile used when none is picked with --profile or UNSPLASH_PROFILE
+const defaultProfile = "default"
+
+// config defines the contents of the config file, which holds credentials
+// for one or more named profiles
+type config struct {
+	Profiles map[string]*profile `json:"profiles"`
+}
+
+// profile holds an application's credentials, and the access token granted by a user on login
+type profile struct {
+	ClientID     string    `json:"client_id"`
+	ClientSecret string    `json:"client_secret,omitempty"`
+	AccessToken  string    `json:"access_token,omitempty"`
+	RefreshToken string    `json:"refresh_token,omitempty"`
+	TokenType    string    `json:"token_type,omitempty"`
+	Expiry       time.Time `json:"expiry,omitempty"`
+	Scopes       []string  `json:"scopes,omitempty"`
+	Username     string    `json:"username,omitempty"`
+	CreatedAt    time.Time `json:"created_at,omitempty"`
+}
+
+// configPath returns the path of the config file, set using the UNSPLASH_CONFIG
+// environment variable, or `unsplash/config.json` in the user's config directory
+func configPath() (string, error) {
+	if path := os.Getenv("UNSPLASH_CONFIG"); path != "" {
+		return path, nil
+	}
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "unsplash", "config.json"), nil
+}
+
+// loadConfig reads the config file, returning an empty config if there's none
+func loadConfig() (*config, error) {
+	conf := &config{Profiles: make(map[string]*profile)}
+	path, err := configPath()
+	if err != nil {
+		return conf, nil
+	}
+	data, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return conf, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, conf); err != nil {
+		return nil, fmt.Errorf("error parsing config file %s: %v", path, err)
+	}
+	if conf.Profiles == nil {
+		conf.Profiles = make(map[string]*profile)
+	}
+	return conf, nil
+}
+
+// save writes the config file. The file holds access tokens, so it's only readable by the user.
+func (conf *config) save() error {
+	path, err := configPath()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(conf, "", "  ")
+	if err != nil {
+		return err
+	}
+	// write to a temporary file first so a failed write does not lose existing profiles
+	tmp, err := ioutil.TempFile(filepath.Dir(path), ".config-*.json")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if err := tmp.Chmod(0600); err != nil {
+		tmp.Close()
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// profile returns the named profile, or an empty profile if there's none
+func (conf *config) profile(name string) *profile {
+	if p, ok := conf.Profiles[name]; ok {
+		return p
+	}
+	return &profile{}
+}
+
+// profileNames returns the names of all profiles, sorted
+func (conf *config) profileNames() []string {
+	names := make([]string, 0, len(conf.Profiles))
+	for name := range conf.Profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
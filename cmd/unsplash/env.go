@@ -0,0 +1,205 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/eddogola/unsplash-go/unsplash"
+	"github.com/eddogola/unsplash-go/unsplash/client"
+	"golang.org/x/oauth2"
+)
+
+// httpClient is the http client used in API requests, nil for http.DefaultClient.
+// It's replaced in tests.
+var httpClient *http.Client
+
+// env holds a subcommand's flags and output streams
+type env struct {
+	name   string
+	stdout io.Writer
+	stderr io.Writer
+	fs     *flag.FlagSet
+
+	format   string
+	jsonOut  bool
+	clientID string
+	profile  string
+	page     int
+	perPage  int
+	params   paramsFlag
+}
+
+// paramsFlag collects repeated `-p key=value` flags into query parameters
+type paramsFlag client.QueryParams
+
+func (p paramsFlag) String() string {
+	var pairs []string
+	for k, v := range p {
+		pairs = append(pairs, k+"="+v)
+	}
+	return strings.Join(pairs, ",")
+}
+
+func (p paramsFlag) Set(val string) error {
+	parts := strings.SplitN(val, "=", 2)
+	if len(parts) != 2 || parts[0] == "" {
+		return fmt.Errorf("query parameter must be in the form key=value, got %q", val)
+	}
+	p[parts[0]] = parts[1]
+	return nil
+}
+
+// usageError is returned when a command is called with the wrong arguments
+type usageError string
+
+func (e usageError) Error() string {
+	return string(e)
+}
+
+func newEnv(name string, stdout, stderr io.Writer) *env {
+	e := &env{name: name, stdout: stdout, stderr: stderr, params: make(paramsFlag)}
+	e.fs = flag.NewFlagSet("unsplash "+name, flag.ContinueOnError)
+	e.fs.SetOutput(stderr)
+	e.fs.StringVar(&e.format, "format", "table", "output `format`: table, csv or json")
+	e.fs.BoolVar(&e.jsonOut, "json", false, "print results as JSON, same as --format json")
+	e.fs.StringVar(&e.clientID, "client-id", "", "Unsplash application client ID")
+	e.fs.StringVar(&e.profile, "profile", "", "`name` of the stored profile to use, defaults to $UNSPLASH_PROFILE or \"default\"")
+	e.fs.Var(e.params, "p", "extra query parameter as `key=value`, can be repeated")
+	return e
+}
+
+// paginate adds --page and --per-page flags
+func (e *env) paginate() {
+	e.fs.IntVar(&e.page, "page", 0, "page number to retrieve")
+	e.fs.IntVar(&e.perPage, "per-page", 0, "number of items per page")
+}
+
+// anyArgs is passed to parse to accept any number of positional arguments
+const anyArgs = math.MinInt32
+
+// parse parses flags, which may appear before or after positional arguments.
+// Returns the positional arguments, expecting exactly n of them, at least -n if n is negative,
+// or any number if n is anyArgs.
+func (e *env) parse(args []string, usage string, n int) ([]string, error) {
+	e.fs.Usage = func() {
+		fmt.Fprintf(e.stderr, "Usage: unsplash %s [flags] %s\n\nFlags:\n", e.name, usage)
+		e.fs.PrintDefaults()
+	}
+	var positional []string
+	for {
+		if err := e.fs.Parse(args); err != nil {
+			if err == flag.ErrHelp {
+				return nil, err
+			}
+			return nil, usageError(err.Error())
+		}
+		if e.fs.NArg() == 0 {
+			break
+		}
+		positional = append(positional, e.fs.Arg(0))
+		args = e.fs.Args()[1:]
+	}
+	if n != anyArgs && ((n >= 0 && len(positional) != n) || (n < 0 && len(positional) < -n)) {
+		e.fs.Usage()
+		return nil, usageError(fmt.Sprintf("wrong number of arguments for %s", e.name))
+	}
+	if e.jsonOut {
+		e.format = "json"
+	}
+	switch e.format {
+	case "table", "csv", "json":
+	default:
+		return nil, usageError(fmt.Sprintf("unknown output format %q", e.format))
+	}
+	return positional, nil
+}
+
+// query returns the query parameters set using flags, along with extra parameters that are not empty
+func (e *env) query(extra map[string]string) client.QueryParams {
+	qp := make(client.QueryParams)
+	for k, v := range e.params {
+		qp[k] = v
+	}
+	if e.page > 0 {
+		qp["page"] = fmt.Sprint(e.page)
+	}
+	if e.perPage > 0 {
+		qp["per_page"] = fmt.Sprint(e.perPage)
+	}
+	for k, v := range extra {
+		if v != "" {
+			qp[k] = v
+		}
+	}
+	return qp
+}
+
+// profileName returns the name of the profile picked using --profile or UNSPLASH_PROFILE
+func (e *env) profileName() string {
+	return firstNonEmpty(e.profile, os.Getenv("UNSPLASH_PROFILE"), defaultProfile)
+}
+
+// client constructs the client used by the command, with credentials from flags,
+// environment variables or the picked profile, in that order.
+// If private is true, the client is authorised for private actions using the profile's access token.
+func (e *env) client(private bool) (*client.Client, error) {
+	conf, err := loadConfig()
+	if err != nil {
+		return nil, err
+	}
+	p := conf.profile(e.profileName())
+	clientID := firstNonEmpty(e.clientID, os.Getenv("UNSPLASH_CLIENT_ID"), os.Getenv("CLIENT_ID"), p.ClientID)
+	if clientID == "" {
+		return nil, errNoClientID
+	}
+	if !private {
+		return client.New(clientID, httpClient, client.NewConfig()), nil
+	}
+
+	token := firstNonEmpty(os.Getenv("UNSPLASH_ACCESS_TOKEN"), p.AccessToken)
+	if token == "" {
+		return nil, errNotLoggedIn(e.profileName())
+	}
+	scopes := p.Scopes
+	if env := os.Getenv("UNSPLASH_SCOPES"); env != "" {
+		scopes = strings.Split(env, ",")
+	}
+	if len(scopes) == 0 {
+		// scopes unknown, leave it to the API to reject actions the token is not allowed
+		scopes = allScopes
+	}
+	c := client.NewPrivateClient(clientID, &oauth2.Token{AccessToken: token, TokenType: "Bearer"},
+		client.NewAuthScopes(scopes...), client.NewConfig())
+	if httpClient != nil {
+		c.HTTPClient.Transport.(*oauth2.Transport).Base = httpClient.Transport
+	}
+	return c, nil
+}
+
+// unsplash constructs the Unsplash object used by the command, using the client returned by client
+func (e *env) unsplash(private bool) (*unsplash.Unsplash, error) {
+	c, err := e.client(private)
+	if err != nil {
+		return nil, err
+	}
+	return unsplash.New(c), nil
+}
+
+var allScopes = []string{
+	client.ReadUserScope, client.WriteUserScope, client.ReadPhotosScope, client.WritePhotosScope,
+	client.WriteLikesScope, client.WriteFollowersScope, client.ReadCollectionsScope, client.WriteCollectionsScope,
+}
+
+func firstNonEmpty(vals ...string) string {
+	for _, v := range vals {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
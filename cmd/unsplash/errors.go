@@ -0,0 +1,76 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/eddogola/unsplash-go/unsplash/client"
+)
+
+var errNoClientID = errors.New("no client ID: use --client-id, set UNSPLASH_CLIENT_ID, or run `unsplash auth login`")
+
+// errNotLoggedIn is returned when a private command is run without an access token
+type errNotLoggedIn string
+
+func (e errNotLoggedIn) Error() string {
+	return fmt.Sprintf("profile %q is not logged in: run `unsplash auth login --profile %s` or set UNSPLASH_ACCESS_TOKEN", string(e), string(e))
+}
+
+// handleErr prints err, returning the exit code matching it
+func handleErr(stderr io.Writer, err error) int {
+	if errors.Is(err, flag.ErrHelp) {
+		return exitOK
+	}
+	fmt.Fprintf(stderr, "unsplash: %v\n", err)
+	return exitCode(err)
+}
+
+// exitCode returns the exit code matching err, or the error it wraps
+func exitCode(err error) int {
+	var (
+		usage       usageError
+		scope       client.ErrRequiredScopeAbsent
+		notLoggedIn errNotLoggedIn
+		status      client.ErrStatusCode
+	)
+	switch {
+	case errors.As(err, &usage):
+		return exitUsage
+	case errors.As(err, &scope), errors.As(err, &notLoggedIn),
+		errors.Is(err, client.ErrClientNotPrivate), errors.Is(err, errNoClientID):
+		return exitAuth
+	case errors.As(err, &status):
+		return statusExitCode(status)
+	}
+	return exitError
+}
+
+func statusExitCode(e client.ErrStatusCode) int {
+	switch code := e.StatusCode(); {
+	case code == http.StatusTooManyRequests:
+		return exitRateLimited
+	case code == http.StatusForbidden && isRateLimit(e.Reasons()):
+		// Unsplash responds with 403 when the hourly rate limit is exceeded
+		return exitRateLimited
+	case code == http.StatusUnauthorized || code == http.StatusForbidden:
+		return exitAuth
+	case code == http.StatusNotFound:
+		return exitNotFound
+	case code >= 500:
+		return exitServerError
+	}
+	return exitError
+}
+
+func isRateLimit(reasons []string) bool {
+	for _, r := range reasons {
+		if strings.Contains(strings.ToLower(r), "rate limit") {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,139 @@
+// Command unsplash is a command-line client for the Unsplash API.
+//
+// Usage:
+//
+//	unsplash <command> <subcommand> [flags] [arguments]
+//
+// Commands mirror the services of package unsplash:
+//
+//	photos      list|get|random|stats|search|like|unlike
+//	users       get|photos|likes|collections|stats|search
+//	collections list|get|photos|related|search|create|update|delete|add|remove|backup|restore|plan|apply
+//	topics      list|get|photos
+//	stats       total|month
+//	auth        login|logout|status
+//	browse      editorial|search|topics|collections
+//
+// `unsplash auth login` authorizes the CLI to act on the user's behalf, storing the access token
+// in a named profile in the config file, `unsplash/config.json` in the user's config directory.
+// Commands use the profile picked with --profile or UNSPLASH_PROFILE, "default" otherwise.
+// The client ID may also be set with the --client-id flag, or the UNSPLASH_CLIENT_ID or CLIENT_ID
+// environment variables, and the access token with the UNSPLASH_ACCESS_TOKEN environment variable.
+//
+// Results are printed as a table by default; use --json or --format csv for other formats.
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+)
+
+// Exit codes
+const (
+	exitOK = iota
+	exitError
+	exitUsage
+	exitAuth
+	exitNotFound
+	exitRateLimited
+	exitServerError
+)
+
+// command defines a subcommand, e.g. `photos get`
+type command struct {
+	usage string // arguments, e.g. "<photo-id>"
+	help  string
+	run   func(env *env, args []string) error
+}
+
+var commands = map[string]map[string]command{
+	"photos":      photosCommands,
+	"users":       usersCommands,
+	"collections": collectionsCommands,
+	"topics":      topicsCommands,
+	"stats":       statsCommands,
+	"auth":        authCommands,
+	"browse":      browseCommands,
+}
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+// run runs the command in args, returning the exit code
+func run(args []string, stdout, stderr io.Writer) int {
+	if len(args) == 0 || isHelp(args[0]) {
+		printUsage(stdout)
+		return exitOK
+	}
+	group, ok := commands[args[0]]
+	if !ok {
+		fmt.Fprintf(stderr, "unknown command %q\n\n", args[0])
+		printUsage(stderr)
+		return exitUsage
+	}
+	if len(args) < 2 || isHelp(args[1]) {
+		printGroupUsage(stdout, args[0])
+		return exitOK
+	}
+	cmd, ok := group[args[1]]
+	if !ok {
+		fmt.Fprintf(stderr, "unknown command %q\n\n", args[0]+" "+args[1])
+		printGroupUsage(stderr, args[0])
+		return exitUsage
+	}
+
+	e := newEnv(args[0]+" "+args[1], stdout, stderr)
+	if err := cmd.run(e, args[2:]); err != nil {
+		return handleErr(stderr, err)
+	}
+	return exitOK
+}
+
+func isHelp(arg string) bool {
+	return arg == "help" || arg == "-h" || arg == "--help"
+}
+
+func printUsage(w io.Writer) {
+	fmt.Fprintln(w, "Usage: unsplash <command> <subcommand> [flags] [arguments]")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Commands:")
+	for _, name := range sortedKeys(commands) {
+		var subs []string
+		for sub := range commands[name] {
+			subs = append(subs, sub)
+		}
+		sort.Strings(subs)
+		fmt.Fprintf(w, "  %-12s %s\n", name, strings.Join(subs, "|"))
+	}
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Run `unsplash <command> help` for details on a command.")
+}
+
+func printGroupUsage(w io.Writer, name string) {
+	fmt.Fprintf(w, "Usage: unsplash %s <subcommand> [flags] [arguments]\n\n", name)
+	fmt.Fprintln(w, "Subcommands:")
+	group := commands[name]
+	var subs []string
+	for sub := range group {
+		subs = append(subs, sub)
+	}
+	sort.Strings(subs)
+	for _, sub := range subs {
+		fmt.Fprintf(w, "  %-30s %s\n", sub+" "+group[sub].usage, group[sub].help)
+	}
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Run `unsplash "+name+" <subcommand> -h` for the subcommand's flags.")
+}
+
+func sortedKeys(m map[string]map[string]command) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,310 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"strings"
+	"testing"
+)
+
+// redirectTransport sends every request to the test server
+type redirectTransport struct {
+	target *url.URL
+}
+
+func (t redirectTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	req.URL.Scheme = t.target.Scheme
+	req.URL.Host = t.target.Host
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+// setup points the command's requests at handler, returning a function running the command
+func setup(t *testing.T, handler http.HandlerFunc) func(args ...string) (int, string, string) {
+	ts := httptest.NewServer(handler)
+	target, _ := url.Parse(ts.URL)
+	httpClient = &http.Client{Transport: redirectTransport{target}}
+	t.Setenv("UNSPLASH_CONFIG", t.TempDir()+"/config.json")
+	t.Setenv("UNSPLASH_CLIENT_ID", "test-client-id")
+	t.Setenv("UNSPLASH_ACCESS_TOKEN", "")
+	t.Cleanup(func() {
+		ts.Close()
+		httpClient = nil
+	})
+	return func(args ...string) (int, string, string) {
+		var stdout, stderr bytes.Buffer
+		code := run(args, &stdout, &stderr)
+		return code, stdout.String(), stderr.String()
+	}
+}
+
+func TestPhotosGet(t *testing.T) {
+	var gotPath, gotAuth string
+	cli := setup(t, func(w http.ResponseWriter, r *http.Request) {
+		gotPath, gotAuth = r.URL.Path, r.Header.Get("Authorization")
+		w.Write([]byte(`{"id": "abc", "width": 100, "height": 50, "likes": 3, "user": {"username": "jane"}}`))
+	})
+
+	code, stdout, stderr := cli("photos", "get", "abc")
+	if code != exitOK {
+		t.Fatalf("expected exit code %d, got %d: %s", exitOK, code, stderr)
+	}
+	if gotPath != "/photos/abc" || gotAuth != "Client-ID test-client-id" {
+		t.Errorf("unexpected request: path %q, authorization %q", gotPath, gotAuth)
+	}
+	if !strings.Contains(stdout, "abc") || !strings.Contains(stdout, "jane") {
+		t.Errorf("expected table listing the photo, got %q", stdout)
+	}
+
+	code, stdout, _ = cli("photos", "get", "--json", "abc")
+	var pic map[string]interface{}
+	if code != exitOK || json.Unmarshal([]byte(stdout), &pic) != nil || pic["id"] != "abc" {
+		t.Errorf("expected JSON output of the photo, got %q", stdout)
+	}
+}
+
+func TestSearchQueryParams(t *testing.T) {
+	var query url.Values
+	cli := setup(t, func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.Query()
+		w.Write([]byte(`{"total": 1, "total_pages": 1, "results": [{"id": "abc"}]}`))
+	})
+
+	code, stdout, stderr := cli("photos", "search", "misty", "--color", "teal", "forest", "--per-page", "5", "-p", "lang=fr", "--format", "csv")
+	if code != exitOK {
+		t.Fatalf("expected exit code %d, got %d: %s", exitOK, code, stderr)
+	}
+	expected := map[string]string{"query": "misty forest", "color": "teal", "per_page": "5", "lang": "fr"}
+	for k, v := range expected {
+		if query.Get(k) != v {
+			t.Errorf("expected %s=%q, got %q", k, v, query.Get(k))
+		}
+	}
+	if query.Get("orientation") != "" {
+		t.Errorf("expected unset flags to be left out, got orientation=%q", query.Get("orientation"))
+	}
+	if !strings.HasPrefix(stdout, "ID,WIDTH") {
+		t.Errorf("expected csv output, got %q", stdout)
+	}
+}
+
+func TestExitCodes(t *testing.T) {
+	status := http.StatusNotFound
+	cli := setup(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(status)
+		w.Write([]byte(`{"errors": ["Rate Limit Exceeded"]}`))
+	})
+
+	tt := []struct {
+		name   string
+		status int
+		args   []string
+		code   int
+	}{
+		{"not found", http.StatusNotFound, []string{"photos", "get", "abc"}, exitNotFound},
+		{"rate limited", http.StatusForbidden, []string{"photos", "get", "abc"}, exitRateLimited},
+		{"server error", http.StatusBadGateway, []string{"users", "get", "jane"}, exitServerError},
+		{"missing argument", http.StatusOK, []string{"photos", "get"}, exitUsage},
+		{"unknown command", http.StatusOK, []string{"albums", "list"}, exitUsage},
+		{"private without token", http.StatusOK, []string{"photos", "like", "abc"}, exitAuth},
+		{"help", http.StatusOK, []string{"photos", "get", "-h"}, exitOK},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			status = tc.status
+			if code, _, stderr := cli(tc.args...); code != tc.code {
+				t.Errorf("expected exit code %d, got %d: %s", tc.code, code, stderr)
+			}
+		})
+	}
+}
+
+func TestWrappedExitCodes(t *testing.T) {
+	tt := []struct {
+		err  error
+		code int
+	}{
+		{fmt.Errorf("apply stopped: %w", usageError("wrong arguments")), exitUsage},
+		{fmt.Errorf("error exchanging authorization code: %w", errNoClientID), exitAuth},
+		{fmt.Errorf("listing likes: %w", errNotLoggedIn("work")), exitAuth},
+		{fmt.Errorf("liking photo: %v", errNotLoggedIn("work")), exitError},
+	}
+	for _, tc := range tt {
+		if code := exitCode(tc.err); code != tc.code {
+			t.Errorf("%v: expected exit code %d, got %d", tc.err, tc.code, code)
+		}
+	}
+}
+
+func TestMissingClientID(t *testing.T) {
+	cli := setup(t, func(w http.ResponseWriter, r *http.Request) {
+		t.Error("unexpected request")
+	})
+	t.Setenv("UNSPLASH_CLIENT_ID", "")
+	t.Setenv("CLIENT_ID", "")
+
+	if code, _, _ := cli("stats", "total"); code != exitAuth {
+		t.Errorf("expected exit code %d, got %d", exitAuth, code)
+	}
+}
+
+func TestPhotosPreview(t *testing.T) {
+	cli := setup(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"id": "abc", "width": 400, "height": 200, "color": "#336699", "urls": {"thumb": "http://127.0.0.1:1/thumb"}}`))
+	})
+
+	code, stdout, stderr := cli("photos", "get", "abc", "--preview", "--preview-width", "10", "--protocol", "halfblocks", "--offline")
+	if code != exitOK {
+		t.Fatalf("expected exit code %d, got %d: %s", exitOK, code, stderr)
+	}
+	if strings.Count(stdout, "▀") != 30 || !strings.Contains(stdout, "\x1b[38;2;51;102;153m") {
+		t.Errorf("expected a 10 by 3 preview in the photo's color, got %q", stdout)
+	}
+
+	if code, stdout, _ = cli("photos", "get", "abc", "--preview", "--json"); code != exitOK || strings.Contains(stdout, "▀") {
+		t.Errorf("expected no preview with JSON output, got %q", stdout)
+	}
+	if code, _, _ = cli("photos", "get", "abc", "--preview", "--protocol", "ascii"); code != exitUsage {
+		t.Errorf("expected exit code %d for an unknown protocol, got %d", exitUsage, code)
+	}
+	for _, width := range []string{"0", "-3"} {
+		if code, _, stderr := cli("photos", "get", "abc", "--preview", "--preview-width", width); code != exitUsage {
+			t.Errorf("expected exit code %d for preview width %s, got %d: %s", exitUsage, width, code, stderr)
+		}
+	}
+}
+
+func TestCollectionsBackup(t *testing.T) {
+	cli := setup(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/collections/c1":
+			w.Write([]byte(`{"id": "c1", "title": "Forests"}`))
+		case "/collections/c1/photos":
+			w.Write([]byte(`[{"id": "a"}, {"id": "b"}]`))
+		default:
+			http.NotFound(w, r)
+		}
+	})
+	out := t.TempDir() + "/backup.tar.gz"
+
+	code, stdout, stderr := cli("collections", "backup", "c1", "--out", out)
+	if code != exitOK {
+		t.Fatalf("expected exit code %d, got %d: %s", exitOK, code, stderr)
+	}
+	if !strings.Contains(stdout, "Forests") {
+		t.Errorf("expected the backed up collection to be listed, got %q", stdout)
+	}
+	if code, _, _ = cli("collections", "backup", "--out", out); code != exitUsage {
+		t.Errorf("expected exit code %d without collections to back up, got %d", exitUsage, code)
+	}
+	if code, _, _ = cli("collections", "restore", out); code != exitAuth {
+		t.Errorf("expected exit code %d restoring without logging in, got %d", exitAuth, code)
+	}
+}
+
+func TestCollectionsPlanAndApply(t *testing.T) {
+	var added []string
+	cli := setup(t, func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/users/jane/collections":
+			w.Write([]byte(`[{"id": "c1", "title": "Forests"}]`))
+		case r.URL.Path == "/collections/c1/photos":
+			w.Write([]byte(`[{"id": "a"}]`))
+		case r.URL.Path == "/collections/c1/add" && r.Method == http.MethodPost:
+			var data map[string]string
+			json.NewDecoder(r.Body).Decode(&data)
+			added = append(added, data["photo_id"])
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte(`{}`))
+		default:
+			http.NotFound(w, r)
+		}
+	})
+	t.Setenv("UNSPLASH_ACCESS_TOKEN", "test-token")
+	manifest := t.TempDir() + "/collections.yaml"
+	ioutil.WriteFile(manifest, []byte("collections:\n  - title: Forests\n    photos: [b, a]\n"), 0644)
+
+	code, stdout, stderr := cli("collections", "plan", manifest, "--user", "jane")
+	if code != exitOK {
+		t.Fatalf("expected exit code %d, got %d: %s", exitOK, code, stderr)
+	}
+	if !strings.Contains(stdout, "+ add photo b") || !strings.Contains(stdout, "1 photos to add") {
+		t.Errorf("expected the plan to add a photo, got %q", stdout)
+	}
+
+	stdin = strings.NewReader("n\n")
+	defer func() { stdin = os.Stdin }()
+	if code, _, _ = cli("collections", "apply", manifest, "--user", "jane"); code != exitError || len(added) != 0 {
+		t.Errorf("expected declining to make no changes, got exit code %d and %v added", code, added)
+	}
+
+	code, stdout, stderr = cli("collections", "apply", manifest, "--user", "jane", "--yes")
+	if code != exitOK {
+		t.Fatalf("expected exit code %d, got %d: %s", exitOK, code, stderr)
+	}
+	if len(added) != 1 || added[0] != "b" || !strings.Contains(stdout, "applied") {
+		t.Errorf("expected photo b to be added, got %v and output %q", added, stdout)
+	}
+
+	// the API's status code is kept through the planner's error wrapping it
+	code, _, stderr = cli("collections", "plan", manifest)
+	if code != exitNotFound || !strings.Contains(stderr, "error getting the account") {
+		t.Errorf("expected exit code %d, got %d: %s", exitNotFound, code, stderr)
+	}
+}
+
+func TestCollectionsWrite(t *testing.T) {
+	var requests []string
+	cli := setup(t, func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.URL.Path)
+		switch {
+		case r.Method == http.MethodPut:
+			w.Write([]byte(`{"id": "123", "title": "Renamed"}`))
+		case r.URL.Path == "/collections/123/remove":
+			w.Write([]byte(`{"photo": {"id": "abc"}, "collection": {"id": "123", "title": "Renamed"}}`))
+		default:
+			w.WriteHeader(http.StatusNoContent)
+		}
+	})
+	t.Setenv("UNSPLASH_ACCESS_TOKEN", "test-token")
+
+	tt := [][]string{
+		{"collections", "update", "123", "--title", "Renamed"},
+		{"collections", "remove", "123", "abc"},
+		{"collections", "delete", "123"},
+	}
+	for _, args := range tt {
+		if code, _, stderr := cli(args...); code != exitOK {
+			t.Errorf("%v: expected exit code %d, got %d: %s", args, exitOK, code, stderr)
+		}
+	}
+	expected := []string{"PUT /collections/123", "DELETE /collections/123/remove", "DELETE /collections/123"}
+	if strings.Join(requests, ", ") != strings.Join(expected, ", ") {
+		t.Errorf("expected requests %v, got %v", expected, requests)
+	}
+}
+
+func TestTruncate(t *testing.T) {
+	tt := []struct {
+		s        string
+		n        int
+		expected string
+	}{
+		{"forest", 10, "forest"},
+		{"forest", 4, "for…"},
+		{"forêt", 1, "…"},
+		{"forest", 0, ""},
+		{"forest", -1, ""},
+	}
+	for _, tc := range tt {
+		if got := truncate(tc.s, tc.n); got != tc.expected {
+			t.Errorf("truncate(%q, %d): expected %q, got %q", tc.s, tc.n, tc.expected, got)
+		}
+	}
+}
@@ -0,0 +1,138 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"text/tabwriter"
+
+	"github.com/eddogola/unsplash-go/unsplash/client"
+)
+
+// table defines results in rows, for table and csv output
+type table struct {
+	headers []string
+	rows    [][]string
+}
+
+// print prints v in the env's format, using t for table and csv output
+func (e *env) print(v interface{}, t *table) error {
+	switch e.format {
+	case "json":
+		enc := json.NewEncoder(e.stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(v)
+	case "csv":
+		w := csv.NewWriter(e.stdout)
+		if err := w.Write(t.headers); err != nil {
+			return err
+		}
+		if err := w.WriteAll(t.rows); err != nil {
+			return err
+		}
+		return w.Error()
+	}
+	w := tabwriter.NewWriter(e.stdout, 0, 4, 2, ' ', 0)
+	for i, h := range t.headers {
+		if i > 0 {
+			fmt.Fprint(w, "\t")
+		}
+		fmt.Fprint(w, h)
+	}
+	fmt.Fprintln(w)
+	for _, row := range t.rows {
+		for i, cell := range row {
+			if i > 0 {
+				fmt.Fprint(w, "\t")
+			}
+			fmt.Fprint(w, cell)
+		}
+		fmt.Fprintln(w)
+	}
+	return w.Flush()
+}
+
+// printOK prints a confirmation message, or a JSON object with the message
+func (e *env) printOK(msg string) error {
+	return e.print(map[string]string{"result": msg}, &table{[]string{"RESULT"}, [][]string{{msg}}})
+}
+
+func photosTable(pics []client.Photo) *table {
+	t := &table{headers: []string{"ID", "WIDTH", "HEIGHT", "LIKES", "PHOTOGRAPHER", "DESCRIPTION", "URL"}}
+	for _, p := range pics {
+		desc := p.Description
+		if desc == "" {
+			desc = p.AltDescription
+		}
+		t.rows = append(t.rows, []string{
+			p.ID, fmt.Sprint(p.Width), fmt.Sprint(p.Height), fmt.Sprint(p.Likes),
+			p.User.Username, truncate(desc, 50), p.Links.HTML,
+		})
+	}
+	return t
+}
+
+func usersTable(users []client.User) *table {
+	t := &table{headers: []string{"USERNAME", "NAME", "PHOTOS", "LIKES", "COLLECTIONS", "LOCATION", "URL"}}
+	for _, u := range users {
+		t.rows = append(t.rows, []string{
+			u.Username, u.Name, fmt.Sprint(u.TotalPhotos), fmt.Sprint(u.TotalLikes),
+			fmt.Sprint(u.TotalCollections), u.Location, u.Links.HTML,
+		})
+	}
+	return t
+}
+
+func collectionsTable(collections []client.Collection) *table {
+	t := &table{headers: []string{"ID", "TITLE", "PHOTOS", "PRIVATE", "OWNER", "URL"}}
+	for _, c := range collections {
+		t.rows = append(t.rows, []string{
+			c.ID, truncate(c.Title, 50), fmt.Sprint(c.TotalPhotos), fmt.Sprint(c.Private), c.User.Username, c.Links.HTML,
+		})
+	}
+	return t
+}
+
+func topicsTable(topics []client.Topic) *table {
+	t := &table{headers: []string{"ID", "SLUG", "TITLE", "PHOTOS", "STATUS", "URL"}}
+	for _, tp := range topics {
+		t.rows = append(t.rows, []string{
+			tp.ID, tp.Slug, tp.Title, fmt.Sprint(tp.TotalPhotos), tp.Status, tp.Links.HTML,
+		})
+	}
+	return t
+}
+
+// statsTable lists the totals of download, view and like stats
+func statsTable(stats map[string]client.Stats) *table {
+	t := &table{headers: []string{"STAT", "TOTAL", "CHANGE"}}
+	for _, name := range []string{"downloads", "views", "likes"} {
+		s, ok := stats[name]
+		if !ok {
+			continue
+		}
+		t.rows = append(t.rows, []string{name, fmt.Sprint(s.Total), fmt.Sprint(s.Historical.Change)})
+	}
+	return t
+}
+
+// fieldsTable lists name and value pairs
+func fieldsTable(fields ...interface{}) *table {
+	t := &table{headers: []string{"FIELD", "VALUE"}}
+	for i := 0; i+1 < len(fields); i += 2 {
+		t.rows = append(t.rows, []string{fmt.Sprint(fields[i]), fmt.Sprint(fields[i+1])})
+	}
+	return t
+}
+
+// truncate shortens s to n runes, ending it with an ellipsis if it was cut
+func truncate(s string, n int) string {
+	r := []rune(s)
+	if len(r) <= n {
+		return s
+	}
+	if n < 1 {
+		return ""
+	}
+	return string(r[:n-1]) + "…"
+}
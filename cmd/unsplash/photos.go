@@ -0,0 +1,171 @@
+package main
+
+import (
+	"github.com/eddogola/unsplash-go/unsplash/client"
+)
+
+var photosCommands = map[string]command{
+	"list":   {"", "list a page of all photos", photosList},
+	"get":    {"<photo-id>", "get a photo", photosGet},
+	"random": {"", "get random photos", photosRandom},
+	"stats":  {"<photo-id>", "get a photo's statistics", photosStats},
+	"search": {"<query>...", "search photos", photosSearch},
+	"like":   {"<photo-id>", "like a photo (private)", photosLike},
+	"unlike": {"<photo-id>", "remove a like from a photo (private)", photosUnlike},
+}
+
+func photosList(e *env, args []string) error {
+	e.paginate()
+	orderBy := e.fs.String("order-by", "", "sort order: latest, oldest or popular")
+	if _, err := e.parse(args, "", 0); err != nil {
+		return err
+	}
+	u, err := e.unsplash(false)
+	if err != nil {
+		return err
+	}
+	pics, err := u.Photos.All(e.query(map[string]string{"order_by": *orderBy}))
+	if err != nil {
+		return err
+	}
+	return e.print(pics, photosTable(pics))
+}
+
+func photosGet(e *env, args []string) error {
+	drawPreviews := previewFlags(e)
+	pos, err := e.parse(args, "<photo-id>", 1)
+	if err != nil {
+		return err
+	}
+	u, err := e.unsplash(false)
+	if err != nil {
+		return err
+	}
+	pic, err := u.Photos.Get(pos[0])
+	if err != nil {
+		return err
+	}
+	if err := e.print(pic, photosTable([]client.Photo{*pic})); err != nil {
+		return err
+	}
+	return drawPreviews([]client.Photo{*pic})
+}
+
+func photosRandom(e *env, args []string) error {
+	count := e.fs.String("count", "", "number of photos to return, 1 to 30")
+	query := e.fs.String("query", "", "limit selection to photos matching a search term")
+	username := e.fs.String("username", "", "limit selection to a single user")
+	collections := e.fs.String("collections", "", "comma separated collection IDs to filter selection")
+	topics := e.fs.String("topics", "", "comma separated topic IDs to filter selection")
+	orientation := e.fs.String("orientation", "", "landscape, portrait or squarish")
+	if _, err := e.parse(args, "", 0); err != nil {
+		return err
+	}
+	u, err := e.unsplash(false)
+	if err != nil {
+		return err
+	}
+	res, err := u.Photos.Random(e.query(map[string]string{
+		"count":       *count,
+		"query":       *query,
+		"username":    *username,
+		"collections": *collections,
+		"topics":      *topics,
+		"orientation": *orientation,
+	}))
+	if err != nil {
+		return err
+	}
+	switch pics := res.(type) {
+	case []client.Photo:
+		return e.print(pics, photosTable(pics))
+	case *client.Photo:
+		return e.print(pics, photosTable([]client.Photo{*pics}))
+	}
+	return nil
+}
+
+func photosStats(e *env, args []string) error {
+	resolution := e.fs.String("resolution", "", "frequency of the historical stats, only `days` is supported")
+	quantity := e.fs.String("quantity", "", "number of days of historical stats, 1 to 30")
+	pos, err := e.parse(args, "<photo-id>", 1)
+	if err != nil {
+		return err
+	}
+	u, err := e.unsplash(false)
+	if err != nil {
+		return err
+	}
+	stats, err := u.Photos.Stats(pos[0], e.query(map[string]string{"resolution": *resolution, "quantity": *quantity}))
+	if err != nil {
+		return err
+	}
+	return e.print(stats, statsTable(map[string]client.Stats{
+		"downloads": stats.Downloads, "views": stats.Views, "likes": stats.Likes,
+	}))
+}
+
+func photosSearch(e *env, args []string) error {
+	e.paginate()
+	orderBy := e.fs.String("order-by", "", "relevant or latest")
+	color := e.fs.String("color", "", "filter results by color, e.g. teal or black_and_white")
+	orientation := e.fs.String("orientation", "", "landscape, portrait or squarish")
+	contentFilter := e.fs.String("content-filter", "", "low or high")
+	collections := e.fs.String("collections", "", "comma separated collection IDs to narrow the search")
+	lang := e.fs.String("lang", "", "ISO 639-1 language code of the query")
+	drawPreviews := previewFlags(e)
+	pos, err := e.parse(args, "<query>...", -1)
+	if err != nil {
+		return err
+	}
+	u, err := e.unsplash(false)
+	if err != nil {
+		return err
+	}
+	res, err := u.Photos.Search(joinQuery(pos), e.query(map[string]string{
+		"order_by":       *orderBy,
+		"color":          *color,
+		"orientation":    *orientation,
+		"content_filter": *contentFilter,
+		"collections":    *collections,
+		"lang":           *lang,
+	}))
+	if err != nil {
+		return err
+	}
+	if err := e.print(res, photosTable(res.Results)); err != nil {
+		return err
+	}
+	return drawPreviews(res.Results)
+}
+
+func photosLike(e *env, args []string) error {
+	pos, err := e.parse(args, "<photo-id>", 1)
+	if err != nil {
+		return err
+	}
+	u, err := e.unsplash(true)
+	if err != nil {
+		return err
+	}
+	lr, err := u.Photos.Like(pos[0])
+	if err != nil {
+		return err
+	}
+	return e.print(lr, photosTable([]client.Photo{lr.Photo}))
+}
+
+func photosUnlike(e *env, args []string) error {
+	pos, err := e.parse(args, "<photo-id>", 1)
+	if err != nil {
+		return err
+	}
+	u, err := e.unsplash(true)
+	if err != nil {
+		return err
+	}
+	if err := u.Photos.Unlike(pos[0]); err != nil {
+		return err
+	}
+	return e.printOK("unliked " + pos[0])
+}
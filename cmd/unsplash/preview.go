@@ -0,0 +1,64 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/eddogola/unsplash-go/unsplash/client"
+	"github.com/eddogola/unsplash-go/unsplash/preview"
+)
+
+// previewFlags adds flags drawing photos in the terminal, returning a function that draws
+// the given photos after the results table if --preview is set
+func previewFlags(e *env) func(pics []client.Photo) error {
+	show := e.fs.Bool("preview", false, "draw the photos in the terminal after the results table")
+	width := cellsFlag(40)
+	e.fs.Var(&width, "preview-width", "width of previews in terminal cells")
+	protocol := e.fs.String("protocol", "auto", "terminal graphics protocol: auto, halfblocks, sixel or kitty")
+	offline := e.fs.Bool("offline", false, "draw previews from the photos' BlurHash or color without fetching images")
+	return func(pics []client.Photo) error {
+		if !*show || e.format != "table" {
+			return nil
+		}
+		p, err := preview.ParseProtocol(*protocol)
+		if err != nil {
+			return usageError(err.Error())
+		}
+		r := preview.New(int(width))
+		r.Client, r.Offline = httpClient, *offline
+		if p != preview.Auto {
+			r.Protocol = p
+		}
+		for i := range pics {
+			caption := pics[i].Description
+			if caption == "" {
+				caption = pics[i].AltDescription
+			}
+			fmt.Fprintf(e.stdout, "\n%s  %s\n", pics[i].ID, truncate(caption, int(width)))
+			if err := r.Render(context.Background(), e.stdout, &pics[i]); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+// cellsFlag is a width in terminal cells, refusing widths under one cell
+type cellsFlag int
+
+func (c *cellsFlag) String() string {
+	return strconv.Itoa(int(*c))
+}
+
+func (c *cellsFlag) Set(val string) error {
+	n, err := strconv.Atoi(val)
+	if err != nil {
+		return err
+	}
+	if n < 1 {
+		return fmt.Errorf("width must be at least 1 cell, got %d", n)
+	}
+	*c = cellsFlag(n)
+	return nil
+}
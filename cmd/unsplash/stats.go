@@ -0,0 +1,45 @@
+package main
+
+var statsCommands = map[string]command{
+	"total": {"", "get counts for all of Unsplash", statsTotal},
+	"month": {"", "get Unsplash stats for the past 30 days", statsMonth},
+}
+
+func statsTotal(e *env, args []string) error {
+	if _, err := e.parse(args, "", 0); err != nil {
+		return err
+	}
+	u, err := e.unsplash(false)
+	if err != nil {
+		return err
+	}
+	s, err := u.Stats.Total()
+	if err != nil {
+		return err
+	}
+	return e.print(s, fieldsTable(
+		"photos", s.Photos, "downloads", s.Downloads, "views", s.Views, "likes", s.Likes,
+		"photographers", s.Photographers, "pixels", s.Pixels, "downloads_per_second", s.DownloadsPerSecond,
+		"views_per_second", s.ViewPerSecond, "developers", s.Developers, "applications", s.Applications,
+		"requests", s.Requests,
+	))
+}
+
+func statsMonth(e *env, args []string) error {
+	if _, err := e.parse(args, "", 0); err != nil {
+		return err
+	}
+	u, err := e.unsplash(false)
+	if err != nil {
+		return err
+	}
+	s, err := u.Stats.Month()
+	if err != nil {
+		return err
+	}
+	return e.print(s, fieldsTable(
+		"downloads", s.Downloads, "views", s.Views, "likes", s.Likes, "new_photos", s.NewPhotos,
+		"new_photographers", s.NewPhotographers, "new_pixels", s.NewPixels, "new_developers", s.NewDevelopers,
+		"new_applications", s.NewApplications, "new_requests", s.NewRequests,
+	))
+}
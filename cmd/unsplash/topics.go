@@ -0,0 +1,64 @@
+package main
+
+import (
+	"github.com/eddogola/unsplash-go/unsplash/client"
+)
+
+var topicsCommands = map[string]command{
+	"list":   {"", "list a page of all topics", topicsList},
+	"get":    {"<topic-id-or-slug>", "get a topic", topicsGet},
+	"photos": {"<topic-id-or-slug>", "list a topic's photos", topicsPhotos},
+}
+
+func topicsList(e *env, args []string) error {
+	e.paginate()
+	orderBy := e.fs.String("order-by", "", "featured, latest, oldest or position")
+	ids := e.fs.String("ids", "", "comma separated topic IDs or slugs to return")
+	if _, err := e.parse(args, "", 0); err != nil {
+		return err
+	}
+	u, err := e.unsplash(false)
+	if err != nil {
+		return err
+	}
+	topics, err := u.Topics.All(e.query(map[string]string{"order_by": *orderBy, "ids": *ids}))
+	if err != nil {
+		return err
+	}
+	return e.print(topics, topicsTable(topics))
+}
+
+func topicsGet(e *env, args []string) error {
+	pos, err := e.parse(args, "<topic-id-or-slug>", 1)
+	if err != nil {
+		return err
+	}
+	u, err := e.unsplash(false)
+	if err != nil {
+		return err
+	}
+	topic, err := u.Topics.Get(pos[0])
+	if err != nil {
+		return err
+	}
+	return e.print(topic, topicsTable([]client.Topic{*topic}))
+}
+
+func topicsPhotos(e *env, args []string) error {
+	e.paginate()
+	orderBy := e.fs.String("order-by", "", "latest, oldest or popular")
+	orientation := e.fs.String("orientation", "", "landscape, portrait or squarish")
+	pos, err := e.parse(args, "<topic-id-or-slug>", 1)
+	if err != nil {
+		return err
+	}
+	u, err := e.unsplash(false)
+	if err != nil {
+		return err
+	}
+	pics, err := u.Topics.Photos(pos[0], e.query(map[string]string{"order_by": *orderBy, "orientation": *orientation}))
+	if err != nil {
+		return err
+	}
+	return e.print(pics, photosTable(pics))
+}
@@ -0,0 +1,122 @@
+package main
+
+import (
+	"strings"
+
+	"github.com/eddogola/unsplash-go/unsplash/client"
+)
+
+var usersCommands = map[string]command{
+	"get":         {"<username>", "get a user's public profile", usersGet},
+	"photos":      {"<username>", "list photos uploaded by a user", usersPhotos},
+	"likes":       {"<username>", "list photos liked by a user", usersLikes},
+	"collections": {"<username>", "list collections created by a user", usersCollections},
+	"stats":       {"<username>", "get a user's statistics", usersStats},
+	"search":      {"<query>...", "search users", usersSearch},
+}
+
+func usersGet(e *env, args []string) error {
+	pos, err := e.parse(args, "<username>", 1)
+	if err != nil {
+		return err
+	}
+	u, err := e.unsplash(false)
+	if err != nil {
+		return err
+	}
+	user, err := u.Users.PublicProfile(pos[0])
+	if err != nil {
+		return err
+	}
+	return e.print(user, usersTable([]client.User{*user}))
+}
+
+func usersPhotos(e *env, args []string) error {
+	return listUserPhotos(e, args, false)
+}
+
+func usersLikes(e *env, args []string) error {
+	return listUserPhotos(e, args, true)
+}
+
+func listUserPhotos(e *env, args []string, liked bool) error {
+	e.paginate()
+	orderBy := e.fs.String("order-by", "", "latest, oldest or popular")
+	orientation := e.fs.String("orientation", "", "landscape, portrait or squarish")
+	pos, err := e.parse(args, "<username>", 1)
+	if err != nil {
+		return err
+	}
+	u, err := e.unsplash(false)
+	if err != nil {
+		return err
+	}
+	qp := e.query(map[string]string{"order_by": *orderBy, "orientation": *orientation})
+	var pics []client.Photo
+	if liked {
+		pics, err = u.Users.LikedPhotos(pos[0], qp)
+	} else {
+		pics, err = u.Users.Photos(pos[0], qp)
+	}
+	if err != nil {
+		return err
+	}
+	return e.print(pics, photosTable(pics))
+}
+
+func usersCollections(e *env, args []string) error {
+	e.paginate()
+	pos, err := e.parse(args, "<username>", 1)
+	if err != nil {
+		return err
+	}
+	u, err := e.unsplash(false)
+	if err != nil {
+		return err
+	}
+	collections, err := u.Users.Collections(pos[0], e.query(nil))
+	if err != nil {
+		return err
+	}
+	return e.print(collections, collectionsTable(collections))
+}
+
+func usersStats(e *env, args []string) error {
+	resolution := e.fs.String("resolution", "", "frequency of the historical stats, only `days` is supported")
+	quantity := e.fs.String("quantity", "", "number of days of historical stats, 1 to 30")
+	pos, err := e.parse(args, "<username>", 1)
+	if err != nil {
+		return err
+	}
+	u, err := e.unsplash(false)
+	if err != nil {
+		return err
+	}
+	stats, err := u.Users.Stats(pos[0], e.query(map[string]string{"resolution": *resolution, "quantity": *quantity}))
+	if err != nil {
+		return err
+	}
+	return e.print(stats, statsTable(map[string]client.Stats{"downloads": stats.Downloads, "views": stats.Views}))
+}
+
+func usersSearch(e *env, args []string) error {
+	e.paginate()
+	pos, err := e.parse(args, "<query>...", -1)
+	if err != nil {
+		return err
+	}
+	u, err := e.unsplash(false)
+	if err != nil {
+		return err
+	}
+	res, err := u.Users.Search(joinQuery(pos), e.query(nil))
+	if err != nil {
+		return err
+	}
+	return e.print(res, usersTable(res.Results))
+}
+
+// joinQuery joins search terms passed as separate arguments
+func joinQuery(args []string) string {
+	return strings.Join(args, " ")
+}
@@ -0,0 +1,162 @@
+// Package attribution credits photographers and Unsplash as required by the Unsplash API guidelines.
+//
+// Links back to Unsplash must carry the `utm_source` and `utm_medium=referral` query parameters,
+// with `utm_source` set to the application's name.
+// https://help.unsplash.com/en/articles/2511315-guideline-attribution
+package attribution
+
+import (
+	"encoding/json"
+	"fmt"
+	"html"
+	"net/url"
+	"strings"
+
+	"github.com/eddogola/unsplash-go/unsplash/client"
+)
+
+const (
+	// UnsplashURL is the Unsplash home page, credited in every attribution
+	UnsplashURL = "https://unsplash.com/"
+	// LicenseURL is the Unsplash license page
+	LicenseURL = "https://unsplash.com/license"
+)
+
+// Attributor generates attributions with links referring back from the configured application
+type Attributor struct {
+	AppName string
+}
+
+// New constructs a new Attributor, given the name of the application as registered on Unsplash
+func New(appName string) *Attributor {
+	return &Attributor{AppName: appName}
+}
+
+// Link adds the referral parameters to link, overriding any referral parameters already present.
+// link is returned unchanged if it cannot be parsed.
+func (a *Attributor) Link(link string) string {
+	URL, err := url.Parse(link)
+	if err != nil {
+		return link
+	}
+	q := URL.Query()
+	q.Set("utm_source", a.AppName)
+	q.Set("utm_medium", "referral")
+	URL.RawQuery = q.Encode()
+	return URL.String()
+}
+
+// PhotographerURL returns the referral link to the Photo's photographer's profile
+func (a *Attributor) PhotographerURL(pic *client.Photo) string {
+	link := pic.User.Links.HTML
+	if link == "" {
+		link = UnsplashURL + "@" + pic.User.Username
+	}
+	return a.Link(link)
+}
+
+// PhotoURL returns the referral link to the Photo's page
+func (a *Attributor) PhotoURL(pic *client.Photo) string {
+	link := pic.Links.HTML
+	if link == "" {
+		link = UnsplashURL + "photos/" + pic.ID
+	}
+	return a.Link(link)
+}
+
+// UnsplashURL returns the referral link to Unsplash
+func (a *Attributor) UnsplashURL() string {
+	return a.Link(UnsplashURL)
+}
+
+// Text returns a plain text attribution, e.g. `Photo by Jane Doe on Unsplash`
+func (a *Attributor) Text(pic *client.Photo) string {
+	return fmt.Sprintf("Photo by %s on Unsplash", photographer(pic))
+}
+
+// Markdown returns the attribution in Markdown, linking to the photographer and Unsplash
+func (a *Attributor) Markdown(pic *client.Photo) string {
+	return fmt.Sprintf("Photo by [%s](%s) on [Unsplash](%s)",
+		escapeMarkdown(photographer(pic)), a.PhotographerURL(pic), a.UnsplashURL())
+}
+
+// HTML returns the attribution in HTML, linking to the photographer and Unsplash
+func (a *Attributor) HTML(pic *client.Photo) string {
+	return fmt.Sprintf(`Photo by <a href="%s">%s</a> on <a href="%s">Unsplash</a>`,
+		html.EscapeString(a.PhotographerURL(pic)), html.EscapeString(photographer(pic)),
+		html.EscapeString(a.UnsplashURL()))
+}
+
+// ImageObject defines a schema.org ImageObject, used to describe a photo in JSON-LD
+// https://schema.org/ImageObject
+type ImageObject struct {
+	Context            string `json:"@context"`
+	Type               string `json:"@type"`
+	ContentURL         string `json:"contentUrl"`
+	URL                string `json:"url"`
+	Description        string `json:"description,omitempty"`
+	Width              int    `json:"width,omitempty"`
+	Height             int    `json:"height,omitempty"`
+	Creator            Person `json:"creator"`
+	CreditText         string `json:"creditText"`
+	CopyrightNotice    string `json:"copyrightNotice"`
+	License            string `json:"license"`
+	AcquireLicensePage string `json:"acquireLicensePage"`
+}
+
+// Person defines a schema.org Person
+type Person struct {
+	Type string `json:"@type"`
+	Name string `json:"name"`
+	URL  string `json:"url"`
+}
+
+// ImageObject returns the Photo described as a schema.org ImageObject
+func (a *Attributor) ImageObject(pic *client.Photo) *ImageObject {
+	description := pic.Description
+	if description == "" {
+		description = pic.AltDescription
+	}
+	return &ImageObject{
+		Context:     "https://schema.org",
+		Type:        "ImageObject",
+		ContentURL:  pic.URLs.Regular,
+		URL:         a.PhotoURL(pic),
+		Description: description,
+		Width:       pic.Width,
+		Height:      pic.Height,
+		Creator: Person{
+			Type: "Person",
+			Name: photographer(pic),
+			URL:  a.PhotographerURL(pic),
+		},
+		CreditText:         a.Text(pic),
+		CopyrightNotice:    photographer(pic),
+		License:            LicenseURL,
+		AcquireLicensePage: a.PhotoURL(pic),
+	}
+}
+
+// JSONLD returns the Photo's ImageObject encoded as JSON-LD, to be embedded in a
+// `<script type="application/ld+json">` element.
+// `<`, `>` and `&` are escaped so the JSON cannot close the script element.
+func (a *Attributor) JSONLD(pic *client.Photo) ([]byte, error) {
+	return json.Marshal(a.ImageObject(pic))
+}
+
+// photographer returns the photographer's name, falling back to their username
+func photographer(pic *client.Photo) string {
+	if pic.User.Name != "" {
+		return pic.User.Name
+	}
+	return pic.User.Username
+}
+
+var markdownEscaper = strings.NewReplacer(
+	`\`, `\\`, "`", "\\`", "*", `\*`, "_", `\_`,
+	"[", `\[`, "]", `\]`, "(", `\(`, ")", `\)`, "<", `\<`, ">", `\>`,
+)
+
+func escapeMarkdown(s string) string {
+	return markdownEscaper.Replace(s)
+}
@@ -0,0 +1,76 @@
+package attribution
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/eddogola/unsplash-go/unsplash/client"
+)
+
+func testPhoto() *client.Photo {
+	var p client.Photo
+	p.ID = "abc"
+	p.URLs.Regular = "https://images.unsplash.com/photo-1?ixid=x&w=1080"
+	p.Links.HTML = "https://unsplash.com/photos/abc"
+	p.User.Name = "Jane <Doe> [Smith]"
+	p.User.Username = "jane"
+	p.User.Links.HTML = "https://unsplash.com/@jane?utm_source=other"
+	return &p
+}
+
+func TestAttributor(t *testing.T) {
+	a := New("my app")
+	p := testPhoto()
+
+	t.Run("referral links", func(t *testing.T) {
+		expected := "https://unsplash.com/@jane?utm_medium=referral&utm_source=my+app"
+		if got := a.PhotographerURL(p); got != expected {
+			t.Errorf("expected %v but got %v", expected, got)
+		}
+		expected = "https://unsplash.com/?utm_medium=referral&utm_source=my+app"
+		if got := a.UnsplashURL(); got != expected {
+			t.Errorf("expected %v but got %v", expected, got)
+		}
+	})
+
+	t.Run("text", func(t *testing.T) {
+		expected := "Photo by Jane <Doe> [Smith] on Unsplash"
+		if got := a.Text(p); got != expected {
+			t.Errorf("expected %v but got %v", expected, got)
+		}
+	})
+
+	t.Run("markdown", func(t *testing.T) {
+		expected := `Photo by [Jane \<Doe\> \[Smith\]](https://unsplash.com/@jane?utm_medium=referral&utm_source=my+app) ` +
+			`on [Unsplash](https://unsplash.com/?utm_medium=referral&utm_source=my+app)`
+		if got := a.Markdown(p); got != expected {
+			t.Errorf("expected %v but got %v", expected, got)
+		}
+	})
+
+	t.Run("html", func(t *testing.T) {
+		expected := `Photo by <a href="https://unsplash.com/@jane?utm_medium=referral&amp;utm_source=my+app">` +
+			`Jane &lt;Doe&gt; [Smith]</a> on <a href="https://unsplash.com/?utm_medium=referral&amp;utm_source=my+app">Unsplash</a>`
+		if got := a.HTML(p); got != expected {
+			t.Errorf("expected %v but got %v", expected, got)
+		}
+	})
+
+	t.Run("json-ld", func(t *testing.T) {
+		data, err := a.JSONLD(p)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if strings.Contains(string(data), "<") {
+			t.Errorf("expected `<` to be escaped in %s", data)
+		}
+		var obj ImageObject
+		if err := json.Unmarshal(data, &obj); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if obj.Type != "ImageObject" || obj.Creator.Name != p.User.Name || obj.License != LicenseURL {
+			t.Errorf("unexpected image object %+v", obj)
+		}
+	})
+}
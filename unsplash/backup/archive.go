@@ -0,0 +1,135 @@
+package backup
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// IsTarball reports whether path names a `.tar.gz` archive, rather than a directory
+func IsTarball(path string) bool {
+	return strings.HasSuffix(path, ".tar.gz") || strings.HasSuffix(path, ".tgz")
+}
+
+// archiveWriter writes files into an archive
+type archiveWriter interface {
+	WriteFile(name string, data []byte) error
+	Close() error
+}
+
+// createArchive creates an archive at path, a tarball if path ends in `.tar.gz` or `.tgz`,
+// otherwise a directory
+func createArchive(path string) (archiveWriter, error) {
+	if !IsTarball(path) {
+		return dirWriter(path), os.MkdirAll(path, 0755)
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return nil, err
+	}
+	gz := gzip.NewWriter(f)
+	return &tarWriter{f: f, gz: gz, tw: tar.NewWriter(gz)}, nil
+}
+
+type dirWriter string
+
+func (d dirWriter) WriteFile(name string, data []byte) error {
+	path := filepath.Join(string(d), filepath.FromSlash(name))
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, data, 0644)
+}
+
+func (d dirWriter) Close() error {
+	return nil
+}
+
+type tarWriter struct {
+	f  *os.File
+	gz *gzip.Writer
+	tw *tar.Writer
+}
+
+func (t *tarWriter) WriteFile(name string, data []byte) error {
+	hdr := &tar.Header{
+		Name:    name,
+		Mode:    0644,
+		Size:    int64(len(data)),
+		ModTime: time.Now(),
+	}
+	if err := t.tw.WriteHeader(hdr); err != nil {
+		return err
+	}
+	_, err := t.tw.Write(data)
+	return err
+}
+
+func (t *tarWriter) Close() error {
+	if err := t.tw.Close(); err != nil {
+		t.f.Close()
+		return err
+	}
+	if err := t.gz.Close(); err != nil {
+		t.f.Close()
+		return err
+	}
+	return t.f.Close()
+}
+
+// ReadManifest reads the manifest of the archive at path, a `.tar.gz` file or a directory
+func ReadManifest(path string) (*Manifest, error) {
+	var data []byte
+	var err error
+	if IsTarball(path) {
+		data, err = readTarFile(path, ManifestFile)
+	} else {
+		data, err = ioutil.ReadFile(filepath.Join(path, ManifestFile))
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var m Manifest
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("error parsing manifest: %v", err)
+	}
+	if m.Version < 1 || m.Version > FormatVersion {
+		return nil, ErrUnsupportedVersion(m.Version)
+	}
+	return &m, nil
+}
+
+// readTarFile returns the contents of the named file in the tarball at path
+func readTarFile(path, name string) ([]byte, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		return nil, err
+	}
+	defer gz.Close()
+	tr := tar.NewReader(gz)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return nil, fmt.Errorf("%s not found in %s", name, path)
+		}
+		if err != nil {
+			return nil, err
+		}
+		if hdr.Name == name {
+			return ioutil.ReadAll(tr)
+		}
+	}
+}
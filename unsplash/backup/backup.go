@@ -0,0 +1,123 @@
+// Package backup exports collections to versioned archives, and restores them into
+// the same or another account.
+//
+// An archive holds a `manifest.json` file listing each collection's metadata and photos,
+// and optionally the photos' image files under `images/`. Archives are written either to
+// a directory, or to a single `.tar.gz` file.
+//
+// Restoring recreates the archived collections and adds their photos. It's idempotent:
+// collections already present in the account, matched by title, are reused, and photos
+// already in them are skipped, so an interrupted restore can be run again.
+package backup
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/eddogola/unsplash-go/unsplash/client"
+)
+
+// FormatVersion is the version of the archive format written by Export.
+// It's increased whenever the manifest changes in a way older versions cannot read.
+const FormatVersion = 1
+
+// ManifestFile is the name of the manifest in an archive
+const ManifestFile = "manifest.json"
+
+// Manifest defines the contents of an archive
+type Manifest struct {
+	Version     int                  `json:"version"`
+	CreatedAt   time.Time            `json:"created_at"`
+	Collections []ArchivedCollection `json:"collections"`
+	// Images lists the image files in the archive, by photo ID.
+	// Photos in more than one collection are only stored once.
+	Images map[string]Image `json:"images,omitempty"`
+}
+
+// ArchivedCollection defines a collection's metadata and photos, in the collection's order
+type ArchivedCollection struct {
+	Collection client.Collection `json:"collection"`
+	Photos     []client.Photo    `json:"photos"`
+}
+
+// Image defines an image file stored in an archive
+type Image struct {
+	File   string `json:"file"`
+	Size   int64  `json:"size"`
+	SHA256 string `json:"sha256"`
+}
+
+// ErrUnsupportedVersion is raised when reading an archive written by a newer version of the package
+type ErrUnsupportedVersion int
+
+func (e ErrUnsupportedVersion) Error() string {
+	return fmt.Sprintf("unsupported archive version %d, the latest supported version is %d", int(e), FormatVersion)
+}
+
+// Failure records a photo that could not be exported or restored
+type Failure struct {
+	CollectionID string
+	PhotoID      string
+	Err          error
+}
+
+func (f Failure) Error() string {
+	return fmt.Sprintf("collection %s, photo %s: %v", f.CollectionID, f.PhotoID, f.Err)
+}
+
+// Client defines client methods used to export collections
+type Client interface {
+	GetCollection(context.Context, string) (*client.Collection, error)
+	GetCollectionPhotos(context.Context, string, client.QueryParams) ([]client.Photo, error)
+	GetUserCollections(context.Context, string, client.QueryParams) ([]client.Collection, error)
+	DownloadPhoto(context.Context, *client.Photo, string, io.Writer, client.ProgressFunc) (int64, error)
+}
+
+// RestoreClient defines client methods used to restore collections.
+// The client must be private, with the write_collections scope.
+type RestoreClient interface {
+	GetUserPrivateProfile(context.Context) (*client.User, error)
+	GetUserCollections(context.Context, string, client.QueryParams) ([]client.Collection, error)
+	GetCollectionPhotos(context.Context, string, client.QueryParams) ([]client.Photo, error)
+	CreateCollection(context.Context, map[string]string) (*client.Collection, error)
+	AddPhotoToCollection(context.Context, string, map[string]string) (*client.CollectionActionResponse, error)
+}
+
+// defaultPerPage is the number of results requested per page when listing, the most the API allows
+const defaultPerPage = 30
+
+// listPhotos requests every page of a collection's photos
+func listPhotos(ctx context.Context, getPage func(context.Context, string, client.QueryParams) ([]client.Photo, error), id string) ([]client.Photo, error) {
+	var pics []client.Photo
+	for page := 1; ; page++ {
+		res, err := getPage(ctx, id, pageParams(page))
+		if err != nil {
+			return nil, err
+		}
+		pics = append(pics, res...)
+		if len(res) < defaultPerPage {
+			return pics, nil
+		}
+	}
+}
+
+// listCollections requests every page of a user's collections
+func listCollections(ctx context.Context, getPage func(context.Context, string, client.QueryParams) ([]client.Collection, error), username string) ([]client.Collection, error) {
+	var collections []client.Collection
+	for page := 1; ; page++ {
+		res, err := getPage(ctx, username, pageParams(page))
+		if err != nil {
+			return nil, err
+		}
+		collections = append(collections, res...)
+		if len(res) < defaultPerPage {
+			return collections, nil
+		}
+	}
+}
+
+func pageParams(page int) client.QueryParams {
+	return client.QueryParams{"page": fmt.Sprint(page), "per_page": fmt.Sprint(defaultPerPage)}
+}
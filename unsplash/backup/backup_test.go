@@ -0,0 +1,269 @@
+package backup
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"github.com/eddogola/unsplash-go/unsplash/client"
+)
+
+// fakeClient holds collections in memory, keyed by ID. Like the API, it lists the most recently
+// added photos first.
+type fakeClient struct {
+	username    string
+	collections map[string]*client.Collection
+	photos      map[string][]client.Photo
+	order       []string // collection IDs, in the order listed
+	created     int
+	added       int
+}
+
+func newFakeClient(username string) *fakeClient {
+	return &fakeClient{username: username, collections: make(map[string]*client.Collection), photos: make(map[string][]client.Photo)}
+}
+
+func (f *fakeClient) add(c client.Collection, pics ...client.Photo) {
+	c.User.Username = f.username
+	f.collections[c.ID] = &c
+	f.photos[c.ID] = pics
+	f.order = append(f.order, c.ID)
+}
+
+// page returns the items in the given page, 30 per page
+func page(n int, qp client.QueryParams) (int, int) {
+	p, _ := strconv.Atoi(qp["page"])
+	start := (p - 1) * defaultPerPage
+	if start > n {
+		start = n
+	}
+	end := start + defaultPerPage
+	if end > n {
+		end = n
+	}
+	return start, end
+}
+
+func (f *fakeClient) GetCollection(ctx context.Context, id string) (*client.Collection, error) {
+	c, ok := f.collections[id]
+	if !ok {
+		return nil, errors.New("not found")
+	}
+	return c, nil
+}
+
+func (f *fakeClient) GetCollectionPhotos(ctx context.Context, id string, qp client.QueryParams) ([]client.Photo, error) {
+	pics := f.photos[id]
+	start, end := page(len(pics), qp)
+	return pics[start:end], nil
+}
+
+func (f *fakeClient) GetUserCollections(ctx context.Context, username string, qp client.QueryParams) ([]client.Collection, error) {
+	if username != f.username {
+		return nil, nil
+	}
+	start, end := page(len(f.order), qp)
+	var res []client.Collection
+	for _, id := range f.order[start:end] {
+		res = append(res, *f.collections[id])
+	}
+	return res, nil
+}
+
+func (f *fakeClient) DownloadPhoto(ctx context.Context, pic *client.Photo, link string, w io.Writer, progress client.ProgressFunc) (int64, error) {
+	if pic.ID == "broken" {
+		return 0, errors.New("download failed")
+	}
+	n, err := io.WriteString(w, "image of "+pic.ID)
+	return int64(n), err
+}
+
+func (f *fakeClient) GetUserPrivateProfile(ctx context.Context) (*client.User, error) {
+	return &client.User{Username: f.username}, nil
+}
+
+func (f *fakeClient) CreateCollection(ctx context.Context, data map[string]string) (*client.Collection, error) {
+	f.created++
+	c := client.Collection{ID: fmt.Sprintf("new%d", f.created), Title: data["title"], Description: data["description"], Private: data["private"] == "true"}
+	f.add(c)
+	return &c, nil
+}
+
+func (f *fakeClient) AddPhotoToCollection(ctx context.Context, id string, data map[string]string) (*client.CollectionActionResponse, error) {
+	if data["photo_id"] == "deleted" {
+		return nil, errors.New("photo not found")
+	}
+	f.added++
+	f.photos[id] = append([]client.Photo{{ID: data["photo_id"]}}, f.photos[id]...)
+	return &client.CollectionActionResponse{Collection: *f.collections[id]}, nil
+}
+
+func photos(ids ...string) []client.Photo {
+	pics := make([]client.Photo, len(ids))
+	for i, id := range ids {
+		pics[i] = client.Photo{ID: id}
+	}
+	return pics
+}
+
+func TestExport(t *testing.T) {
+	src := newFakeClient("jane")
+	var many []string
+	for i := 0; i < 45; i++ {
+		many = append(many, fmt.Sprintf("p%d", i))
+	}
+	src.add(client.Collection{ID: "c1", Title: "Forests"}, photos(many...)...)
+	src.add(client.Collection{ID: "c2", Title: "Secret", Private: true}, photos("p1", "broken")...)
+
+	e := NewExporter(src)
+	e.Images = true
+	collections, err := e.UserCollections(context.Background(), "jane")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(collections) != 2 || len(collections[0].Photos) != 45 {
+		t.Fatalf("expected every page of photos to be fetched, got %d collections", len(collections))
+	}
+
+	for _, name := range []string{"backup", "backup.tar.gz"} {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), name)
+			m, failures, err := e.Export(context.Background(), path, collections)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(failures) != 1 || failures[0].PhotoID != "broken" {
+				t.Errorf("expected the broken image to be reported, got %v", failures)
+			}
+			if len(m.Images) != 45 {
+				t.Errorf("expected images shared by collections to be stored once, got %d", len(m.Images))
+			}
+
+			read, err := ReadManifest(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if read.Version != FormatVersion || len(read.Collections) != 2 || !read.Collections[1].Collection.Private {
+				t.Errorf("unexpected manifest read back: %+v", read)
+			}
+			if name == "backup" {
+				data, err := ioutil.ReadFile(filepath.Join(path, "images", "p1.jpg"))
+				if err != nil || string(data) != "image of p1" {
+					t.Errorf("expected image file in the archive, got %q, %v", data, err)
+				}
+			} else {
+				data, err := readTarFile(path, "images/p1.jpg")
+				if err != nil || string(data) != "image of p1" {
+					t.Errorf("expected image file in the tarball, got %q, %v", data, err)
+				}
+			}
+		})
+	}
+}
+
+func TestReadManifestVersion(t *testing.T) {
+	dir := t.TempDir()
+	ioutil.WriteFile(filepath.Join(dir, ManifestFile), []byte(`{"version": 99}`), 0644)
+	if _, err := ReadManifest(dir); err != ErrUnsupportedVersion(99) {
+		t.Errorf("expected unsupported version error, got %v", err)
+	}
+	if _, err := ReadManifest(filepath.Join(dir, "missing")); !os.IsNotExist(err) {
+		t.Errorf("expected missing archive error, got %v", err)
+	}
+}
+
+func TestRestore(t *testing.T) {
+	m := &Manifest{Version: FormatVersion, Collections: []ArchivedCollection{
+		{Collection: client.Collection{ID: "c1", Title: "Forests", Description: "trees"}, Photos: photos("a", "b", "deleted")},
+		{Collection: client.Collection{ID: "c2", Title: "Secret", Private: true}, Photos: photos("c", "d", "e")},
+	}}
+	dst := newFakeClient("team")
+	// the account already has a collection with one of the photos
+	dst.add(client.Collection{ID: "existing", Title: " forests "}, photos("a")...)
+
+	t.Run("dry run", func(t *testing.T) {
+		r := NewRestorer(dst)
+		r.DryRun = true
+		report, err := r.Restore(context.Background(), m)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if dst.created != 0 || dst.added != 0 {
+			t.Errorf("expected no changes, got %d created and %d added", dst.created, dst.added)
+		}
+		if !report.Collections[1].Created || report.Collections[0].Added != 2 {
+			t.Errorf("expected changes to be reported, got %+v", report.Collections)
+		}
+	})
+
+	r := NewRestorer(dst)
+	report, err := r.Restore(context.Background(), m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	forests, secret := report.Collections[0], report.Collections[1]
+	if forests.Created || forests.ID != "existing" || forests.Added != 1 || forests.Present != 1 {
+		t.Errorf("expected the existing collection to be reused, got %+v", forests)
+	}
+	if !secret.Created || !dst.collections[secret.ID].Private || secret.Added != 3 {
+		t.Errorf("expected a private collection to be created, got %+v", secret)
+	}
+	var order []string
+	for _, p := range dst.photos[secret.ID] {
+		order = append(order, p.ID)
+	}
+	if fmt.Sprint(order) != "[c d e]" {
+		t.Errorf("expected the photos to be listed in the archive's order, got %v", order)
+	}
+	if len(report.Failures) != 1 || report.Failures[0].PhotoID != "deleted" {
+		t.Errorf("expected the deleted photo to fail, got %v", report.Failures)
+	}
+
+	// restoring again changes nothing
+	created, added := dst.created, dst.added
+	if _, err := r.Restore(context.Background(), m); err != nil {
+		t.Fatal(err)
+	}
+	if dst.created != created || dst.added != added {
+		t.Errorf("expected restoring again to make no changes, got %d created and %d added", dst.created-created, dst.added-added)
+	}
+}
+
+func TestRestoreDryRunMergesTitles(t *testing.T) {
+	m := &Manifest{Version: FormatVersion, Collections: []ArchivedCollection{
+		{Collection: client.Collection{ID: "c1", Title: "Trips"}, Photos: photos("a", "b")},
+		{Collection: client.Collection{ID: "c2", Title: "trips "}, Photos: photos("b", "c")},
+	}}
+	dst := newFakeClient("team")
+	summary := func(report *RestoreReport) string {
+		var s string
+		for _, c := range report.Collections {
+			s += fmt.Sprintf("%v %d %d; ", c.Created, c.Added, c.Present)
+		}
+		return s
+	}
+
+	r := NewRestorer(dst)
+	r.DryRun = true
+	planned, err := r.Restore(context.Background(), m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := summary(planned); got != "true 2 0; false 1 1; " {
+		t.Errorf("expected the second collection to be merged into the first, got %s", got)
+	}
+
+	report, err := NewRestorer(dst).Restore(context.Background(), m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if summary(report) != summary(planned) || dst.created != 1 {
+		t.Errorf("expected the dry run to report what the restore did, got %s and %s", summary(planned), summary(report))
+	}
+}
@@ -0,0 +1,138 @@
+package backup
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/eddogola/unsplash-go/unsplash/client"
+	"github.com/eddogola/unsplash-go/unsplash/utils"
+)
+
+// Exporter fetches collections and writes them to archives
+type Exporter struct {
+	client Client
+	// Images downloads every photo's image file into the archive. Downloads are tracked,
+	// as required by the API guidelines.
+	Images bool
+	// ResizeOptions, if not nil, are used to download resized images instead of the full size images
+	ResizeOptions *utils.ResizeOptions
+}
+
+// NewExporter constructs a new Exporter, exporting metadata only.
+// To export a user's private collections, c must be private, with the read_collections scope.
+func NewExporter(c Client) *Exporter {
+	return &Exporter{client: c}
+}
+
+// Collection fetches a collection's metadata and all its photos
+func (e *Exporter) Collection(ctx context.Context, id string) (*ArchivedCollection, error) {
+	collection, err := e.client.GetCollection(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	pics, err := listPhotos(ctx, e.client.GetCollectionPhotos, id)
+	if err != nil {
+		return nil, err
+	}
+	return &ArchivedCollection{Collection: *collection, Photos: pics}, nil
+}
+
+// UserCollections fetches all of a user's collections, with their photos.
+// Private collections are included if the client is authorised to read them.
+func (e *Exporter) UserCollections(ctx context.Context, username string) ([]ArchivedCollection, error) {
+	collections, err := listCollections(ctx, e.client.GetUserCollections, username)
+	if err != nil {
+		return nil, err
+	}
+	archived := make([]ArchivedCollection, 0, len(collections))
+	for _, collection := range collections {
+		pics, err := listPhotos(ctx, e.client.GetCollectionPhotos, collection.ID)
+		if err != nil {
+			return nil, err
+		}
+		archived = append(archived, ArchivedCollection{Collection: collection, Photos: pics})
+	}
+	return archived, nil
+}
+
+// Export writes collections to an archive at path: a `.tar.gz` file if path ends in `.tar.gz` or `.tgz`,
+// otherwise a directory. Images that fail to download are left out of the archive, and reported
+// as failures; the manifest is written regardless.
+func (e *Exporter) Export(ctx context.Context, path string, collections []ArchivedCollection) (*Manifest, []Failure, error) {
+	m := &Manifest{
+		Version:     FormatVersion,
+		CreatedAt:   time.Now().UTC(),
+		Collections: collections,
+	}
+	w, err := createArchive(path)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var failures []Failure
+	if e.Images {
+		m.Images = make(map[string]Image)
+		for _, ac := range collections {
+			for i := range ac.Photos {
+				pic := &ac.Photos[i]
+				if _, ok := m.Images[pic.ID]; ok {
+					continue
+				}
+				img, err := e.writeImage(ctx, w, pic)
+				if err != nil {
+					if ctx.Err() != nil {
+						w.Close()
+						return nil, nil, ctx.Err()
+					}
+					failures = append(failures, Failure{ac.Collection.ID, pic.ID, err})
+					continue
+				}
+				m.Images[pic.ID] = img
+			}
+		}
+	}
+
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		w.Close()
+		return nil, nil, err
+	}
+	if err := w.WriteFile(ManifestFile, data); err != nil {
+		w.Close()
+		return nil, nil, err
+	}
+	sort.Slice(failures, func(i, j int) bool { return failures[i].PhotoID < failures[j].PhotoID })
+	return m, failures, w.Close()
+}
+
+// writeImage downloads pic's image into the archive
+func (e *Exporter) writeImage(ctx context.Context, w archiveWriter, pic *client.Photo) (Image, error) {
+	link := pic.URLs.Full
+	ext := "jpg"
+	if e.ResizeOptions != nil {
+		var err error
+		if link, err = utils.BuildPhotoURL(pic, *e.ResizeOptions); err != nil {
+			return Image{}, err
+		}
+		if e.ResizeOptions.ImageFormat != "" {
+			ext = strings.ToLower(e.ResizeOptions.ImageFormat)
+		}
+	}
+	var buf bytes.Buffer
+	n, err := e.client.DownloadPhoto(ctx, pic, link, &buf, nil)
+	if err != nil {
+		return Image{}, err
+	}
+	name := "images/" + pic.ID + "." + ext
+	if err := w.WriteFile(name, buf.Bytes()); err != nil {
+		return Image{}, err
+	}
+	sum := sha256.Sum256(buf.Bytes())
+	return Image{File: name, Size: n, SHA256: hex.EncodeToString(sum[:])}, nil
+}
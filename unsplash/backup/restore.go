@@ -0,0 +1,154 @@
+package backup
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/eddogola/unsplash-go/unsplash/client"
+)
+
+// Restorer recreates archived collections in an account
+type Restorer struct {
+	client RestoreClient
+	// Username is the account collections are restored into, used to find collections restored before.
+	// It's requested from the API, which needs the read_user scope, if empty.
+	Username string
+	// DryRun reports the changes a restore would make, without making them
+	DryRun bool
+}
+
+// RestoredCollection reports what was done to restore one collection
+type RestoredCollection struct {
+	SourceID string // ID of the archived collection
+	ID       string // ID of the collection in the account, empty if not created in a dry run
+	Title    string
+	Created  bool // false if an existing collection was reused
+	Added    int  // photos added
+	Present  int  // photos already in the collection
+}
+
+// RestoreReport summarizes a restore
+type RestoreReport struct {
+	Collections []RestoredCollection
+	Failures    []Failure
+}
+
+// NewRestorer constructs a new Restorer.
+// c must be private, with the write_collections scope.
+func NewRestorer(c RestoreClient) *Restorer {
+	return &Restorer{client: c}
+}
+
+// Restore recreates the collections in m. A collection in the account with the same title as an
+// archived one is reused, only adding the photos missing from it, so running a restore again
+// makes no changes. Photos that cannot be added, e.g. because they were deleted, are reported as
+// failures without stopping the restore.
+func (r *Restorer) Restore(ctx context.Context, m *Manifest) (*RestoreReport, error) {
+	username := r.Username
+	if username == "" {
+		me, err := r.client.GetUserPrivateProfile(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("error getting the account to restore into, set Restorer.Username: %w", err)
+		}
+		username = me.Username
+	}
+	existing, err := listCollections(ctx, r.client.GetUserCollections, username)
+	if err != nil {
+		return nil, err
+	}
+	byTitle := make(map[string]client.Collection)
+	for _, c := range existing {
+		key := titleKey(c.Title)
+		if _, ok := byTitle[key]; !ok {
+			byTitle[key] = c
+		}
+	}
+
+	report := &RestoreReport{}
+	planned := make(map[string]map[string]bool) // photos of the collections a dry run would create, by title key
+	for _, ac := range m.Collections {
+		restored, failures, err := r.restoreCollection(ctx, ac, byTitle, planned)
+		if err != nil {
+			return report, err
+		}
+		report.Collections = append(report.Collections, restored)
+		report.Failures = append(report.Failures, failures...)
+	}
+	return report, nil
+}
+
+func (r *Restorer) restoreCollection(ctx context.Context, ac ArchivedCollection, byTitle map[string]client.Collection,
+	planned map[string]map[string]bool) (RestoredCollection, []Failure, error) {
+	src := ac.Collection
+	restored := RestoredCollection{SourceID: src.ID, Title: src.Title}
+
+	key := titleKey(src.Title)
+	present := make(map[string]bool)
+	if c, ok := byTitle[key]; ok {
+		restored.ID = c.ID
+		if photos, ok := planned[key]; ok {
+			// merged into a collection the dry run would create
+			present = photos
+		} else {
+			pics, err := listPhotos(ctx, r.client.GetCollectionPhotos, c.ID)
+			if err != nil {
+				return restored, nil, err
+			}
+			for _, p := range pics {
+				present[p.ID] = true
+			}
+		}
+	} else {
+		restored.Created = true
+		if r.DryRun {
+			// later collections with the same title would be merged into this one
+			byTitle[key] = client.Collection{Title: src.Title}
+			planned[key] = present
+		} else {
+			c, err := r.client.CreateCollection(ctx, map[string]string{
+				"title":       src.Title,
+				"description": src.Description,
+				"private":     fmt.Sprint(src.Private),
+			})
+			if err != nil {
+				return restored, nil, fmt.Errorf("error creating collection %q: %w", src.Title, err)
+			}
+			restored.ID = c.ID
+			// later collections with the same title are merged into this one
+			byTitle[key] = *c
+		}
+	}
+
+	var failures []Failure
+	// the archive lists photos as the API does, most recently added first, and the API lists each
+	// photo added first, so photos are added last first to restore the collection's order
+	for i := len(ac.Photos) - 1; i >= 0; i-- {
+		p := ac.Photos[i]
+		if present[p.ID] {
+			restored.Present++
+			continue
+		}
+		if !r.DryRun {
+			_, err := r.client.AddPhotoToCollection(ctx, restored.ID, map[string]string{
+				"collection_id": restored.ID,
+				"photo_id":      p.ID,
+			})
+			if err != nil {
+				if ctx.Err() != nil {
+					return restored, failures, ctx.Err()
+				}
+				failures = append(failures, Failure{src.ID, p.ID, err})
+				continue
+			}
+		}
+		present[p.ID] = true
+		restored.Added++
+	}
+	return restored, failures, nil
+}
+
+// titleKey normalizes a collection title for matching
+func titleKey(title string) string {
+	return strings.ToLower(strings.TrimSpace(title))
+}
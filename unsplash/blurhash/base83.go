@@ -0,0 +1,36 @@
+package blurhash
+
+import "strings"
+
+const base83Chars = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz#$%*+,-.:;=?@[]^_{|}~"
+
+// decode83 decodes a base 83 string into an integer
+func decode83(str string) (int, error) {
+	var value int
+	for _, r := range str {
+		digit := strings.IndexRune(base83Chars, r)
+		if digit < 0 {
+			return 0, ErrInvalidHash("invalid character " + string(r))
+		}
+		value = value*83 + digit
+	}
+	return value, nil
+}
+
+// encode83 encodes value as a base 83 string of the given length
+func encode83(value, length int) string {
+	var b strings.Builder
+	for i := 1; i <= length; i++ {
+		digit := (value / pow83(length-i)) % 83
+		b.WriteByte(base83Chars[digit])
+	}
+	return b.String()
+}
+
+func pow83(exp int) int {
+	result := 1
+	for i := 0; i < exp; i++ {
+		result *= 83
+	}
+	return result
+}
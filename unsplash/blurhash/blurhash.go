@@ -0,0 +1,268 @@
+// Package blurhash decodes and encodes BlurHash strings, the compact placeholders
+// Unsplash returns for every photo in Photo.BlurHash.
+//
+// Decoded placeholders can be rendered inline as tiny PNG data URIs while the full image loads.
+// The algorithm is described at https://blurha.sh.
+package blurhash
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"image"
+	"image/color"
+	"image/png"
+	"math"
+
+	"github.com/eddogola/unsplash-go/unsplash/client"
+)
+
+// ErrInvalidHash is raised when a BlurHash string cannot be decoded
+type ErrInvalidHash string
+
+func (e ErrInvalidHash) Error() string {
+	return "invalid blurhash: " + string(e)
+}
+
+// ErrInvalidComponents is raised when encoding with a number of components outside 1-9
+type ErrInvalidComponents int
+
+func (e ErrInvalidComponents) Error() string {
+	return "blurhash components must be between 1 and 9"
+}
+
+// ErrEmptyImage is raised when encoding an image with no pixels
+var ErrEmptyImage = errors.New("cannot compute the blurhash of an empty image")
+
+// Components returns the number of horizontal and vertical components encoded in hash
+func Components(hash string) (x, y int, err error) {
+	if len(hash) < 6 {
+		return 0, 0, ErrInvalidHash("must be at least 6 characters long")
+	}
+	sizeFlag, err := decode83(hash[:1])
+	if err != nil {
+		return 0, 0, err
+	}
+	x = sizeFlag%9 + 1
+	y = sizeFlag/9 + 1
+	if len(hash) != 4+2*x*y {
+		return 0, 0, ErrInvalidHash("length does not match the number of components")
+	}
+	return x, y, nil
+}
+
+// Decode decodes hash into an image of the given dimensions.
+// punch adjusts the contrast of the image, 1 leaves it as encoded.
+func Decode(hash string, width, height int, punch float64) (*image.NRGBA, error) {
+	numX, numY, err := Components(hash)
+	if err != nil {
+		return nil, err
+	}
+	if punch <= 0 {
+		punch = 1
+	}
+	quantisedMax, err := decode83(hash[1:2])
+	if err != nil {
+		return nil, err
+	}
+	maxValue := float64(quantisedMax+1) / 166 * punch
+
+	colors := make([][3]float64, numX*numY)
+	for i := range colors {
+		if i == 0 {
+			val, err := decode83(hash[2:6])
+			if err != nil {
+				return nil, err
+			}
+			colors[i] = decodeDC(val)
+			continue
+		}
+		val, err := decode83(hash[4+i*2 : 6+i*2])
+		if err != nil {
+			return nil, err
+		}
+		colors[i] = decodeAC(val, maxValue)
+	}
+
+	img := image.NewNRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			var r, g, b float64
+			for j := 0; j < numY; j++ {
+				for i := 0; i < numX; i++ {
+					basis := math.Cos(math.Pi*float64(x*i)/float64(width)) *
+						math.Cos(math.Pi*float64(y*j)/float64(height))
+					c := colors[i+j*numX]
+					r += c[0] * basis
+					g += c[1] * basis
+					b += c[2] * basis
+				}
+			}
+			img.SetNRGBA(x, y, color.NRGBA{linearToSRGB(r), linearToSRGB(g), linearToSRGB(b), 255})
+		}
+	}
+	return img, nil
+}
+
+// Encode computes the BlurHash of img, using the given number of horizontal and vertical components.
+// More components keep more detail, at the cost of a longer hash. Unsplash uses 4 by 3.
+func Encode(xComponents, yComponents int, img image.Image) (string, error) {
+	if xComponents < 1 || xComponents > 9 {
+		return "", ErrInvalidComponents(xComponents)
+	}
+	if yComponents < 1 || yComponents > 9 {
+		return "", ErrInvalidComponents(yComponents)
+	}
+	bounds := img.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+	if width == 0 || height == 0 {
+		return "", ErrEmptyImage
+	}
+
+	// convert pixels to linear RGB once, rather than once per component
+	linear := make([][3]float64, width*height)
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			c := color.NRGBAModel.Convert(img.At(bounds.Min.X+x, bounds.Min.Y+y)).(color.NRGBA)
+			linear[x+y*width] = [3]float64{sRGBToLinear(c.R), sRGBToLinear(c.G), sRGBToLinear(c.B)}
+		}
+	}
+
+	factors := make([][3]float64, 0, xComponents*yComponents)
+	for j := 0; j < yComponents; j++ {
+		for i := 0; i < xComponents; i++ {
+			normalisation := 2.0
+			if i == 0 && j == 0 {
+				normalisation = 1
+			}
+			var r, g, b float64
+			for y := 0; y < height; y++ {
+				for x := 0; x < width; x++ {
+					basis := math.Cos(math.Pi*float64(i*x)/float64(width)) *
+						math.Cos(math.Pi*float64(j*y)/float64(height))
+					p := linear[x+y*width]
+					r += basis * p[0]
+					g += basis * p[1]
+					b += basis * p[2]
+				}
+			}
+			scale := normalisation / float64(width*height)
+			factors = append(factors, [3]float64{r * scale, g * scale, b * scale})
+		}
+	}
+
+	hash := encode83((xComponents-1)+(yComponents-1)*9, 1)
+	dc, ac := factors[0], factors[1:]
+	maxValue := 1.0
+	if len(ac) > 0 {
+		var actualMax float64
+		for _, f := range ac {
+			actualMax = math.Max(actualMax, math.Max(math.Abs(f[0]), math.Max(math.Abs(f[1]), math.Abs(f[2]))))
+		}
+		quantisedMax := int(math.Max(0, math.Min(82, math.Floor(actualMax*166-0.5))))
+		maxValue = float64(quantisedMax+1) / 166
+		hash += encode83(quantisedMax, 1)
+	} else {
+		hash += encode83(0, 1)
+	}
+	hash += encode83(encodeDC(dc), 4)
+	for _, f := range ac {
+		hash += encode83(encodeAC(f, maxValue), 2)
+	}
+	return hash, nil
+}
+
+// PNG decodes hash into an image of the given dimensions, and encodes it as a PNG
+func PNG(hash string, width, height int, punch float64) ([]byte, error) {
+	img, err := Decode(hash, width, height, punch)
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// Base64 returns the base64 encoded PNG of the decoded hash
+func Base64(hash string, width, height int, punch float64) (string, error) {
+	data, err := PNG(hash, width, height, punch)
+	if err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(data), nil
+}
+
+// DataURI returns a `data:image/png;base64,` URI of the decoded hash, suitable for inline placeholders
+func DataURI(hash string, width, height int, punch float64) (string, error) {
+	encoded, err := Base64(hash, width, height, punch)
+	if err != nil {
+		return "", err
+	}
+	return "data:image/png;base64," + encoded, nil
+}
+
+// PhotoDataURI returns a placeholder data URI for the Photo, of the given width.
+// The placeholder's height is computed from the Photo's aspect ratio.
+// Since the browser scales the placeholder, a small width, like 32, is usually enough.
+func PhotoDataURI(pic *client.Photo, width int) (string, error) {
+	height := width
+	if pic.Width > 0 && pic.Height > 0 {
+		height = int(math.Round(float64(width) * float64(pic.Height) / float64(pic.Width)))
+		if height < 1 {
+			height = 1
+		}
+	}
+	return DataURI(pic.BlurHash, width, height, 1)
+}
+
+func decodeDC(value int) [3]float64 {
+	return [3]float64{
+		sRGBToLinear(uint8(value >> 16)),
+		sRGBToLinear(uint8(value >> 8 & 255)),
+		sRGBToLinear(uint8(value & 255)),
+	}
+}
+
+func decodeAC(value int, maxValue float64) [3]float64 {
+	quantR := value / (19 * 19)
+	quantG := (value / 19) % 19
+	quantB := value % 19
+	return [3]float64{
+		signPow(float64(quantR-9)/9, 2) * maxValue,
+		signPow(float64(quantG-9)/9, 2) * maxValue,
+		signPow(float64(quantB-9)/9, 2) * maxValue,
+	}
+}
+
+func encodeDC(c [3]float64) int {
+	return int(linearToSRGB(c[0]))<<16 + int(linearToSRGB(c[1]))<<8 + int(linearToSRGB(c[2]))
+}
+
+func encodeAC(c [3]float64, maxValue float64) int {
+	quant := func(v float64) int {
+		return int(math.Max(0, math.Min(18, math.Floor(signPow(v/maxValue, 0.5)*9+9.5))))
+	}
+	return quant(c[0])*19*19 + quant(c[1])*19 + quant(c[2])
+}
+
+func sRGBToLinear(value uint8) float64 {
+	v := float64(value) / 255
+	if v <= 0.04045 {
+		return v / 12.92
+	}
+	return math.Pow((v+0.055)/1.055, 2.4)
+}
+
+func linearToSRGB(value float64) uint8 {
+	v := math.Max(0, math.Min(1, value))
+	if v <= 0.0031308 {
+		return uint8(math.Round(v * 12.92 * 255))
+	}
+	return uint8(math.Round((1.055*math.Pow(v, 1/2.4) - 0.055) * 255))
+}
+
+func signPow(value, exp float64) float64 {
+	return math.Copysign(math.Pow(math.Abs(value), exp), value)
+}
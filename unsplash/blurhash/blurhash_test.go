@@ -0,0 +1,87 @@
+package blurhash
+
+import (
+	"image"
+	"image/color"
+	"strings"
+	"testing"
+)
+
+const testHash = "LEHV6nWB2yk8pyo0adR*.7kCMdnj"
+
+func TestDecode(t *testing.T) {
+	t.Run("decode hash", func(t *testing.T) {
+		img, err := Decode(testHash, 32, 24, 1)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if img.Bounds().Dx() != 32 || img.Bounds().Dy() != 24 {
+			t.Errorf("expected 32x24 image but got %v", img.Bounds())
+		}
+	})
+
+	t.Run("decode then encode keeps components and average color", func(t *testing.T) {
+		img, err := Decode(testHash, 64, 64, 1)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		x, y, err := Components(testHash)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		hash, err := Encode(x, y, img)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if hash[0] != testHash[0] || hash[2:6] != testHash[2:6] || len(hash) != len(testHash) {
+			t.Errorf("expected hash similar to %v but got %v", testHash, hash)
+		}
+	})
+
+	t.Run("invalid hashes", func(t *testing.T) {
+		for _, hash := range []string{"", "LEHV6", testHash[:27], "LEHV6nWB2yk8pyo0adR*.7kCMdn\""} {
+			if _, err := Decode(hash, 4, 4, 1); err == nil {
+				t.Errorf("expected an error decoding %q", hash)
+			}
+		}
+	})
+
+	t.Run("data uri", func(t *testing.T) {
+		uri, err := DataURI(testHash, 8, 8, 1)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !strings.HasPrefix(uri, "data:image/png;base64,") {
+			t.Errorf("unexpected data uri %v", uri)
+		}
+	})
+}
+
+func TestEncode(t *testing.T) {
+	solid := image.NewNRGBA(image.Rect(0, 0, 16, 16))
+	teal := color.NRGBA{12, 38, 38, 255}
+	for y := 0; y < 16; y++ {
+		for x := 0; x < 16; x++ {
+			solid.SetNRGBA(x, y, teal)
+		}
+	}
+
+	hash, err := Encode(4, 3, solid)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	img, err := Decode(hash, 4, 4, 1)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := img.NRGBAAt(2, 2); got != teal {
+		t.Errorf("expected %v but got %v", teal, got)
+	}
+
+	if _, err := Encode(10, 3, solid); err == nil {
+		t.Errorf("expected an error encoding with 10 components")
+	}
+	if _, err := Encode(4, 3, image.NewNRGBA(image.Rect(0, 0, 16, 0))); err != ErrEmptyImage {
+		t.Errorf("expected %v but got %v", ErrEmptyImage, err)
+	}
+}
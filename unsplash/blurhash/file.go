@@ -0,0 +1,25 @@
+package blurhash
+
+import (
+	"image"
+	// register formats Unsplash photos are commonly stored in
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+	"os"
+)
+
+// EncodeFile computes the BlurHash of the image stored at path.
+// JPEG, PNG and GIF images are supported.
+func EncodeFile(path string, xComponents, yComponents int) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	img, _, err := image.Decode(f)
+	if err != nil {
+		return "", err
+	}
+	return Encode(xComponents, yComponents, img)
+}
@@ -0,0 +1,68 @@
+package client
+
+import (
+	"context"
+	"sync"
+)
+
+// Budget bounds the number of API requests made with a context, e.g. by a bulk job, so that
+// the job can't use up more of the hourly rate limit than it was allotted. It's safe for
+// concurrent use.
+type Budget struct {
+	mu    sync.Mutex
+	limit int
+	used  int
+}
+
+// NewBudget constructs a Budget allowing limit requests
+func NewBudget(limit int) *Budget {
+	return &Budget{limit: limit}
+}
+
+// WithBudget returns a context whose API requests are counted against b. A request that
+// would exceed b is refused with an ErrBudgetExceeded, without being sent. Requests are
+// counted whatever their response, and image downloads, which aren't API requests, aren't.
+func WithBudget(ctx context.Context, b *Budget) context.Context {
+	return context.WithValue(ctx, budgetKey{}, b)
+}
+
+// budgetKey is the context key of the Budget set by WithBudget
+type budgetKey struct{}
+
+// Limit returns the number of requests the budget allows
+func (b *Budget) Limit() int {
+	return b.limit
+}
+
+// Used returns the number of requests counted against the budget
+func (b *Budget) Used() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.used
+}
+
+// Remaining returns the number of requests left in the budget
+func (b *Budget) Remaining() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.limit - b.used
+}
+
+// take counts a request against the budget, unless it's used up
+func (b *Budget) take() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.used >= b.limit {
+		return ErrBudgetExceeded(b.limit)
+	}
+	b.used++
+	return nil
+}
+
+// spend counts a request against ctx's Budget, if it has one
+func spend(ctx context.Context) error {
+	if b, ok := ctx.Value(budgetKey{}).(*Budget); ok {
+		return b.take()
+	}
+	return nil
+}
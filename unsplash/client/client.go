@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"io/ioutil"
 	"net/http"
+	"sync"
 )
 
 // QueryParams defines url link parameters
@@ -19,6 +20,9 @@ type Client struct {
 	Config     *Config
 	Private    bool // true if private authentication is required to make requests, default should be false
 	AuthScopes *AuthScopes
+
+	rateLimitMu sync.Mutex
+	rateLimit   RateLimit
 }
 
 // Config sets up configuration details to be used in making requests.
@@ -48,6 +52,28 @@ func New(clientID string, client *http.Client, config *Config) *Client {
 	return &Client{ClientID: clientID, HTTPClient: client, Config: config, Private: false}
 }
 
+// Do sends req with the client's headers, including its credentials, and records the rate limit
+// reported in the response. Unlike the client's other methods, it returns the response whatever
+// its status code, and the caller must close its body. It's meant for requests the client has no
+// method for, e.g. requests forwarded by a proxy.
+func (c *Client) Do(req *http.Request) (*http.Response, error) {
+	if req.Header == nil {
+		req.Header = make(http.Header)
+	}
+	for k, v := range c.Config.Headers {
+		req.Header[k] = append([]string(nil), v...)
+	}
+	if err := spend(req.Context()); err != nil {
+		return nil, err
+	}
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	c.recordRateLimit(resp)
+	return resp, nil
+}
+
 // Client http methods to get data from the API using a context
 
 func (c *Client) getHTTP(ctx context.Context, link string) (*http.Response, error) {
@@ -57,14 +83,19 @@ func (c *Client) getHTTP(ctx context.Context, link string) (*http.Response, erro
 	if err != nil {
 		return nil, err
 	}
+	if err := spend(ctx); err != nil {
+		return nil, err
+	}
 	resp, err := c.HTTPClient.Do(req)
 	if err != nil {
 		return nil, err
 	}
+	c.recordRateLimit(resp)
 
 	if resp.StatusCode != http.StatusOK {
 		return nil, ErrStatusCode{resp.StatusCode, getErrReasons(resp)}
 	}
+	recordTotal(ctx, resp)
 	return resp, nil
 }
 
@@ -79,10 +110,14 @@ func (c *Client) postHTTP(ctx context.Context, link string, postData map[string]
 	if err != nil {
 		return nil, err
 	}
+	if err := spend(ctx); err != nil {
+		return nil, err
+	}
 	resp, err := c.HTTPClient.Do(req)
 	if err != nil {
 		return nil, err
 	}
+	c.recordRateLimit(resp)
 
 	if resp.StatusCode != http.StatusCreated {
 		return nil, ErrStatusCode{resp.StatusCode, getErrReasons(resp)}
@@ -101,14 +136,16 @@ func (c *Client) putHTTP(ctx context.Context, link string, putData map[string]st
 	if err != nil {
 		return nil, err
 	}
+	if err := spend(ctx); err != nil {
+		return nil, err
+	}
 	resp, err := c.HTTPClient.Do(req)
 	if err != nil {
 		return nil, err
 	}
+	c.recordRateLimit(resp)
 
-	if resp.StatusCode != http.StatusOK {
-		return nil, ErrStatusCode{resp.StatusCode, getErrReasons(resp)}
-	} else if resp.StatusCode != http.StatusCreated {
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
 		return nil, ErrStatusCode{resp.StatusCode, getErrReasons(resp)}
 	}
 	return resp, nil
@@ -125,14 +162,16 @@ func (c *Client) deleteHTTP(ctx context.Context, link string, dt map[string]stri
 	if err != nil {
 		return nil, err
 	}
+	if err := spend(ctx); err != nil {
+		return nil, err
+	}
 	resp, err := c.HTTPClient.Do(req)
 	if err != nil {
 		return nil, err
 	}
+	c.recordRateLimit(resp)
 
-	if resp.StatusCode != http.StatusOK {
-		return nil, ErrStatusCode{resp.StatusCode, getErrReasons(resp)}
-	} else if resp.StatusCode != http.StatusNoContent {
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNoContent {
 		return nil, ErrStatusCode{resp.StatusCode, getErrReasons(resp)}
 	}
 	return resp, nil
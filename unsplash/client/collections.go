@@ -2,6 +2,7 @@ package client
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 )
 
@@ -24,6 +25,29 @@ type Collection struct {
 		HTML   string `json:"html"`
 		Photos string `json:"photos"`
 	} `json:"links"`
+	Tags          []Tag          `json:"tags"`
+	PreviewPhotos []PhotoPreview `json:"preview_photos"`
+	// Raw holds the JSON the Collection was parsed from, including fields not defined in the struct,
+	// unless the Collection is nested in another resource. It isn't encoded to JSON.
+	Raw json.RawMessage `json:"-"`
+}
+
+// UnmarshalJSON parses a Collection, retaining the JSON data in Collection.Raw.
+// Nested resources don't keep a copy of their part of it.
+func (col *Collection) UnmarshalJSON(data []byte) error {
+	type collection Collection
+	if err := json.Unmarshal(data, (*collection)(col)); err != nil {
+		return err
+	}
+	col.Raw = append(json.RawMessage(nil), data...)
+	col.CoverPhoto.Raw, col.User.Raw = nil, nil
+	return nil
+}
+
+// Field parses the JSON field with the given name into v.
+// Use it to get fields returned by the API that are not yet defined in Collection.
+func (col *Collection) Field(name string, v interface{}) error {
+	return unmarshalField(col.Raw, name, v)
 }
 
 // CollectionActionResponse defines the fields returned on adding a photo to a collection
@@ -0,0 +1,190 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+)
+
+// ProgressFunc is called as a photo download progresses, with the number of bytes
+// written so far and the total number of bytes expected.
+// total is -1 if the size of the download is not known.
+type ProgressFunc func(written, total int64)
+
+// DownloadResponse defines the response returned on tracking a photo download
+type DownloadResponse struct {
+	URL string `json:"url"`
+}
+
+// TrackDownload takes in a context and a photo, and triggers a download event on the photo
+// by requesting its `links.download_location`. Returns the photo's download URL.
+// From the API guidelines, this must be done whenever a photo is downloaded by a user
+// of the application.
+// https://unsplash.com/documentation#track-a-photo-download
+func (c *Client) TrackDownload(ctx context.Context, pic *Photo) (*DownloadResponse, error) {
+	if pic.Links.DownloadLocation == "" {
+		return nil, ErrDownloadLocationEmpty(pic.ID)
+	}
+	data, err := c.getBodyBytes(ctx, pic.Links.DownloadLocation)
+	if err != nil {
+		return nil, err
+	}
+	var dr DownloadResponse
+	err = parseJSON(data, &dr)
+	if err != nil {
+		return nil, err
+	}
+	return &dr, nil
+}
+
+// DownloadPhoto takes in a context, a photo, the image link to download and a writer.
+// The download is first tracked using TrackDownload, after which the image found at link
+// is streamed to w. If link is empty, the photo's full size image, `urls.full`, is downloaded.
+// progress, if not nil, is called after every write to w.
+// Returns the number of bytes written to w.
+func (c *Client) DownloadPhoto(ctx context.Context, pic *Photo, link string, w io.Writer, progress ProgressFunc) (int64, error) {
+	if _, err := c.TrackDownload(ctx, pic); err != nil {
+		return 0, err
+	}
+	if link == "" {
+		link = pic.URLs.Full
+	}
+	resp, err := c.getImageHTTP(ctx, link, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	return copyWithProgress(ctx, w, resp.Body, 0, resp.ContentLength, progress)
+}
+
+// DownloadPhotoToFile works like DownloadPhoto, writing the downloaded image to the file at path.
+// The file is created if it does not exist, and truncated if it does.
+func (c *Client) DownloadPhotoToFile(ctx context.Context, pic *Photo, link, path string, progress ProgressFunc) (int64, error) {
+	f, err := os.Create(path)
+	if err != nil {
+		return 0, err
+	}
+	n, err := c.DownloadPhoto(ctx, pic, link, f, progress)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		os.Remove(path)
+		return n, err
+	}
+	return n, nil
+}
+
+// ResumePhotoDownloadToFile works like DownloadPhotoToFile, but if a file already exists at path,
+// it is treated as a partially downloaded image, and only the remaining bytes are requested
+// using a HTTP Range request.
+// If the image server does not honour the range, the file is overwritten with the whole image.
+// Unlike DownloadPhotoToFile, the file is kept if the download fails, for it to be resumed later.
+// Returns the size of the downloaded file.
+func (c *Client) ResumePhotoDownloadToFile(ctx context.Context, pic *Photo, link, path string, progress ProgressFunc) (int64, error) {
+	var offset int64
+	if info, err := os.Stat(path); err == nil {
+		offset = info.Size()
+	}
+
+	if _, err := c.TrackDownload(ctx, pic); err != nil {
+		return 0, err
+	}
+	if link == "" {
+		link = pic.URLs.Full
+	}
+	headers := make(http.Header)
+	if offset > 0 {
+		headers.Set("Range", fmt.Sprintf("bytes=%d-", offset))
+	}
+	resp, err := c.getImageHTTP(ctx, link, headers)
+	if e, ok := err.(ErrStatusCode); ok && e.statusCode == http.StatusRequestedRangeNotSatisfiable {
+		// the file already holds the whole image
+		return offset, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	flag := os.O_WRONLY | os.O_CREATE | os.O_APPEND
+	if resp.StatusCode != http.StatusPartialContent {
+		// no range requested, or range ignored: the whole image is being sent
+		flag = os.O_WRONLY | os.O_CREATE | os.O_TRUNC
+		offset = 0
+	}
+	f, err := os.OpenFile(path, flag, 0644)
+	if err != nil {
+		return 0, err
+	}
+	n, err := copyWithProgress(ctx, f, resp.Body, offset, resp.ContentLength, progress)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	return offset + n, err
+}
+
+// getImageHTTP requests an image from Unsplash's image servers.
+// Unlike getHTTP, API headers are not sent with the request since the images are not
+// served by the API.
+func (c *Client) getImageHTTP(ctx context.Context, link string, headers http.Header) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, link, nil)
+	if err != nil {
+		return nil, err
+	}
+	for key, vals := range headers {
+		for _, val := range vals {
+			req.Header.Add(key, val)
+		}
+	}
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusPartialContent {
+		defer resp.Body.Close()
+		return nil, ErrStatusCode{resp.StatusCode, []string{http.StatusText(resp.StatusCode)}}
+	}
+	return resp, nil
+}
+
+// copyWithProgress copies src to dst in chunks, stopping if the context is cancelled.
+// offset is the number of bytes already written before the copy started, and is
+// included in what is reported to progress.
+func copyWithProgress(ctx context.Context, dst io.Writer, src io.Reader, offset, size int64, progress ProgressFunc) (int64, error) {
+	total := int64(-1)
+	if size >= 0 {
+		total = offset + size
+	}
+	buf := make([]byte, 32*1024)
+	var written int64
+	for {
+		if err := ctx.Err(); err != nil {
+			return written, err
+		}
+		nr, rerr := src.Read(buf)
+		if nr > 0 {
+			nw, werr := dst.Write(buf[:nr])
+			written += int64(nw)
+			if progress != nil {
+				progress(offset+written, total)
+			}
+			if werr != nil {
+				return written, werr
+			}
+			if nw != nr {
+				return written, io.ErrShortWrite
+			}
+		}
+		if rerr == io.EOF {
+			return written, nil
+		}
+		if rerr != nil {
+			return written, rerr
+		}
+	}
+}
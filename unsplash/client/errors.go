@@ -28,18 +28,42 @@ var (
 // ErrQueryNotInURL is raised when a search query parameter is not part of the url.
 type ErrQueryNotInURL string
 
+// ErrDownloadLocationEmpty is raised when a download is tracked on a photo
+// whose `links.download_location` is empty. It holds the photo's ID.
+type ErrDownloadLocationEmpty string
+
+// ErrFieldNotFound is raised when a field is not found in a resource's raw JSON data.
+// It holds the field's name.
+type ErrFieldNotFound string
+
 // ErrRequiredScopeAbsent is raised on trying to access a private action
 // when the required scope is not provided or allowed from the authenticated user's endd.
 type ErrRequiredScopeAbsent string
 
+// ErrBudgetExceeded is raised when a request would exceed its context's Budget.
+// It holds the budget's limit.
+type ErrBudgetExceeded int
+
 func (e ErrQueryNotInURL) Error() string {
 	return "search query parameter absent in url: " + string(e)
 }
 
+func (e ErrDownloadLocationEmpty) Error() string {
+	return "download location absent in photo: " + string(e)
+}
+
+func (e ErrFieldNotFound) Error() string {
+	return "field not found in resource: " + string(e)
+}
+
 func (e ErrRequiredScopeAbsent) Error() string {
 	return "required scope `%v` not in client auth scopes"
 }
 
+func (e ErrBudgetExceeded) Error() string {
+	return fmt.Sprintf("request budget of %d requests used up", int(e))
+}
+
 // ErrStatusCode defines a http status code error
 // with the status code and tthe reasons for the error
 type ErrStatusCode struct {
@@ -51,6 +75,16 @@ func (e ErrStatusCode) Error() string {
 	return fmt.Sprintf("unexpected status code: %d\n encountered errors: %v", e.statusCode, e.reasons)
 }
 
+// StatusCode returns the http status code the API responded with
+func (e ErrStatusCode) StatusCode() int {
+	return e.statusCode
+}
+
+// Reasons returns the errors the API gave for the status code
+func (e ErrStatusCode) Reasons() []string {
+	return e.reasons
+}
+
 func getErrReasons(resp *http.Response) []string {
 	var otherErrs []error
 	data, err := ioutil.ReadAll(resp.Body)
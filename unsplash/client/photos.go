@@ -1,6 +1,9 @@
 package client
 
-import "context"
+import (
+	"context"
+	"encoding/json"
+)
 
 // Photo defines fields in a photo resource
 type Photo struct {
@@ -18,6 +21,7 @@ type Photo struct {
 	Description    string `json:"description"`
 	AltDescription string `json:"alt_description"`
 	Exif           struct {
+		Name         string `json:"name"`
 		Make         string `json:"make"`
 		Model        string `json:"model"`
 		ExposureTime string `json:"exposure_time"`
@@ -26,6 +30,7 @@ type Photo struct {
 		ISO          int    `json:"iso"`
 	} `json:"exif"`
 	Location struct {
+		Name     string `json:"name"`
 		City     string `json:"city"`
 		Country  string `json:"country"`
 		Position struct {
@@ -34,8 +39,16 @@ type Photo struct {
 		} `json:"position"`
 	} `json:"location"`
 	Tags                   []Tag        `json:"tags"`
+	Topics                 []Topic      `json:"topics"`
 	CurrentUserCollections []Collection `json:"current_user_collections"`
-	URLs                   struct {
+	RelatedCollections     struct {
+		Total   int          `json:"total"`
+		Type    string       `json:"type"`
+		Results []Collection `json:"results"`
+	} `json:"related_collections"`
+	Sponsorship      *Sponsorship               `json:"sponsorship"`
+	TopicSubmissions map[string]TopicSubmission `json:"topic_submissions"`
+	URLs             struct {
 		Raw     string `json:"raw"`
 		Full    string `json:"full"`
 		Regular string `json:"regular"`
@@ -54,13 +67,77 @@ type Photo struct {
 		Views     Stats `json:"views"`
 		Likes     Stats `json:"likes"`
 	} `json:"statistics"`
+	// Raw holds the JSON the Photo was parsed from, including fields not defined in the struct,
+	// unless the Photo is nested in another resource. It isn't encoded to JSON.
+	Raw json.RawMessage `json:"-"`
+}
+
+// UnmarshalJSON parses a Photo, retaining the JSON data in Photo.Raw.
+// Nested resources don't keep a copy of their part of it.
+func (p *Photo) UnmarshalJSON(data []byte) error {
+	type photo Photo
+	if err := json.Unmarshal(data, (*photo)(p)); err != nil {
+		return err
+	}
+	p.Raw = append(json.RawMessage(nil), data...)
+	p.User.Raw = nil
+	for i := range p.Topics {
+		p.Topics[i].Raw = nil
+	}
+	for i := range p.CurrentUserCollections {
+		p.CurrentUserCollections[i].Raw = nil
+	}
+	for i := range p.RelatedCollections.Results {
+		p.RelatedCollections.Results[i].Raw = nil
+	}
+	if p.Sponsorship != nil {
+		p.Sponsorship.Sponsor.Raw = nil
+	}
+	return nil
+}
+
+// Field parses the JSON field with the given name into v.
+// Use it to get fields returned by the API that are not yet defined in Photo.
+func (p *Photo) Field(name string, v interface{}) error {
+	return unmarshalField(p.Raw, name, v)
 }
 
 // Tag defines fields in a photo's tag
 type Tag struct {
+	Type  string `json:"type"`
 	Title string `json:"title"`
 }
 
+// Sponsorship defines fields describing a sponsored photo
+type Sponsorship struct {
+	ImpressionURLs []string `json:"impression_urls"`
+	Tagline        string   `json:"tagline"`
+	TaglineURL     string   `json:"tagline_url"`
+	Sponsor        User     `json:"sponsor"`
+}
+
+// TopicSubmission defines the status of a photo's submission to a topic
+type TopicSubmission struct {
+	Status     string `json:"status"`
+	ApprovedOn string `json:"approved_on"`
+}
+
+// PhotoPreview defines the abbreviated photo returned in previews of
+// a user's or collection's photos
+type PhotoPreview struct {
+	ID        string `json:"id"`
+	CreatedAt string `json:"created_at"`
+	UpdatedAt string `json:"updated_at"`
+	BlurHash  string `json:"blur_hash"`
+	URLs      struct {
+		Raw     string `json:"raw"`
+		Full    string `json:"full"`
+		Regular string `json:"regular"`
+		Small   string `json:"small"`
+		Thumb   string `json:"thumb"`
+	} `json:"urls"`
+}
+
 // GetPhotoList takes in a context and query parameters to return a list(given page, default first) of all
 // Unsplash photos.
 // Get a single page with a list of all photos
@@ -3,6 +3,7 @@ package client
 import (
 	"context"
 	"io/ioutil"
+	"net/http"
 )
 
 /*
@@ -296,15 +297,15 @@ func (c *Client) RemovePhotoFromCollection(ctx context.Context, collectionID str
 		return nil, ErrRequiredScopeAbsent(WriteCollectionsScope)
 	}
 	// make DELETE request
-	// responds with a 204 status code and an empty body
+	// responds with the collection and photo, or a 204 status code and an empty body
 	endPoint := CollectionsListEndpoint + collectionID + "/remove"
 	resp, err := c.deleteHTTP(ctx, endPoint, data)
 	if err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close()
-	if resp.StatusCode != 204 {
-		return nil, ErrStatusCode{resp.StatusCode, getErrReasons(resp)}
+	if resp.StatusCode == http.StatusNoContent {
+		return &CollectionActionResponse{}, nil
 	}
 
 	// parse json response
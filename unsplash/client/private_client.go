@@ -45,6 +45,18 @@ var unsplashEndpoint = oauth2.Endpoint{
 	TokenURL: AuthTokenEndpoint,
 }
 
+// NewPrivateClient initializes a new client authorised for private actions using
+// an access token obtained earlier, e.g. one stored after NewPrivateAuthClient.
+// as should list the scopes the token was granted.
+func NewPrivateClient(clientID string, tok *oauth2.Token, as *AuthScopes, config *Config) *Client {
+	client := oauth2.NewClient(context.Background(), oauth2.StaticTokenSource(tok))
+	return &Client{ClientID: clientID,
+		HTTPClient: client,
+		Config:     config,
+		Private:    true,
+		AuthScopes: as}
+}
+
 // NewPrivateAuthClient initializes a new client that has been authorised
 // for private actions.
 func NewPrivateAuthClient(clientID, clientSecret, redirectURI string, as *AuthScopes, config *Config) (*Client, error) {
@@ -0,0 +1,38 @@
+package client
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// RateLimit holds the rate limit state reported by the API in the `X-Ratelimit-Limit`
+// and `X-Ratelimit-Remaining` response headers
+type RateLimit struct {
+	Limit     int       `json:"limit"`     // requests allowed per hour
+	Remaining int       `json:"remaining"` // requests left in the current hour
+	Time      time.Time `json:"time"`      // when the response reporting the state was received
+}
+
+// RateLimit returns the rate limit state reported in the last API response.
+// ok is false if no response with rate limit headers has been received yet.
+func (c *Client) RateLimit() (rl RateLimit, ok bool) {
+	c.rateLimitMu.Lock()
+	defer c.rateLimitMu.Unlock()
+	return c.rateLimit, !c.rateLimit.Time.IsZero()
+}
+
+// recordRateLimit saves the rate limit state reported in resp's headers, if any
+func (c *Client) recordRateLimit(resp *http.Response) {
+	limit, err := strconv.Atoi(resp.Header.Get("X-Ratelimit-Limit"))
+	if err != nil {
+		return
+	}
+	remaining, err := strconv.Atoi(resp.Header.Get("X-Ratelimit-Remaining"))
+	if err != nil {
+		return
+	}
+	c.rateLimitMu.Lock()
+	defer c.rateLimitMu.Unlock()
+	c.rateLimit = RateLimit{Limit: limit, Remaining: remaining, Time: time.Now()}
+}
@@ -81,7 +81,7 @@ func (c *Client) GetStatsTotal(ctx context.Context) (*StatsTotal, error) {
 // Get the overall Unsplash stats for the past 30 days.
 // https://unsplash.com/documentation#month
 func (c *Client) GetStatsMonth(ctx context.Context) (*StatsMonth, error) {
-	data, err := c.getBodyBytes(ctx, StatsTotalEndpoint)
+	data, err := c.getBodyBytes(ctx, StatsMonthEndpoint)
 	if err != nil {
 		return nil, err
 	}
@@ -1,6 +1,9 @@
 package client
 
-import "context"
+import (
+	"context"
+	"encoding/json"
+)
 
 // Topic defines fields in an Unsplash topic
 type Topic struct {
@@ -14,6 +17,7 @@ type Topic struct {
 	EndsAt      string `json:"ends_at"`
 	Featured    bool   `json:"featured"`
 	TotalPhotos int    `json:"total_photos"`
+	Visibility  string `json:"visibility"`
 	Links       struct {
 		Self   string `json:"self"`
 		HTML   string `json:"html"`
@@ -26,6 +30,37 @@ type Topic struct {
 	TotalCurrentUserSubmissions int     `json:"total_current_user_submissions"`
 	CoverPhoto                  Photo   `json:"cover_photo"`
 	PreviewPhotos               []Photo `json:"preview_photos"`
+	// Raw holds the JSON the Topic was parsed from, including fields not defined in the struct,
+	// unless the Topic is nested in another resource. It isn't encoded to JSON.
+	Raw json.RawMessage `json:"-"`
+}
+
+// UnmarshalJSON parses a Topic, retaining the JSON data in Topic.Raw.
+// Nested resources don't keep a copy of their part of it.
+func (t *Topic) UnmarshalJSON(data []byte) error {
+	type topic Topic
+	if err := json.Unmarshal(data, (*topic)(t)); err != nil {
+		return err
+	}
+	t.Raw = append(json.RawMessage(nil), data...)
+	t.CoverPhoto.Raw = nil
+	for _, users := range [][]User{t.Owners, t.TopContributors} {
+		for i := range users {
+			users[i].Raw = nil
+		}
+	}
+	for _, pics := range [][]Photo{t.CurrentUserContributions, t.PreviewPhotos} {
+		for i := range pics {
+			pics[i].Raw = nil
+		}
+	}
+	return nil
+}
+
+// Field parses the JSON field with the given name into v.
+// Use it to get fields returned by the API that are not yet defined in Topic.
+func (t *Topic) Field(name string, v interface{}) error {
+	return unmarshalField(t.Raw, name, v)
 }
 
 // GetTopicsList takes a context and query parameters, returns a slice of Topic objects.
@@ -0,0 +1,28 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+)
+
+// totalKey is the context key of the int WithTotal records the total number of results in
+type totalKey struct{}
+
+// WithTotal returns a context recording in total the number of results that list endpoints, e.g.
+// GetUserPhotos, report in the `X-Total` response header. total is left unchanged by responses
+// without the header. Use a separate context for concurrent requests.
+func WithTotal(ctx context.Context, total *int) context.Context {
+	return context.WithValue(ctx, totalKey{}, total)
+}
+
+// recordTotal saves the total number of results reported in resp's headers, if ctx asks for it
+func recordTotal(ctx context.Context, resp *http.Response) {
+	total, ok := ctx.Value(totalKey{}).(*int)
+	if !ok {
+		return
+	}
+	if n, err := strconv.Atoi(resp.Header.Get("X-Total")); err == nil {
+		*total = n
+	}
+}
@@ -2,6 +2,7 @@ package client
 
 import (
 	"context"
+	"encoding/json"
 	"net/url"
 )
 
@@ -48,6 +49,36 @@ type User struct {
 		Following string `json:"following"`
 		Followers string `json:"followers"`
 	} `json:"links"`
+	Social struct {
+		InstagramUsername string `json:"instagram_username"`
+		PortfolioURL      string `json:"portfolio_url"`
+		TwitterUsername   string `json:"twitter_username"`
+		PaypalEmail       string `json:"paypal_email"`
+	} `json:"social"`
+	Photos []PhotoPreview `json:"photos"`
+	Tags   struct {
+		Custom     []Tag `json:"custom"`
+		Aggregated []Tag `json:"aggregated"`
+	} `json:"tags"`
+	// Raw holds the JSON the User was parsed from, including fields not defined in the struct,
+	// unless the User is nested in another resource. It isn't encoded to JSON.
+	Raw json.RawMessage `json:"-"`
+}
+
+// UnmarshalJSON parses a User, retaining the JSON data in User.Raw
+func (u *User) UnmarshalJSON(data []byte) error {
+	type user User
+	if err := json.Unmarshal(data, (*user)(u)); err != nil {
+		return err
+	}
+	u.Raw = append(json.RawMessage(nil), data...)
+	return nil
+}
+
+// Field parses the JSON field with the given name into v.
+// Use it to get fields returned by the API that are not yet defined in User.
+func (u *User) Field(name string, v interface{}) error {
+	return unmarshalField(u.Raw, name, v)
 }
 
 // LikeResponse defines the struct returned on liking and unliking photos
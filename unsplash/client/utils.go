@@ -13,6 +13,22 @@ func parseJSON(data []byte, desiredObject interface{}) error {
 	return nil
 }
 
+// unmarshalField parses the field with the given name, in the raw JSON object, into v
+func unmarshalField(raw json.RawMessage, name string, v interface{}) error {
+	if raw == nil {
+		return ErrFieldNotFound(name)
+	}
+	var fields map[string]json.RawMessage
+	if err := parseJSON(raw, &fields); err != nil {
+		return err
+	}
+	field, ok := fields[name]
+	if !ok {
+		return ErrFieldNotFound(name)
+	}
+	return parseJSON(field, v)
+}
+
 func buildURL(link string, queryParams QueryParams) (string, error) {
 	// add query params to request
 	if queryParams != nil {
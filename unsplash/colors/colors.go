@@ -0,0 +1,225 @@
+// Package colors works with the dominant colors Unsplash returns for photos in Photo.Color.
+//
+// Colors can be parsed, classified into the color names accepted by photo search's `color`
+// query parameter, checked for contrast, and used to rank photos by how close their
+// colors are to a target color.
+package colors
+
+import (
+	"fmt"
+	"image/color"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/eddogola/unsplash-go/unsplash/client"
+)
+
+// Color names accepted by the `color` query parameter when searching photos
+// https://unsplash.com/documentation#search-photos
+const (
+	BlackAndWhite = "black_and_white"
+	Black         = "black"
+	White         = "white"
+	Yellow        = "yellow"
+	Orange        = "orange"
+	Red           = "red"
+	Purple        = "purple"
+	Magenta       = "magenta"
+	Green         = "green"
+	Teal          = "teal"
+	Blue          = "blue"
+)
+
+var (
+	black = color.RGBA{0, 0, 0, 255}
+	white = color.RGBA{255, 255, 255, 255}
+)
+
+// ErrInvalidColor is raised when a color is not a valid hex color
+type ErrInvalidColor string
+
+func (e ErrInvalidColor) Error() string {
+	return fmt.Sprintf("invalid hex color: %q", string(e))
+}
+
+// Parse parses a hex color like `#0c2626`, as found in Photo.Color.
+// The leading `#` is optional, and the 3 digit shorthand is supported.
+func Parse(hex string) (color.RGBA, error) {
+	h := strings.TrimPrefix(strings.TrimSpace(hex), "#")
+	if len(h) == 3 {
+		h = string([]byte{h[0], h[0], h[1], h[1], h[2], h[2]})
+	}
+	if len(h) != 6 {
+		return color.RGBA{}, ErrInvalidColor(hex)
+	}
+	v, err := strconv.ParseUint(h, 16, 32)
+	if err != nil {
+		return color.RGBA{}, ErrInvalidColor(hex)
+	}
+	return color.RGBA{uint8(v >> 16), uint8(v >> 8), uint8(v), 255}, nil
+}
+
+// Hex formats c as a hex color like `#0c2626`
+func Hex(c color.Color) string {
+	rgba := toRGBA(c)
+	return fmt.Sprintf("#%02x%02x%02x", rgba.R, rgba.G, rgba.B)
+}
+
+// Name classifies c into one of the color names accepted by photo search
+func Name(c color.Color) string {
+	h, s, l := hsl(toRGBA(c))
+	switch {
+	case l < 0.1:
+		return Black
+	case l > 0.92:
+		return White
+	case s < 0.15:
+		if l < 0.25 {
+			return Black
+		}
+		if l > 0.85 {
+			return White
+		}
+		return BlackAndWhite
+	}
+	switch {
+	case h < 15 || h >= 345:
+		return Red
+	case h < 45:
+		return Orange
+	case h < 70:
+		return Yellow
+	case h < 165:
+		return Green
+	case h < 195:
+		return Teal
+	case h < 255:
+		return Blue
+	case h < 290:
+		return Purple
+	default:
+		return Magenta
+	}
+}
+
+// PhotoColor parses the Photo's dominant color
+func PhotoColor(pic *client.Photo) (color.RGBA, error) {
+	return Parse(pic.Color)
+}
+
+// Luminance returns the relative luminance of c, from 0 for black to 1 for white,
+// as defined by WCAG.
+// https://www.w3.org/TR/WCAG21/#dfn-relative-luminance
+func Luminance(c color.Color) float64 {
+	rgba := toRGBA(c)
+	return 0.2126*linear(rgba.R) + 0.7152*linear(rgba.G) + 0.0722*linear(rgba.B)
+}
+
+// ContrastRatio returns the WCAG contrast ratio between two colors, from 1 to 21.
+// WCAG AA requires a ratio of at least 4.5 for normal text.
+// https://www.w3.org/TR/WCAG21/#dfn-contrast-ratio
+func ContrastRatio(a, b color.Color) float64 {
+	la, lb := Luminance(a), Luminance(b)
+	if la < lb {
+		la, lb = lb, la
+	}
+	return (la + 0.05) / (lb + 0.05)
+}
+
+// TextColor returns black or white, whichever is more readable on the background color bg
+func TextColor(bg color.Color) color.RGBA {
+	if ContrastRatio(bg, black) >= ContrastRatio(bg, white) {
+		return black
+	}
+	return white
+}
+
+// Distance returns the perceptual distance between two colors, as the CIE76 difference
+// between the colors in the CIELAB color space. A distance of about 2.3 is barely noticeable.
+func Distance(a, b color.Color) float64 {
+	l1, a1, b1 := lab(toRGBA(a))
+	l2, a2, b2 := lab(toRGBA(b))
+	return math.Sqrt((l1-l2)*(l1-l2) + (a1-a2)*(a1-a2) + (b1-b2)*(b1-b2))
+}
+
+// RankByColor returns a copy of pics sorted by how close each photo's color is to target,
+// closest first. Photos without a valid color are placed last.
+func RankByColor(pics []client.Photo, target color.Color) []client.Photo {
+	distances := make([]float64, len(pics))
+	indices := make([]int, len(pics))
+	for i := range pics {
+		indices[i] = i
+		c, err := Parse(pics[i].Color)
+		if err != nil {
+			distances[i] = math.Inf(1)
+			continue
+		}
+		distances[i] = Distance(c, target)
+	}
+	sort.SliceStable(indices, func(i, j int) bool {
+		return distances[indices[i]] < distances[indices[j]]
+	})
+	ranked := make([]client.Photo, len(pics))
+	for i, idx := range indices {
+		ranked[i] = pics[idx]
+	}
+	return ranked
+}
+
+func toRGBA(c color.Color) color.RGBA {
+	return color.RGBAModel.Convert(c).(color.RGBA)
+}
+
+// linear converts an sRGB channel value to linear light
+func linear(v uint8) float64 {
+	c := float64(v) / 255
+	if c <= 0.04045 {
+		return c / 12.92
+	}
+	return math.Pow((c+0.055)/1.055, 2.4)
+}
+
+// hsl converts c to hue in degrees, saturation and lightness
+func hsl(c color.RGBA) (h, s, l float64) {
+	r, g, b := float64(c.R)/255, float64(c.G)/255, float64(c.B)/255
+	max := math.Max(r, math.Max(g, b))
+	min := math.Min(r, math.Min(g, b))
+	l = (max + min) / 2
+	d := max - min
+	if d == 0 {
+		return 0, 0, l
+	}
+	s = d / (1 - math.Abs(2*l-1))
+	switch max {
+	case r:
+		h = math.Mod((g-b)/d, 6)
+	case g:
+		h = (b-r)/d + 2
+	default:
+		h = (r-g)/d + 4
+	}
+	h *= 60
+	if h < 0 {
+		h += 360
+	}
+	return h, s, l
+}
+
+// lab converts c to the CIELAB color space, using the D65 white point
+func lab(c color.RGBA) (l, a, b float64) {
+	r, g, bl := linear(c.R), linear(c.G), linear(c.B)
+	x := (0.4124*r + 0.3576*g + 0.1805*bl) / 0.95047
+	y := 0.2126*r + 0.7152*g + 0.0722*bl
+	z := (0.0193*r + 0.1192*g + 0.9505*bl) / 1.08883
+
+	f := func(t float64) float64 {
+		if t > 216.0/24389 {
+			return math.Cbrt(t)
+		}
+		return (24389.0/27*t + 16) / 116
+	}
+	fx, fy, fz := f(x), f(y), f(z)
+	return 116*fy - 16, 500 * (fx - fy), 200 * (fy - fz)
+}
@@ -0,0 +1,81 @@
+package colors
+
+import (
+	"image/color"
+	"testing"
+
+	"github.com/eddogola/unsplash-go/unsplash/client"
+)
+
+func TestColors(t *testing.T) {
+	t.Run("parse", func(t *testing.T) {
+		got, err := Parse("#0c2626")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if expected := (color.RGBA{12, 38, 38, 255}); got != expected {
+			t.Errorf("expected %v but got %v", expected, got)
+		}
+		if Hex(got) != "#0c2626" {
+			t.Errorf("expected #0c2626 but got %v", Hex(got))
+		}
+		for _, invalid := range []string{"", "#12345", "#gggggg"} {
+			if _, err := Parse(invalid); err == nil {
+				t.Errorf("expected an error parsing %q", invalid)
+			}
+		}
+	})
+
+	t.Run("names", func(t *testing.T) {
+		cases := map[string]string{
+			"#000000": Black,
+			"#ffffff": White,
+			"#808080": BlackAndWhite,
+			"#0c2626": Black,
+			"#1f7a7a": Teal,
+			"#e67e22": Orange,
+			"#c0392b": Red,
+			"#f1c40f": Yellow,
+			"#27ae60": Green,
+			"#2980b9": Blue,
+			"#8e44ad": Purple,
+			"#d63384": Magenta,
+		}
+		for hex, expected := range cases {
+			c, _ := Parse(hex)
+			if got := Name(c); got != expected {
+				t.Errorf("expected %v to be %v but got %v", hex, expected, got)
+			}
+		}
+	})
+
+	t.Run("contrast", func(t *testing.T) {
+		if ratio := ContrastRatio(black, white); ratio < 20.9 || ratio > 21.1 {
+			t.Errorf("expected a ratio of 21 but got %v", ratio)
+		}
+		dark, _ := Parse("#0c2626")
+		if TextColor(dark) != white {
+			t.Errorf("expected white text on %v", dark)
+		}
+		light, _ := Parse("#f1c40f")
+		if TextColor(light) != black {
+			t.Errorf("expected black text on %v", light)
+		}
+	})
+
+	t.Run("rank photos by color", func(t *testing.T) {
+		pics := []client.Photo{
+			{ID: "blue", Color: "#2980b9"},
+			{ID: "none", Color: ""},
+			{ID: "red", Color: "#c0392b"},
+			{ID: "orange", Color: "#e67e22"},
+		}
+		ranked := RankByColor(pics, color.RGBA{255, 0, 0, 255})
+		expected := []string{"red", "orange", "blue", "none"}
+		for i, id := range expected {
+			if ranked[i].ID != id {
+				t.Errorf("expected %v at position %d but got %v", id, i, ranked[i].ID)
+			}
+		}
+	})
+}
@@ -0,0 +1,244 @@
+// Package cost estimates the API requests bulk operations make, before they're run, and compares
+// them with the hourly rate limit.
+//
+// Operations paginating a resource are estimated from the resource's totals, e.g. a user's
+// `total_photos`, and searches from their `total_pages`, each costing one request to look up:
+//
+//	p := cost.NewPlanner(c)
+//	photos, err := p.UserPhotos(ctx, "jane")
+//	plan := p.Plan(photos, cost.Hydrate(photos.Items))
+//	fmt.Print(plan)
+//	if plan.Fits() {
+//		ctx = client.WithBudget(ctx, plan.Budget())
+//		// run the job with ctx; the client refuses requests beyond the plan
+//	}
+package cost
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/eddogola/unsplash-go/unsplash/client"
+)
+
+// DefaultPerPage is the number of results per page operations are assumed to request,
+// the most the API allows
+const DefaultPerPage = 30
+
+// DefaultHourlyLimit is the hourly rate limit assumed before the API has reported it,
+// the limit of apps in demo mode
+const DefaultHourlyLimit = 50
+
+// Client defines client methods used to look up the totals operations are estimated from
+type Client interface {
+	GetUserPublicProfile(context.Context, string) (*client.User, error)
+	GetCollection(context.Context, string) (*client.Collection, error)
+	GetTopic(context.Context, string) (*client.Topic, error)
+	SearchPhotos(context.Context, client.QueryParams) (*client.PhotoSearchResult, error)
+	SearchCollections(context.Context, client.QueryParams) (*client.CollectionSearchResult, error)
+	SearchUsers(context.Context, client.QueryParams) (*client.UserSearchResult, error)
+	RateLimit() (client.RateLimit, bool)
+}
+
+// Estimate defines the cost of an operation
+type Estimate struct {
+	Operation string `json:"operation"` // description, e.g. `photos of user "jane"`
+	Items     int    `json:"items"`     // results the operation covers
+	Requests  int    `json:"requests"`  // requests the operation makes
+	Lookups   int    `json:"lookups"`   // requests already made to estimate it
+}
+
+// Planner estimates operations, looking up their totals with a Client
+type Planner struct {
+	client Client
+	// PerPage is the number of results per page operations request, from 1 to 30
+	PerPage int
+	// HourlyLimit is the hourly rate limit plans assume until the API has reported it
+	HourlyLimit int
+}
+
+// NewPlanner constructs a Planner for operations requesting pages of DefaultPerPage results
+func NewPlanner(c Client) *Planner {
+	return &Planner{client: c, PerPage: DefaultPerPage, HourlyLimit: DefaultHourlyLimit}
+}
+
+// UserPhotos estimates paginating the photos uploaded by a user
+func (p *Planner) UserPhotos(ctx context.Context, username string) (Estimate, error) {
+	u, err := p.client.GetUserPublicProfile(ctx, username)
+	if err != nil {
+		return Estimate{}, err
+	}
+	return p.paginate(fmt.Sprintf("photos of user %q", username), u.TotalPhotos), nil
+}
+
+// UserLikedPhotos estimates paginating the photos liked by a user
+func (p *Planner) UserLikedPhotos(ctx context.Context, username string) (Estimate, error) {
+	u, err := p.client.GetUserPublicProfile(ctx, username)
+	if err != nil {
+		return Estimate{}, err
+	}
+	return p.paginate(fmt.Sprintf("photos liked by user %q", username), u.TotalLikes), nil
+}
+
+// UserCollections estimates paginating the collections created by a user
+func (p *Planner) UserCollections(ctx context.Context, username string) (Estimate, error) {
+	u, err := p.client.GetUserPublicProfile(ctx, username)
+	if err != nil {
+		return Estimate{}, err
+	}
+	return p.paginate(fmt.Sprintf("collections of user %q", username), u.TotalCollections), nil
+}
+
+// CollectionPhotos estimates paginating a collection's photos
+func (p *Planner) CollectionPhotos(ctx context.Context, id string) (Estimate, error) {
+	c, err := p.client.GetCollection(ctx, id)
+	if err != nil {
+		return Estimate{}, err
+	}
+	return p.paginate(fmt.Sprintf("photos of collection %s", id), c.TotalPhotos), nil
+}
+
+// TopicPhotos estimates paginating a topic's photos
+func (p *Planner) TopicPhotos(ctx context.Context, idOrSlug string) (Estimate, error) {
+	t, err := p.client.GetTopic(ctx, idOrSlug)
+	if err != nil {
+		return Estimate{}, err
+	}
+	return p.paginate(fmt.Sprintf("photos of topic %s", idOrSlug), t.TotalPhotos), nil
+}
+
+// SearchPhotos estimates requesting maxPages pages of a photo search, or every page if maxPages is 0
+func (p *Planner) SearchPhotos(ctx context.Context, searchQuery string, maxPages int) (Estimate, error) {
+	res, err := p.client.SearchPhotos(ctx, p.searchParams(searchQuery))
+	if err != nil {
+		return Estimate{}, err
+	}
+	return p.search(fmt.Sprintf("photo search %q", searchQuery), res.Total, res.TotalPages, maxPages), nil
+}
+
+// SearchCollections estimates requesting maxPages pages of a collection search, or every page if maxPages is 0
+func (p *Planner) SearchCollections(ctx context.Context, searchQuery string, maxPages int) (Estimate, error) {
+	res, err := p.client.SearchCollections(ctx, p.searchParams(searchQuery))
+	if err != nil {
+		return Estimate{}, err
+	}
+	return p.search(fmt.Sprintf("collection search %q", searchQuery), res.Total, res.TotalPages, maxPages), nil
+}
+
+// SearchUsers estimates requesting maxPages pages of a user search, or every page if maxPages is 0
+func (p *Planner) SearchUsers(ctx context.Context, searchQuery string, maxPages int) (Estimate, error) {
+	res, err := p.client.SearchUsers(ctx, p.searchParams(searchQuery))
+	if err != nil {
+		return Estimate{}, err
+	}
+	return p.search(fmt.Sprintf("user search %q", searchQuery), res.Total, res.TotalPages, maxPages), nil
+}
+
+// Hydrate estimates requesting the details of n photos by ID, one request each
+func Hydrate(n int) Estimate {
+	return Estimate{Operation: fmt.Sprintf("details of %d photos", n), Items: n, Requests: n}
+}
+
+// TrackDownloads estimates tracking the downloads of n photos, one request each
+func TrackDownloads(n int) Estimate {
+	return Estimate{Operation: fmt.Sprintf("tracking %d downloads", n), Items: n, Requests: n}
+}
+
+func (p *Planner) perPage() int {
+	if p.PerPage < 1 || p.PerPage > DefaultPerPage {
+		return DefaultPerPage
+	}
+	return p.PerPage
+}
+
+// paginate estimates requesting every page of total results. A resource without results
+// still takes a request to list.
+func (p *Planner) paginate(operation string, total int) Estimate {
+	pages := (total + p.perPage() - 1) / p.perPage()
+	if pages == 0 {
+		pages = 1
+	}
+	return Estimate{Operation: operation, Items: total, Requests: pages, Lookups: 1}
+}
+
+func (p *Planner) searchParams(searchQuery string) client.QueryParams {
+	return client.QueryParams{"query": searchQuery, "per_page": strconv.Itoa(p.perPage())}
+}
+
+func (p *Planner) search(operation string, total, totalPages, maxPages int) Estimate {
+	pages := totalPages
+	if maxPages > 0 && pages > maxPages {
+		pages = maxPages
+	}
+	items := pages * p.perPage()
+	if items > total {
+		items = total
+	}
+	if pages == 0 {
+		pages = 1
+	}
+	return Estimate{Operation: operation, Items: items, Requests: pages, Lookups: 1}
+}
+
+// Plan defines the cost of operations run together, compared with the hourly rate limit
+type Plan struct {
+	Estimates []Estimate `json:"estimates"`
+	Requests  int        `json:"requests"`  // requests the operations make in all
+	Limit     int        `json:"limit"`     // requests allowed per hour
+	Remaining int        `json:"remaining"` // requests left in the current hour
+}
+
+// Plan sums estimates, comparing them with the rate limit the API last reported to the client,
+// or with HourlyLimit if it hasn't reported it yet
+func (p *Planner) Plan(estimates ...Estimate) *Plan {
+	plan := &Plan{Estimates: estimates, Limit: p.HourlyLimit, Remaining: p.HourlyLimit}
+	if rl, ok := p.client.RateLimit(); ok {
+		plan.Limit, plan.Remaining = rl.Limit, rl.Remaining
+	}
+	for _, e := range estimates {
+		plan.Requests += e.Requests
+	}
+	return plan
+}
+
+// Fits reports whether the plan's requests fit in what's left of the current hour's rate limit
+func (p *Plan) Fits() bool {
+	return p.Requests <= p.Remaining
+}
+
+// Hours returns the number of hourly rate limit windows the plan's requests span, starting
+// with the current one, or 0 if the limit is 0
+func (p *Plan) Hours() int {
+	if p.Requests <= p.Remaining {
+		return 1
+	}
+	if p.Limit <= 0 {
+		return 0
+	}
+	rest := p.Requests - p.Remaining
+	return 1 + (rest+p.Limit-1)/p.Limit
+}
+
+// Budget returns a Budget allowing the plan's requests, to run them with client.WithBudget
+func (p *Plan) Budget() *client.Budget {
+	return client.NewBudget(p.Requests)
+}
+
+// String formats the plan as a table of its operations, followed by a summary
+func (p *Plan) String() string {
+	var b strings.Builder
+	for _, e := range p.Estimates {
+		fmt.Fprintf(&b, "%-40s %6d items %6d requests\n", e.Operation, e.Items, e.Requests)
+	}
+	fmt.Fprintf(&b, "%d requests, %d of %d left this hour", p.Requests, p.Remaining, p.Limit)
+	if p.Fits() {
+		b.WriteString(": fits\n")
+	} else if hours := p.Hours(); hours > 0 {
+		fmt.Fprintf(&b, ": spans %d hours\n", hours)
+	} else {
+		b.WriteString(": doesn't fit\n")
+	}
+	return b.String()
+}
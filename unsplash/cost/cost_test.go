@@ -0,0 +1,165 @@
+package cost
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/eddogola/unsplash-go/unsplash/client"
+)
+
+type fakeClient struct {
+	rateLimit *client.RateLimit
+}
+
+func (f *fakeClient) GetUserPublicProfile(ctx context.Context, username string) (*client.User, error) {
+	return &client.User{Username: username, TotalPhotos: 95, TotalLikes: 0, TotalCollections: 30}, nil
+}
+
+func (f *fakeClient) GetCollection(ctx context.Context, id string) (*client.Collection, error) {
+	return &client.Collection{ID: id, TotalPhotos: 31}, nil
+}
+
+func (f *fakeClient) GetTopic(ctx context.Context, idOrSlug string) (*client.Topic, error) {
+	return &client.Topic{Slug: idOrSlug, TotalPhotos: 3000}, nil
+}
+
+func (f *fakeClient) SearchPhotos(ctx context.Context, qp client.QueryParams) (*client.PhotoSearchResult, error) {
+	return &client.PhotoSearchResult{Total: 1000, TotalPages: 34}, nil
+}
+
+func (f *fakeClient) SearchCollections(ctx context.Context, qp client.QueryParams) (*client.CollectionSearchResult, error) {
+	return &client.CollectionSearchResult{Total: 40, TotalPages: 2}, nil
+}
+
+func (f *fakeClient) SearchUsers(ctx context.Context, qp client.QueryParams) (*client.UserSearchResult, error) {
+	return &client.UserSearchResult{}, nil
+}
+
+func (f *fakeClient) RateLimit() (client.RateLimit, bool) {
+	if f.rateLimit == nil {
+		return client.RateLimit{}, false
+	}
+	return *f.rateLimit, true
+}
+
+func TestPlanner(t *testing.T) {
+	ctx := context.Background()
+	p := NewPlanner(&fakeClient{})
+
+	t.Run("estimates", func(t *testing.T) {
+		cases := []struct {
+			estimate func() (Estimate, error)
+			items    int
+			requests int
+		}{
+			{func() (Estimate, error) { return p.UserPhotos(ctx, "jane") }, 95, 4},
+			{func() (Estimate, error) { return p.UserLikedPhotos(ctx, "jane") }, 0, 1},
+			{func() (Estimate, error) { return p.UserCollections(ctx, "jane") }, 30, 1},
+			{func() (Estimate, error) { return p.CollectionPhotos(ctx, "123") }, 31, 2},
+			{func() (Estimate, error) { return p.TopicPhotos(ctx, "nature") }, 3000, 100},
+			{func() (Estimate, error) { return p.SearchPhotos(ctx, "forest", 0) }, 1000, 34},
+			{func() (Estimate, error) { return p.SearchPhotos(ctx, "forest", 5) }, 150, 5},
+			{func() (Estimate, error) { return p.SearchCollections(ctx, "forest", 5) }, 40, 2},
+			{func() (Estimate, error) { return p.SearchUsers(ctx, "nobody", 0) }, 0, 1},
+			{func() (Estimate, error) { return Hydrate(40), nil }, 40, 40},
+			{func() (Estimate, error) { return TrackDownloads(7), nil }, 7, 7},
+		}
+		for _, c := range cases {
+			e, err := c.estimate()
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if e.Items != c.items || e.Requests != c.requests {
+				t.Errorf("%s: expected %d items in %d requests but got %d in %d", e.Operation, c.items, c.requests, e.Items, e.Requests)
+			}
+		}
+	})
+
+	t.Run("plans", func(t *testing.T) {
+		photos, _ := p.UserPhotos(ctx, "jane")
+		plan := p.Plan(photos, Hydrate(photos.Items))
+		if plan.Requests != 99 {
+			t.Errorf("expected 99 requests but got %d", plan.Requests)
+		}
+		// the demo limit is assumed until the API reports one
+		if plan.Limit != DefaultHourlyLimit || plan.Fits() || plan.Hours() != 2 {
+			t.Errorf("expected the plan to span 2 hours of the demo limit but got %+v, %d hours", plan, plan.Hours())
+		}
+		if s := plan.String(); !strings.Contains(s, `photos of user "jane"`) || !strings.Contains(s, "spans 2 hours") {
+			t.Errorf("unexpected plan:\n%s", s)
+		}
+
+		p := NewPlanner(&fakeClient{rateLimit: &client.RateLimit{Limit: 5000, Remaining: 120}})
+		plan = p.Plan(photos, Hydrate(photos.Items))
+		if !plan.Fits() || plan.Hours() != 1 {
+			t.Errorf("expected the plan to fit but got %+v", plan)
+		}
+		plan = p.Plan(Hydrate(5200))
+		if plan.Fits() || plan.Hours() != 3 {
+			t.Errorf("expected the plan to span 3 hours but got %d", plan.Hours())
+		}
+		if b := plan.Budget(); b.Limit() != 5200 || b.Remaining() != 5200 {
+			t.Errorf("expected a budget of 5200 requests but got %d", b.Limit())
+		}
+	})
+}
+
+// redirectTransport sends every request to the test server
+type redirectTransport struct {
+	target *url.URL
+}
+
+func (t redirectTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	req.URL.Scheme = t.target.Scheme
+	req.URL.Host = t.target.Host
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+func TestBudget(t *testing.T) {
+	var requests int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		w.Header().Set("X-Ratelimit-Limit", "50")
+		w.Header().Set("X-Ratelimit-Remaining", "42")
+		w.Write([]byte(`{"id": "abc", "total_photos": 12}`))
+	}))
+	defer ts.Close()
+	target, _ := url.Parse(ts.URL)
+	c := client.New("id", &http.Client{Transport: redirectTransport{target}}, client.NewConfig())
+
+	b := client.NewBudget(2)
+	ctx := client.WithBudget(context.Background(), b)
+	for i := 0; i < 2; i++ {
+		if _, err := c.GetPhoto(ctx, "abc"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	_, err := c.GetPhoto(ctx, "abc")
+	if e, ok := err.(client.ErrBudgetExceeded); !ok || int(e) != 2 {
+		t.Errorf("expected an ErrBudgetExceeded but got %v", err)
+	}
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, "https://api.unsplash.com/photos/abc", nil)
+	if _, err := c.Do(req); err == nil {
+		t.Error("expected Do to respect the budget")
+	}
+	if requests != 2 || b.Used() != 2 || b.Remaining() != 0 {
+		t.Errorf("expected 2 requests but got %d, %d used", requests, b.Used())
+	}
+
+	// other contexts aren't limited
+	if _, err := c.GetPhoto(context.Background(), "abc"); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	// the planner compares plans with the rate limit the API reported
+	plan := NewPlanner(c).Plan(Hydrate(40))
+	if plan.Limit != 50 || plan.Remaining != 42 || !plan.Fits() {
+		t.Errorf("expected the reported rate limit but got %+v", plan)
+	}
+}
@@ -0,0 +1,94 @@
+package curate
+
+import (
+	"context"
+	"fmt"
+)
+
+// Failure records an action that failed
+type Failure struct {
+	Action Action
+	Err    error
+}
+
+func (f Failure) Error() string {
+	return fmt.Sprintf("%v: %v", f.Action, f.Err)
+}
+
+// Result reports what applying a plan did
+type Result struct {
+	Applied []Action
+	Failed  []Failure
+	// Skipped lists actions on collections that could not be created
+	Skipped []Action
+}
+
+// OK reports whether every action was applied
+func (r *Result) OK() bool {
+	return len(r.Failed) == 0 && len(r.Skipped) == 0
+}
+
+// Apply makes the changes in plan, in order. An action that fails is reported in the result
+// and the rest are still applied, except for the actions on a collection that could not be
+// created, which are skipped. Planning again after fixing the cause of a failure, e.g. a deleted
+// photo removed from the manifest, picks up where the failed apply left off.
+// Apply stops early, returning ctx's error, if ctx is done.
+func (p *Planner) Apply(ctx context.Context, plan *Plan) (*Result, error) {
+	res := &Result{}
+	created := make(map[string]string) // IDs of the collections created, by title key
+	for _, a := range plan.Actions {
+		if err := ctx.Err(); err != nil {
+			return res, err
+		}
+		id := a.CollectionID
+		if id == "" && a.Type != Create {
+			var ok bool
+			if id, ok = created[titleKey(a.Collection)]; !ok {
+				res.Skipped = append(res.Skipped, a)
+				continue
+			}
+		}
+		if err := p.apply(ctx, a, id, created); err != nil {
+			if ctx.Err() != nil {
+				return res, ctx.Err()
+			}
+			res.Failed = append(res.Failed, Failure{a, err})
+			continue
+		}
+		res.Applied = append(res.Applied, a)
+	}
+	return res, nil
+}
+
+func (p *Planner) apply(ctx context.Context, a Action, id string, created map[string]string) error {
+	switch a.Type {
+	case Create:
+		c, err := p.client.CreateCollection(ctx, changeData(a.Changes))
+		if err != nil {
+			return err
+		}
+		created[titleKey(a.Collection)] = c.ID
+		return nil
+	case Update:
+		_, err := p.client.UpdateCollection(ctx, id, changeData(a.Changes))
+		return err
+	case Delete:
+		return p.client.DeleteCollection(ctx, id)
+	case AddPhoto:
+		_, err := p.client.AddPhotoToCollection(ctx, id, map[string]string{"collection_id": id, "photo_id": a.PhotoID})
+		return err
+	case RemovePhoto:
+		_, err := p.client.RemovePhotoFromCollection(ctx, id, map[string]string{"collection_id": id, "photo_id": a.PhotoID})
+		return err
+	}
+	return fmt.Errorf("unknown action type %q", a.Type)
+}
+
+// changeData converts changes into the data sent to the API
+func changeData(changes []Change) map[string]string {
+	data := make(map[string]string, len(changes))
+	for _, c := range changes {
+		data[c.Field] = c.New
+	}
+	return data
+}
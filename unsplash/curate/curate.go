@@ -0,0 +1,183 @@
+// Package curate manages an account's collections declaratively.
+//
+// A manifest, written in YAML or JSON, lists the collections an account should have: each
+// collection's title, description, privacy and photos, in order. Planning compares a manifest
+// with the account's live collections and lists the actions needed to make them match;
+// applying a plan makes the changes, carrying on past actions that fail and reporting them.
+//
+// An example manifest:
+//
+//	collections:
+//	  - title: Forests
+//	    description: Trees, mostly
+//	    photos: [Dwu85P9SOIk, 9gz3wfHr65U]
+//	  - id: 206               # bind to an existing collection, so it can be renamed
+//	    title: Deserts
+//	    private: true
+//	    photos: [eOLpJytrbsQ]
+//
+// Collections without an ID are matched to live collections by title, ignoring case and
+// surrounding spaces.
+package curate
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+
+	"github.com/eddogola/unsplash-go/unsplash/client"
+	"gopkg.in/yaml.v3"
+)
+
+// Manifest defines the desired state of an account's collections
+type Manifest struct {
+	Collections []CollectionSpec `json:"collections" yaml:"collections"`
+}
+
+// CollectionSpec defines the desired state of one collection
+type CollectionSpec struct {
+	// ID binds the spec to an existing collection. If empty, the collection is matched by title,
+	// and created if there is no match.
+	ID          string `json:"id,omitempty" yaml:"id,omitempty"`
+	Title       string `json:"title" yaml:"title"`
+	Description string `json:"description,omitempty" yaml:"description,omitempty"`
+	Private     bool   `json:"private,omitempty" yaml:"private,omitempty"`
+	// Photos lists photo IDs in the order the collection lists them, most recently added first
+	Photos []string `json:"photos" yaml:"photos"`
+}
+
+// ErrInvalidManifest is raised when a manifest is malformed, or contradicts itself
+type ErrInvalidManifest string
+
+func (e ErrInvalidManifest) Error() string {
+	return "invalid manifest: " + string(e)
+}
+
+// Load reads the manifest at path. Files ending in `.json` are parsed as JSON, anything else as YAML.
+func Load(path string) (*Manifest, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return Parse(data, strings.EqualFold(filepath.Ext(path), ".json"))
+}
+
+// Parse parses a manifest in JSON, if isJSON is true, or YAML. Unknown fields are rejected,
+// so that misspelt fields aren't silently ignored.
+func Parse(data []byte, isJSON bool) (*Manifest, error) {
+	var m Manifest
+	if isJSON {
+		dec := json.NewDecoder(bytes.NewReader(data))
+		dec.DisallowUnknownFields()
+		if err := dec.Decode(&m); err != nil {
+			return nil, ErrInvalidManifest(err.Error())
+		}
+	} else {
+		dec := yaml.NewDecoder(bytes.NewReader(data))
+		dec.KnownFields(true)
+		// an empty document decodes to an empty manifest
+		if err := dec.Decode(&m); err != nil && err != io.EOF {
+			return nil, ErrInvalidManifest(err.Error())
+		}
+	}
+	if err := m.Validate(); err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
+
+// Validate checks that every collection has a title, and that no collection, or photo within
+// a collection, is listed twice
+func (m *Manifest) Validate() error {
+	titles := make(map[string]bool)
+	ids := make(map[string]bool)
+	for i, spec := range m.Collections {
+		key := titleKey(spec.Title)
+		if key == "" {
+			return ErrInvalidManifest(fmt.Sprintf("collection %d has no title", i+1))
+		}
+		if titles[key] {
+			return ErrInvalidManifest(fmt.Sprintf("collection %q is listed more than once", spec.Title))
+		}
+		titles[key] = true
+		if spec.ID != "" {
+			if ids[spec.ID] {
+				return ErrInvalidManifest(fmt.Sprintf("collection ID %s is listed more than once", spec.ID))
+			}
+			ids[spec.ID] = true
+		}
+		pics := make(map[string]bool)
+		for _, id := range spec.Photos {
+			if id == "" {
+				return ErrInvalidManifest(fmt.Sprintf("collection %q has an empty photo ID", spec.Title))
+			}
+			if pics[id] {
+				return ErrInvalidManifest(fmt.Sprintf("photo %s is listed more than once in collection %q", id, spec.Title))
+			}
+			pics[id] = true
+		}
+	}
+	return nil
+}
+
+// Client defines client methods used to plan and apply changes.
+// Planning needs the read_collections scope to see private collections, applying needs write_collections.
+type Client interface {
+	GetUserPrivateProfile(context.Context) (*client.User, error)
+	GetUserCollections(context.Context, string, client.QueryParams) ([]client.Collection, error)
+	GetCollectionPhotos(context.Context, string, client.QueryParams) ([]client.Photo, error)
+	CreateCollection(context.Context, map[string]string) (*client.Collection, error)
+	UpdateCollection(context.Context, string, map[string]string) (*client.Collection, error)
+	DeleteCollection(context.Context, string) error
+	AddPhotoToCollection(context.Context, string, map[string]string) (*client.CollectionActionResponse, error)
+	RemovePhotoFromCollection(context.Context, string, map[string]string) (*client.CollectionActionResponse, error)
+}
+
+// perPage is the number of results requested per page when listing, the most the API allows
+const perPage = 30
+
+// listPhotoIDs requests every page of a collection's photos, returning their IDs
+func listPhotoIDs(ctx context.Context, c Client, id string) ([]string, error) {
+	var ids []string
+	for page := 1; ; page++ {
+		res, err := c.GetCollectionPhotos(ctx, id, pageParams(page))
+		if err != nil {
+			return nil, err
+		}
+		for _, p := range res {
+			ids = append(ids, p.ID)
+		}
+		if len(res) < perPage {
+			return ids, nil
+		}
+	}
+}
+
+// listCollections requests every page of a user's collections
+func listCollections(ctx context.Context, c Client, username string) ([]client.Collection, error) {
+	var collections []client.Collection
+	for page := 1; ; page++ {
+		res, err := c.GetUserCollections(ctx, username, pageParams(page))
+		if err != nil {
+			return nil, err
+		}
+		collections = append(collections, res...)
+		if len(res) < perPage {
+			return collections, nil
+		}
+	}
+}
+
+func pageParams(page int) client.QueryParams {
+	return client.QueryParams{"page": fmt.Sprint(page), "per_page": fmt.Sprint(perPage)}
+}
+
+// titleKey normalizes a collection title for matching
+func titleKey(title string) string {
+	return strings.ToLower(strings.TrimSpace(title))
+}
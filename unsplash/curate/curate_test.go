@@ -0,0 +1,330 @@
+package curate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/eddogola/unsplash-go/unsplash/client"
+)
+
+// fakeClient holds an account's collections in memory. Like the API, it lists the most recently
+// added photos first.
+type fakeClient struct {
+	collections []*client.Collection
+	photos      map[string][]string
+	created     int
+	calls       int // write requests made
+	failCreate  string
+}
+
+func newFakeClient() *fakeClient {
+	return &fakeClient{photos: make(map[string][]string)}
+}
+
+func (f *fakeClient) add(id, title string, photoIDs ...string) {
+	f.collections = append(f.collections, &client.Collection{ID: id, Title: title})
+	f.photos[id] = photoIDs
+}
+
+func (f *fakeClient) find(id string) (*client.Collection, error) {
+	for _, c := range f.collections {
+		if c.ID == id {
+			return c, nil
+		}
+	}
+	return nil, errors.New("not found")
+}
+
+// page returns the bounds of the items in the requested page
+func page(n int, qp client.QueryParams) (int, int) {
+	p, _ := strconv.Atoi(qp["page"])
+	start := (p - 1) * perPage
+	if start > n {
+		start = n
+	}
+	end := start + perPage
+	if end > n {
+		end = n
+	}
+	return start, end
+}
+
+func (f *fakeClient) GetUserPrivateProfile(ctx context.Context) (*client.User, error) {
+	return &client.User{Username: "curator"}, nil
+}
+
+func (f *fakeClient) GetUserCollections(ctx context.Context, username string, qp client.QueryParams) ([]client.Collection, error) {
+	start, end := page(len(f.collections), qp)
+	var res []client.Collection
+	for _, c := range f.collections[start:end] {
+		res = append(res, *c)
+	}
+	return res, nil
+}
+
+func (f *fakeClient) GetCollectionPhotos(ctx context.Context, id string, qp client.QueryParams) ([]client.Photo, error) {
+	ids := f.photos[id]
+	start, end := page(len(ids), qp)
+	var res []client.Photo
+	for _, id := range ids[start:end] {
+		res = append(res, client.Photo{ID: id})
+	}
+	return res, nil
+}
+
+func (f *fakeClient) CreateCollection(ctx context.Context, data map[string]string) (*client.Collection, error) {
+	f.calls++
+	if data["title"] == f.failCreate {
+		return nil, errors.New("rejected")
+	}
+	f.created++
+	id := fmt.Sprintf("new%d", f.created)
+	f.add(id, data["title"])
+	c, _ := f.find(id)
+	c.Description, c.Private = data["description"], data["private"] == "true"
+	return c, nil
+}
+
+func (f *fakeClient) UpdateCollection(ctx context.Context, id string, data map[string]string) (*client.Collection, error) {
+	f.calls++
+	c, err := f.find(id)
+	if err != nil {
+		return nil, err
+	}
+	if v, ok := data["title"]; ok {
+		c.Title = v
+	}
+	if v, ok := data["description"]; ok {
+		c.Description = v
+	}
+	if v, ok := data["private"]; ok {
+		c.Private = v == "true"
+	}
+	return c, nil
+}
+
+func (f *fakeClient) DeleteCollection(ctx context.Context, id string) error {
+	f.calls++
+	for i, c := range f.collections {
+		if c.ID == id {
+			f.collections = append(f.collections[:i], f.collections[i+1:]...)
+			delete(f.photos, id)
+			return nil
+		}
+	}
+	return errors.New("not found")
+}
+
+func (f *fakeClient) AddPhotoToCollection(ctx context.Context, id string, data map[string]string) (*client.CollectionActionResponse, error) {
+	f.calls++
+	if data["photo_id"] == "deleted" {
+		return nil, errors.New("photo not found")
+	}
+	f.photos[id] = append([]string{data["photo_id"]}, f.photos[id]...)
+	return &client.CollectionActionResponse{}, nil
+}
+
+func (f *fakeClient) RemovePhotoFromCollection(ctx context.Context, id string, data map[string]string) (*client.CollectionActionResponse, error) {
+	f.calls++
+	ids := f.photos[id]
+	for i, photoID := range ids {
+		if photoID == data["photo_id"] {
+			f.photos[id] = append(ids[:i:i], ids[i+1:]...)
+			return &client.CollectionActionResponse{}, nil
+		}
+	}
+	return nil, errors.New("photo not in collection")
+}
+
+func TestParse(t *testing.T) {
+	yamlManifest := `
+collections:
+  - title: Forests
+    description: Trees, mostly
+    photos: [a, b]
+  - id: 206
+    title: Deserts
+    private: true
+    photos:
+      - c
+`
+	jsonManifest := `{"collections": [
+		{"title": "Forests", "description": "Trees, mostly", "photos": ["a", "b"]},
+		{"id": "206", "title": "Deserts", "private": true, "photos": ["c"]}
+	]}`
+	want := &Manifest{Collections: []CollectionSpec{
+		{Title: "Forests", Description: "Trees, mostly", Photos: []string{"a", "b"}},
+		{ID: "206", Title: "Deserts", Private: true, Photos: []string{"c"}},
+	}}
+	for name, tc := range map[string]struct {
+		data   string
+		isJSON bool
+	}{"yaml": {yamlManifest, false}, "json": {jsonManifest, true}} {
+		m, err := Parse([]byte(tc.data), tc.isJSON)
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if !reflect.DeepEqual(m, want) {
+			t.Errorf("%s: expected %+v, got %+v", name, want, m)
+		}
+	}
+
+	if m, err := Parse(nil, false); err != nil || len(m.Collections) != 0 {
+		t.Errorf("expected an empty document to be an empty manifest, got %v, %v", m, err)
+	}
+	for _, invalid := range []string{
+		"collections:\n  - title: Forests\n    photo: [a]\n",
+		"collections:\n  - description: untitled\n",
+		"collections:\n  - title: Forests\n  - title: ' forests'\n",
+		"collections:\n  - title: Forests\n    photos: [a, a]\n",
+	} {
+		if _, err := Parse([]byte(invalid), false); !errors.As(err, new(ErrInvalidManifest)) {
+			t.Errorf("expected %q to be invalid, got %v", invalid, err)
+		}
+	}
+}
+
+func TestDiffPhotos(t *testing.T) {
+	for _, tc := range []struct {
+		live, desired, remove, add []string
+		moved                      int
+	}{
+		{live: []string{"a", "b"}, desired: []string{"a", "b"}},
+		{live: []string{"a", "b"}, desired: []string{"x", "a", "b"}, add: []string{"x"}},
+		{live: []string{"a", "z", "b"}, desired: []string{"a", "b"}, remove: []string{"z"}},
+		{live: nil, desired: []string{"a", "b", "c"}, add: []string{"c", "b", "a"}},
+		// b is listed after c, so it's moved
+		{live: []string{"a", "c", "b"}, desired: []string{"a", "b", "c"}, remove: []string{"a", "b"}, add: []string{"b", "a"}, moved: 2},
+	} {
+		remove, add, moved := diffPhotos(tc.live, tc.desired)
+		count := 0
+		for _, m := range moved {
+			if m {
+				count++
+			}
+		}
+		if strings.Join(remove, ",") != strings.Join(tc.remove, ",") || strings.Join(add, ",") != strings.Join(tc.add, ",") || count != tc.moved {
+			t.Errorf("%v -> %v: got remove %v, add %v and %d moved", tc.live, tc.desired, remove, add, count)
+		}
+	}
+}
+
+func TestPlanAndApply(t *testing.T) {
+	ctx := context.Background()
+	f := newFakeClient()
+	f.add("206", "deserts", "c", "old")
+	f.add("300", "Forests", "b")
+	f.add("400", "Unmanaged", "z")
+	var many []string
+	for i := 0; i < 40; i++ {
+		many = append(many, fmt.Sprintf("p%d", i))
+	}
+	f.add("500", "Archive", many...)
+	m := &Manifest{Collections: []CollectionSpec{
+		{Title: "forests", Description: "Trees", Photos: []string{"a", "b"}},
+		{ID: "206", Title: "Dunes", Private: true, Photos: []string{"c"}},
+		{Title: "Archive", Photos: many},
+		{Title: "Lakes", Photos: []string{"l1", "deleted", "l2"}},
+	}}
+
+	p := NewPlanner(f)
+	p.Prune = true
+	plan, err := p.Plan(ctx, m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if f.calls != 0 {
+		t.Fatalf("expected planning to make no changes, made %d", f.calls)
+	}
+	out := plan.String()
+	for _, want := range []string{
+		`~ update collection "forests" (300)`,
+		`description: "" -> "Trees"`,
+		`title: "deserts" -> "Dunes"`,
+		`- remove photo old`,
+		`+ create collection "Lakes"`,
+		`- delete collection "Unmanaged" (400)`,
+		"Plan: 1 to create, 2 to update, 1 to delete; 4 photos to add, 1 to remove, 0 to move.",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected plan to contain %q, got\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, `"Archive"`) {
+		t.Errorf("expected no changes to the matching collection, got\n%s", out)
+	}
+
+	res, err := p.Apply(ctx, plan)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Failed) != 1 || res.Failed[0].Action.PhotoID != "deleted" || res.OK() {
+		t.Errorf("expected the deleted photo to fail, got %v", res.Failed)
+	}
+	if len(res.Applied) != len(plan.Actions)-1 {
+		t.Errorf("expected the other actions to be applied, got %d of %d", len(res.Applied), len(plan.Actions))
+	}
+	lakes, _ := f.find("new1")
+	if got := strings.Join(f.photos["new1"], ","); lakes == nil || got != "l1,l2" {
+		t.Errorf("expected the new collection's photos in manifest order, got %q", got)
+	}
+	if _, err := f.find("400"); err == nil {
+		t.Errorf("expected the unmanaged collection to be pruned")
+	}
+
+	// with the failing photo dropped, the account matches the manifest
+	m.Collections[3].Photos = []string{"l1", "l2"}
+	if plan, err = p.Plan(ctx, m); err != nil || !plan.Empty() {
+		t.Errorf("expected an empty plan after applying, got %v, %v", plan, err)
+	}
+
+	// reordering moves photos
+	m.Collections[0].Photos = []string{"b", "a"}
+	if plan, err = p.Plan(ctx, m); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(plan.String(), "~ move photo b") {
+		t.Errorf("expected b to be moved, got\n%s", plan)
+	}
+	if _, err := p.Apply(ctx, plan); err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.Join(f.photos["300"], ","); got != "b,a" {
+		t.Errorf("expected photos to be reordered, got %q", got)
+	}
+}
+
+func TestApplySkipsUncreatedCollections(t *testing.T) {
+	f := newFakeClient()
+	f.failCreate = "Lakes"
+	p := NewPlanner(f)
+	p.Username = "curator"
+	m := &Manifest{Collections: []CollectionSpec{{Title: "Lakes", Photos: []string{"l1", "l2"}}}}
+	plan, err := p.Plan(context.Background(), m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	res, err := p.Apply(context.Background(), plan)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Failed) != 1 || res.Failed[0].Action.Type != Create || len(res.Skipped) != 2 {
+		t.Errorf("expected the create to fail and its photos to be skipped, got %+v", res)
+	}
+	if f.calls != 1 {
+		t.Errorf("expected no requests for skipped actions, made %d", f.calls-1)
+	}
+}
+
+func TestPlanUnknownID(t *testing.T) {
+	p := NewPlanner(newFakeClient())
+	m := &Manifest{Collections: []CollectionSpec{{ID: "missing", Title: "Lakes"}}}
+	if _, err := p.Plan(context.Background(), m); err != ErrCollectionNotFound("missing") {
+		t.Errorf("expected collection not found error, got %v", err)
+	}
+}
@@ -0,0 +1,303 @@
+package curate
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/eddogola/unsplash-go/unsplash/client"
+)
+
+// ActionType identifies a change made by a plan
+type ActionType string
+
+// Types of changes made by a plan
+const (
+	Create      ActionType = "create"
+	Update      ActionType = "update"
+	Delete      ActionType = "delete"
+	AddPhoto    ActionType = "add_photo"
+	RemovePhoto ActionType = "remove_photo"
+)
+
+// Change defines a collection field changed by a create or update action
+type Change struct {
+	Field string `json:"field"`
+	Old   string `json:"old"`
+	New   string `json:"new"`
+}
+
+// Action defines one change to an account's collections
+type Action struct {
+	Type ActionType `json:"type"`
+	// Collection is the collection's title in the manifest, or its live title if it's being deleted
+	Collection string `json:"collection"`
+	// CollectionID is empty for actions on a collection the plan creates
+	CollectionID string   `json:"collection_id,omitempty"`
+	PhotoID      string   `json:"photo_id,omitempty"`
+	Changes      []Change `json:"changes,omitempty"`
+	// Reorder is set on photos removed and added again to move them to their position in the manifest
+	Reorder bool `json:"reorder,omitempty"`
+}
+
+func (a Action) String() string {
+	switch a.Type {
+	case Create:
+		return fmt.Sprintf("create collection %q", a.Collection)
+	case Update:
+		return fmt.Sprintf("update collection %q", a.Collection)
+	case Delete:
+		return fmt.Sprintf("delete collection %q", a.Collection)
+	case AddPhoto:
+		return fmt.Sprintf("add photo %s to collection %q", a.PhotoID, a.Collection)
+	case RemovePhoto:
+		return fmt.Sprintf("remove photo %s from collection %q", a.PhotoID, a.Collection)
+	}
+	return string(a.Type)
+}
+
+// Plan lists the actions that make an account's collections match a manifest, in the order they're applied
+type Plan struct {
+	Username string   `json:"username"`
+	Actions  []Action `json:"actions"`
+}
+
+// Empty reports whether the collections already match the manifest
+func (p *Plan) Empty() bool {
+	return len(p.Actions) == 0
+}
+
+// String describes the plan for review, grouping actions by collection
+func (p *Plan) String() string {
+	if p.Empty() {
+		return "No changes, the collections match the manifest.\n"
+	}
+	var b strings.Builder
+	var current string
+	counts := make(map[ActionType]int)
+	for _, a := range p.Actions {
+		key := a.CollectionID + "/" + a.Collection
+		switch a.Type {
+		case Create:
+			fmt.Fprintf(&b, "+ create collection %q\n", a.Collection)
+			for _, c := range a.Changes {
+				fmt.Fprintf(&b, "      %s: %q\n", c.Field, c.New)
+			}
+		case Update:
+			fmt.Fprintf(&b, "~ update collection %q (%s)\n", a.Collection, a.CollectionID)
+			for _, c := range a.Changes {
+				fmt.Fprintf(&b, "      %s: %q -> %q\n", c.Field, c.Old, c.New)
+			}
+		case Delete:
+			fmt.Fprintf(&b, "- delete collection %q (%s)\n", a.Collection, a.CollectionID)
+		default:
+			if key != current {
+				fmt.Fprintf(&b, "  collection %q (%s)\n", a.Collection, a.CollectionID)
+			}
+			switch {
+			case a.Reorder && a.Type == AddPhoto:
+				fmt.Fprintf(&b, "    ~ move photo %s\n", a.PhotoID)
+			case a.Reorder:
+				// shown once, as a move, when the photo is added again
+			case a.Type == AddPhoto:
+				fmt.Fprintf(&b, "    + add photo %s\n", a.PhotoID)
+			default:
+				fmt.Fprintf(&b, "    - remove photo %s\n", a.PhotoID)
+			}
+		}
+		current = key
+		if !a.Reorder {
+			counts[a.Type]++
+		} else if a.Type == AddPhoto {
+			counts["move"]++
+		}
+	}
+	fmt.Fprintf(&b, "\nPlan: %d to create, %d to update, %d to delete; %d photos to add, %d to remove, %d to move.\n",
+		counts[Create], counts[Update], counts[Delete], counts[AddPhoto], counts[RemovePhoto], counts["move"])
+	return b.String()
+}
+
+// ErrCollectionNotFound is raised when a manifest binds a collection ID that isn't in the account
+type ErrCollectionNotFound string
+
+func (e ErrCollectionNotFound) Error() string {
+	return fmt.Sprintf("collection %s not found in the account", string(e))
+}
+
+// Planner plans and applies changes to an account's collections
+type Planner struct {
+	client Client
+	// Username is the account whose collections are managed.
+	// It's requested from the API, which needs the read_user scope, if empty.
+	Username string
+	// Prune deletes the account's collections that aren't in the manifest.
+	// Without it, they're left alone.
+	Prune bool
+}
+
+// NewPlanner constructs a new Planner.
+// c must be private, with the write_collections scope to apply plans.
+func NewPlanner(c Client) *Planner {
+	return &Planner{client: c}
+}
+
+// Plan compares m with the account's live collections, returning the actions that make them match
+func (p *Planner) Plan(ctx context.Context, m *Manifest) (*Plan, error) {
+	if err := m.Validate(); err != nil {
+		return nil, err
+	}
+	username := p.Username
+	if username == "" {
+		me, err := p.client.GetUserPrivateProfile(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("error getting the account to manage, set Planner.Username: %w", err)
+		}
+		username = me.Username
+	}
+	live, err := listCollections(ctx, p.client, username)
+	if err != nil {
+		return nil, err
+	}
+
+	// match collections bound by ID first, so they aren't claimed by another collection's title
+	matched := make([]*client.Collection, len(m.Collections))
+	claimed := make(map[string]bool)
+	for i, spec := range m.Collections {
+		if spec.ID == "" {
+			continue
+		}
+		for j := range live {
+			if live[j].ID == spec.ID {
+				matched[i] = &live[j]
+			}
+		}
+		if matched[i] == nil {
+			return nil, ErrCollectionNotFound(spec.ID)
+		}
+		claimed[spec.ID] = true
+	}
+	for i, spec := range m.Collections {
+		if spec.ID != "" {
+			continue
+		}
+		for j := range live {
+			if !claimed[live[j].ID] && titleKey(live[j].Title) == titleKey(spec.Title) {
+				matched[i] = &live[j]
+				claimed[live[j].ID] = true
+				break
+			}
+		}
+	}
+
+	plan := &Plan{Username: username}
+	for i, spec := range m.Collections {
+		actions, err := p.planCollection(ctx, spec, matched[i])
+		if err != nil {
+			return nil, err
+		}
+		plan.Actions = append(plan.Actions, actions...)
+	}
+	if p.Prune {
+		for _, c := range live {
+			if !claimed[c.ID] {
+				plan.Actions = append(plan.Actions, Action{Type: Delete, Collection: c.Title, CollectionID: c.ID})
+			}
+		}
+	}
+	return plan, nil
+}
+
+// planCollection returns the actions making the live collection c match spec, creating it if c is nil
+func (p *Planner) planCollection(ctx context.Context, spec CollectionSpec, c *client.Collection) ([]Action, error) {
+	var actions []Action
+	var live []string
+	if c == nil {
+		create := Action{Type: Create, Collection: spec.Title, Changes: []Change{{"title", "", spec.Title}}}
+		if spec.Description != "" {
+			create.Changes = append(create.Changes, Change{"description", "", spec.Description})
+		}
+		if spec.Private {
+			create.Changes = append(create.Changes, Change{"private", "", "true"})
+		}
+		actions = append(actions, create)
+	} else {
+		var changes []Change
+		if c.Title != spec.Title {
+			changes = append(changes, Change{"title", c.Title, spec.Title})
+		}
+		if c.Description != spec.Description {
+			changes = append(changes, Change{"description", c.Description, spec.Description})
+		}
+		if c.Private != spec.Private {
+			changes = append(changes, Change{"private", fmt.Sprint(c.Private), fmt.Sprint(spec.Private)})
+		}
+		if len(changes) > 0 {
+			actions = append(actions, Action{Type: Update, Collection: spec.Title, CollectionID: c.ID, Changes: changes})
+		}
+		var err error
+		if live, err = listPhotoIDs(ctx, p.client, c.ID); err != nil {
+			return nil, err
+		}
+	}
+
+	var id string
+	if c != nil {
+		id = c.ID
+	}
+	remove, add, moved := diffPhotos(live, spec.Photos)
+	for _, photoID := range remove {
+		actions = append(actions, Action{Type: RemovePhoto, Collection: spec.Title, CollectionID: id, PhotoID: photoID, Reorder: moved[photoID]})
+	}
+	for _, photoID := range add {
+		actions = append(actions, Action{Type: AddPhoto, Collection: spec.Title, CollectionID: id, PhotoID: photoID, Reorder: moved[photoID]})
+	}
+	return actions, nil
+}
+
+// diffPhotos compares a collection's live photos with the desired photos, both in the order the
+// collection lists them. The API lists the most recently added photo first, and has no way to move
+// a photo, so photos out of place are removed and added again. It returns the photos to remove, the
+// photos to add, in the order they must be added, and the photos that are moved.
+func diffPhotos(live, desired []string) (remove, add []string, moved map[string]bool) {
+	want := make(map[string]bool, len(desired))
+	for _, id := range desired {
+		want[id] = true
+	}
+	have := make(map[string]bool, len(live))
+	var kept []string
+	for _, id := range live {
+		have[id] = true
+		if want[id] {
+			kept = append(kept, id)
+		}
+	}
+
+	// the longest tail of desired whose photos are already listed in order stays in place,
+	// and everything listed before it is added, last first
+	i, j := len(desired), len(kept)-1
+	for i > 0 && have[desired[i-1]] {
+		for j >= 0 && kept[j] != desired[i-1] {
+			j--
+		}
+		if j < 0 {
+			break
+		}
+		i, j = i-1, j-1
+	}
+	stay := make(map[string]bool)
+	for _, id := range desired[i:] {
+		stay[id] = true
+	}
+
+	moved = make(map[string]bool)
+	for _, id := range live {
+		if !stay[id] {
+			remove = append(remove, id)
+			moved[id] = want[id]
+		}
+	}
+	for k := i - 1; k >= 0; k-- {
+		add = append(add, desired[k])
+	}
+	return remove, add, moved
+}
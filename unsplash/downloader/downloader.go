@@ -0,0 +1,399 @@
+// Package downloader downloads whole collections or users' photos for offline use.
+//
+// Photos are downloaded concurrently into a directory, alongside a `manifest.json` file
+// recording each photo's ID, photographer, source URL and SHA-256 checksum.
+// Interrupted downloads are resumed, and photos already present in the directory are skipped,
+// so a Manager can be run repeatedly against the same directory to keep it up to date.
+// Every download is tracked, as required by the Unsplash API guidelines.
+package downloader
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/eddogola/unsplash-go/unsplash/client"
+	"github.com/eddogola/unsplash-go/unsplash/utils"
+)
+
+// ManifestFile is the name of the manifest written to the download directory
+const ManifestFile = "manifest.json"
+
+// partSuffix is appended to the names of files being downloaded.
+// The suffix is dropped once the download completes.
+const partSuffix = ".part"
+
+// Client defines client methods used to list and download photos
+type Client interface {
+	GetCollectionPhotos(context.Context, string, client.QueryParams) ([]client.Photo, error)
+	GetUserPhotos(context.Context, string, client.QueryParams) ([]client.Photo, error)
+	ResumePhotoDownloadToFile(context.Context, *client.Photo, string, string, client.ProgressFunc) (int64, error)
+}
+
+// Manager downloads photos into a directory
+type Manager struct {
+	client Client
+	// Dir is the directory photos and the manifest are written to
+	Dir string
+	// Concurrency is the maximum number of photos downloaded at a time
+	Concurrency int
+	// PerPage is the number of photos requested per page when listing photos
+	PerPage int
+	// ResizeOptions, if not nil, are used to download resized photos instead of the full size images
+	ResizeOptions *utils.ResizeOptions
+	// Progress, if not nil, is called as each photo is downloaded
+	Progress func(photoID string, written, total int64)
+}
+
+// ManifestEntry defines the details recorded for every downloaded photo
+type ManifestEntry struct {
+	ID           string    `json:"id"`
+	Photographer string    `json:"photographer"`
+	Username     string    `json:"username"`
+	URL          string    `json:"url"`
+	File         string    `json:"file"`
+	Size         int64     `json:"size"`
+	SHA256       string    `json:"sha256"`
+	DownloadedAt time.Time `json:"downloaded_at"`
+}
+
+// Manifest lists the photos found in a download directory
+type Manifest struct {
+	Photos []ManifestEntry `json:"photos"`
+}
+
+// Failure records a photo that could not be downloaded
+type Failure struct {
+	PhotoID string
+	Err     error
+}
+
+func (f Failure) Error() string {
+	return fmt.Sprintf("photo %s: %v", f.PhotoID, f.Err)
+}
+
+// Report summarizes a Manager's run
+type Report struct {
+	Downloaded int
+	Skipped    int
+	Failures   []Failure
+	Manifest   *Manifest
+}
+
+// New constructs a new Manager writing to dir.
+// By default, four photos are downloaded at a time.
+func New(c Client, dir string) *Manager {
+	return &Manager{client: c, Dir: dir, Concurrency: 4, PerPage: 30}
+}
+
+// DownloadCollection downloads every photo in the given collection
+func (m *Manager) DownloadCollection(ctx context.Context, collectionID string) (*Report, error) {
+	pics, err := m.listAll(ctx, func(qp client.QueryParams) ([]client.Photo, error) {
+		return m.client.GetCollectionPhotos(ctx, collectionID, qp)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return m.DownloadPhotos(ctx, pics)
+}
+
+// DownloadUser downloads every photo uploaded by the given user
+func (m *Manager) DownloadUser(ctx context.Context, username string) (*Report, error) {
+	pics, err := m.listAll(ctx, func(qp client.QueryParams) ([]client.Photo, error) {
+		return m.client.GetUserPhotos(ctx, username, qp)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return m.DownloadPhotos(ctx, pics)
+}
+
+// DownloadPhotos downloads the given photos, then writes the manifest.
+// Failing to download a photo does not stop the other downloads; failures are
+// listed in the returned Report instead. Photos listed more than once are downloaded once.
+func (m *Manager) DownloadPhotos(ctx context.Context, pics []client.Photo) (*Report, error) {
+	if err := os.MkdirAll(m.Dir, 0755); err != nil {
+		return nil, err
+	}
+	pics = uniquePhotos(pics)
+	manifest, err := m.readManifest()
+	if err != nil {
+		return nil, err
+	}
+	known := make(map[string]ManifestEntry)
+	for _, entry := range manifest.Photos {
+		known[entry.ID] = entry
+	}
+
+	concurrency := m.Concurrency
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	var (
+		mu     sync.Mutex
+		wg     sync.WaitGroup
+		report = &Report{}
+		sem    = make(chan struct{}, concurrency)
+	)
+	for i := range pics {
+		pic := &pics[i]
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			mu.Lock()
+			report.Failures = append(report.Failures, Failure{pic.ID, ctx.Err()})
+			mu.Unlock()
+			continue
+		}
+		wg.Add(1)
+		go func() {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			mu.Lock()
+			entry, ok := known[pic.ID]
+			mu.Unlock()
+			if !ok {
+				// the file may be present without a manifest entry, e.g. if the manifest was lost
+				entry, ok = m.existing(pic)
+			}
+
+			skipped := ok && m.isComplete(entry)
+			if !skipped {
+				var err error
+				entry, err = m.download(ctx, pic)
+				if err != nil {
+					mu.Lock()
+					report.Failures = append(report.Failures, Failure{pic.ID, err})
+					mu.Unlock()
+					return
+				}
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			known[pic.ID] = entry
+			if skipped {
+				report.Skipped++
+			} else {
+				report.Downloaded++
+			}
+		}()
+	}
+	wg.Wait()
+
+	manifest.Photos = manifest.Photos[:0]
+	for _, entry := range known {
+		manifest.Photos = append(manifest.Photos, entry)
+	}
+	sort.Slice(manifest.Photos, func(i, j int) bool {
+		return manifest.Photos[i].ID < manifest.Photos[j].ID
+	})
+	sort.Slice(report.Failures, func(i, j int) bool {
+		return report.Failures[i].PhotoID < report.Failures[j].PhotoID
+	})
+	report.Manifest = manifest
+	return report, m.writeManifest(manifest)
+}
+
+// listAll requests pages using getPage until an incomplete page is returned
+func (m *Manager) listAll(ctx context.Context, getPage func(client.QueryParams) ([]client.Photo, error)) ([]client.Photo, error) {
+	perPage := m.PerPage
+	if perPage < 1 {
+		perPage = 30
+	}
+	var pics []client.Photo
+	for page := 1; ; page++ {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		res, err := getPage(client.QueryParams{
+			"page":     fmt.Sprint(page),
+			"per_page": fmt.Sprint(perPage),
+		})
+		if err != nil {
+			return nil, err
+		}
+		pics = append(pics, res...)
+		if len(res) < perPage {
+			return pics, nil
+		}
+	}
+}
+
+// uniquePhotos returns pics without the photos listed before, so that no two workers write
+// the same file
+func uniquePhotos(pics []client.Photo) []client.Photo {
+	seen := make(map[string]bool, len(pics))
+	unique := make([]client.Photo, 0, len(pics))
+	for _, pic := range pics {
+		if !seen[pic.ID] {
+			seen[pic.ID] = true
+			unique = append(unique, pic)
+		}
+	}
+	return unique
+}
+
+// isComplete checks whether the file recorded in entry is present, with the recorded size
+func (m *Manager) isComplete(entry ManifestEntry) bool {
+	info, err := os.Stat(filepath.Join(m.Dir, entry.File))
+	return err == nil && info.Size() == entry.Size
+}
+
+// target returns the link pic is downloaded from, and the name of the file it's downloaded to
+func (m *Manager) target(pic *client.Photo) (link, name string, err error) {
+	link = pic.URLs.Full
+	ext := "jpg"
+	if m.ResizeOptions != nil {
+		link, err = utils.BuildPhotoURL(pic, *m.ResizeOptions)
+		if err != nil {
+			return "", "", err
+		}
+		if m.ResizeOptions.ImageFormat != "" {
+			ext = m.ResizeOptions.ImageFormat
+		}
+	}
+	return link, pic.ID + "." + strings.ToLower(ext), nil
+}
+
+// existing returns a manifest entry for pic's file if it's already in the directory.
+// Downloads are renamed once complete, so the file holds the whole image.
+func (m *Manager) existing(pic *client.Photo) (ManifestEntry, bool) {
+	link, name, err := m.target(pic)
+	if err != nil {
+		return ManifestEntry{}, false
+	}
+	path := filepath.Join(m.Dir, name)
+	info, err := os.Stat(path)
+	if err != nil || !info.Mode().IsRegular() {
+		return ManifestEntry{}, false
+	}
+	sum, err := fileSHA256(path)
+	if err != nil {
+		return ManifestEntry{}, false
+	}
+	return newEntry(pic, link, name, info.Size(), sum, info.ModTime().UTC()), true
+}
+
+// download downloads pic into a partial file, renaming the file once it's complete
+func (m *Manager) download(ctx context.Context, pic *client.Photo) (ManifestEntry, error) {
+	link, name, err := m.target(pic)
+	if err != nil {
+		return ManifestEntry{}, err
+	}
+	path := filepath.Join(m.Dir, name)
+
+	var progress client.ProgressFunc
+	if m.Progress != nil {
+		progress = func(written, total int64) {
+			m.Progress(pic.ID, written, total)
+		}
+	}
+	size, err := m.client.ResumePhotoDownloadToFile(ctx, pic, link, path+partSuffix, progress)
+	if err != nil {
+		return ManifestEntry{}, err
+	}
+	if err := os.Rename(path+partSuffix, path); err != nil {
+		return ManifestEntry{}, err
+	}
+	sum, err := fileSHA256(path)
+	if err != nil {
+		return ManifestEntry{}, err
+	}
+
+	return newEntry(pic, link, name, size, sum, time.Now().UTC()), nil
+}
+
+func newEntry(pic *client.Photo, link, name string, size int64, sum string, downloadedAt time.Time) ManifestEntry {
+	return ManifestEntry{
+		ID:           pic.ID,
+		Photographer: pic.User.Name,
+		Username:     pic.User.Username,
+		URL:          link,
+		File:         name,
+		Size:         size,
+		SHA256:       sum,
+		DownloadedAt: downloadedAt,
+	}
+}
+
+func (m *Manager) readManifest() (*Manifest, error) {
+	var manifest Manifest
+	data, err := ioutil.ReadFile(filepath.Join(m.Dir, ManifestFile))
+	if os.IsNotExist(err) {
+		return &manifest, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return nil, fmt.Errorf("error parsing manifest: %v", err)
+	}
+	return &manifest, nil
+}
+
+func (m *Manager) writeManifest(manifest *Manifest) error {
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(filepath.Join(m.Dir, ManifestFile), data, 0644)
+}
+
+// Verify checks the files listed in the directory's manifest against their recorded checksums.
+// Returns the files that are missing or whose contents have changed.
+func (m *Manager) Verify() ([]Failure, error) {
+	manifest, err := m.readManifest()
+	if err != nil {
+		return nil, err
+	}
+	var failures []Failure
+	for _, entry := range manifest.Photos {
+		sum, err := fileSHA256(filepath.Join(m.Dir, entry.File))
+		if err != nil {
+			failures = append(failures, Failure{entry.ID, err})
+			continue
+		}
+		if sum != entry.SHA256 {
+			failures = append(failures, Failure{entry.ID, ErrChecksumMismatch{entry.File, entry.SHA256, sum}})
+		}
+	}
+	return failures, nil
+}
+
+// ErrChecksumMismatch is raised when a downloaded file's contents do not match
+// the checksum recorded in the manifest
+type ErrChecksumMismatch struct {
+	File     string
+	Expected string
+	Got      string
+}
+
+func (e ErrChecksumMismatch) Error() string {
+	return fmt.Sprintf("checksum mismatch for %s: expected %s but got %s", e.File, e.Expected, e.Got)
+}
+
+func fileSHA256(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
@@ -0,0 +1,184 @@
+package downloader
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/eddogola/unsplash-go/unsplash/client"
+)
+
+var images = map[string][]byte{
+	"a": bytes.Repeat([]byte("a"), 40*1024),
+	"b": bytes.Repeat([]byte("b"), 70*1024),
+}
+
+type mockClient struct {
+	*client.Client
+	pics []client.Photo
+}
+
+func (m *mockClient) GetCollectionPhotos(ctx context.Context, id string, queryParams client.QueryParams) ([]client.Photo, error) {
+	if queryParams["page"] != "1" {
+		return nil, nil
+	}
+	return m.pics, nil
+}
+
+func (m *mockClient) GetUserPhotos(ctx context.Context, username string, queryParams client.QueryParams) ([]client.Photo, error) {
+	return m.GetCollectionPhotos(ctx, username, queryParams)
+}
+
+func newTestServer(ranges *[]string) *httptest.Server {
+	var mu sync.Mutex
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := strings.TrimPrefix(r.URL.Path, "/images/")
+		if strings.HasPrefix(r.URL.Path, "/download/") {
+			fmt.Fprint(w, `{"url": ""}`)
+			return
+		}
+		img, ok := images[id]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		mu.Lock()
+		*ranges = append(*ranges, r.Header.Get("Range"))
+		mu.Unlock()
+		http.ServeContent(w, r, id, time.Time{}, bytes.NewReader(img))
+	}))
+}
+
+func testPhoto(server *httptest.Server, id string) client.Photo {
+	var p client.Photo
+	p.ID = id
+	p.User.Name = "Cole"
+	p.URLs.Full = server.URL + "/images/" + id
+	p.Links.DownloadLocation = server.URL + "/download/" + id
+	return p
+}
+
+func TestManager(t *testing.T) {
+	var ranges []string
+	server := newTestServer(&ranges)
+	defer server.Close()
+
+	dir, err := ioutil.TempDir("", "downloader")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	mc := &mockClient{
+		Client: client.New("", server.Client(), client.NewConfig()),
+		pics:   []client.Photo{testPhoto(server, "a"), testPhoto(server, "b"), testPhoto(server, "missing")},
+	}
+	m := New(mc, dir)
+
+	// a partially downloaded file should be resumed
+	if err := ioutil.WriteFile(filepath.Join(dir, "b.jpg"+partSuffix), images["b"][:1000], 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("download collection", func(t *testing.T) {
+		report, err := m.DownloadCollection(context.Background(), "1")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if report.Downloaded != 2 || report.Skipped != 0 {
+			t.Errorf("expected 2 downloaded and 0 skipped but got %v and %v", report.Downloaded, report.Skipped)
+		}
+		if len(report.Failures) != 1 || report.Failures[0].PhotoID != "missing" {
+			t.Errorf("expected a failure for photo `missing` but got %v", report.Failures)
+		}
+		for id, img := range images {
+			got, err := ioutil.ReadFile(filepath.Join(dir, id+".jpg"))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !bytes.Equal(got, img) {
+				t.Errorf("downloaded image %v does not match", id)
+			}
+		}
+		if len(report.Manifest.Photos) != 2 || report.Manifest.Photos[0].Photographer != "Cole" {
+			t.Errorf("unexpected manifest: %+v", report.Manifest)
+		}
+	})
+
+	t.Run("partial download resumed using range", func(t *testing.T) {
+		var found bool
+		for _, r := range ranges {
+			if r == "bytes=1000-" {
+				found = true
+			}
+		}
+		if !found {
+			t.Errorf("expected a range request resuming at byte 1000, got %v", ranges)
+		}
+	})
+
+	t.Run("present files skipped", func(t *testing.T) {
+		ranges = nil
+		report, err := m.DownloadUser(context.Background(), "cole")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if report.Skipped != 2 || report.Downloaded != 0 {
+			t.Errorf("expected 2 skipped and 0 downloaded but got %v and %v", report.Skipped, report.Downloaded)
+		}
+		if len(ranges) != 0 {
+			t.Errorf("expected no image requests but got %v", len(ranges))
+		}
+	})
+
+	t.Run("present files skipped without a manifest", func(t *testing.T) {
+		if err := os.Remove(filepath.Join(dir, ManifestFile)); err != nil {
+			t.Fatal(err)
+		}
+		ranges = nil
+		report, err := m.DownloadCollection(context.Background(), "1")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if report.Skipped != 2 || report.Downloaded != 0 || len(ranges) != 0 {
+			t.Errorf("expected 2 skipped and no image requests but got %v skipped and %v", report.Skipped, ranges)
+		}
+		if len(report.Manifest.Photos) != 2 || report.Manifest.Photos[1].Size != int64(len(images["b"])) {
+			t.Errorf("expected the manifest to be rebuilt from the files, got %+v", report.Manifest)
+		}
+	})
+
+	t.Run("photos listed twice downloaded once", func(t *testing.T) {
+		ranges = nil
+		m := New(mc, t.TempDir())
+		report, err := m.DownloadPhotos(context.Background(), []client.Photo{testPhoto(server, "a"), testPhoto(server, "a")})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if report.Downloaded != 1 || len(ranges) != 1 || len(report.Manifest.Photos) != 1 {
+			t.Errorf("expected 1 download but got %v downloaded in %v requests", report.Downloaded, len(ranges))
+		}
+	})
+
+	t.Run("verify checksums", func(t *testing.T) {
+		if err := ioutil.WriteFile(filepath.Join(dir, "a.jpg"), []byte("changed"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		failures, err := m.Verify()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(failures) != 1 || failures[0].PhotoID != "a" {
+			t.Errorf("expected a checksum failure for photo `a` but got %v", failures)
+		}
+	})
+}
@@ -0,0 +1,188 @@
+// Package exif parses the EXIF data Unsplash returns for photos, and summarizes the
+// camera gear and settings used across many photos.
+//
+// Unsplash returns exposure time, aperture and focal length as free-form strings,
+// e.g. "1/125", "2.8" and "50.0"; Parse turns them into numbers.
+package exif
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/eddogola/unsplash-go/unsplash/client"
+)
+
+// ExposureTime defines an exposure time as a fraction of seconds, e.g. 1/125
+type ExposureTime struct {
+	Numerator   int64
+	Denominator int64
+}
+
+// Seconds returns the exposure time in seconds
+func (e ExposureTime) Seconds() float64 {
+	if e.Denominator == 0 {
+		return 0
+	}
+	return float64(e.Numerator) / float64(e.Denominator)
+}
+
+// IsZero reports whether the exposure time is unknown
+func (e ExposureTime) IsZero() bool {
+	return e.Numerator == 0 || e.Denominator == 0
+}
+
+func (e ExposureTime) String() string {
+	if e.IsZero() {
+		return ""
+	}
+	if e.Denominator == 1 {
+		return fmt.Sprintf("%ds", e.Numerator)
+	}
+	return fmt.Sprintf("%d/%ds", e.Numerator, e.Denominator)
+}
+
+// Exif defines a photo's parsed EXIF data.
+// Fields that are absent, or could not be parsed, are left at their zero values.
+type Exif struct {
+	Make         string // normalized camera make, e.g. "Canon"
+	Model        string // normalized camera model, without the make, e.g. "EOS 5D Mark IV"
+	ExposureTime ExposureTime
+	FNumber      float64 // aperture f-number, e.g. 2.8
+	FocalLength  float64 // focal length in millimetres
+	ISO          int
+}
+
+// Camera returns the camera's make and model, e.g. "Canon EOS 5D Mark IV"
+func (e Exif) Camera() string {
+	return strings.TrimSpace(e.Make + " " + e.Model)
+}
+
+// ErrInvalidValue is raised when an EXIF value cannot be parsed
+type ErrInvalidValue struct {
+	Field string
+	Value string
+}
+
+func (e ErrInvalidValue) Error() string {
+	return fmt.Sprintf("invalid exif %s: %q", e.Field, e.Value)
+}
+
+// Parse parses the Photo's EXIF data.
+// An ErrInvalidValue is returned for the first value that could not be parsed, along with
+// the values that could.
+func Parse(pic *client.Photo) (Exif, error) {
+	var firstErr error
+	setErr := func(err error) {
+		if firstErr == nil {
+			firstErr = err
+		}
+	}
+
+	e := Exif{ISO: pic.Exif.ISO}
+	e.Make, e.Model = normalizeCamera(pic.Exif.Make, pic.Exif.Model)
+	if pic.Exif.ExposureTime != "" {
+		exposure, err := ParseExposureTime(pic.Exif.ExposureTime)
+		if err != nil {
+			setErr(err)
+		}
+		e.ExposureTime = exposure
+	}
+	if pic.Exif.Aperture != "" {
+		fNumber, err := parseNumber("aperture", strings.TrimPrefix(strings.ToLower(pic.Exif.Aperture), "f/"))
+		if err != nil {
+			setErr(err)
+		}
+		e.FNumber = fNumber
+	}
+	if pic.Exif.FocalLength != "" {
+		focalLength, err := parseNumber("focal length", strings.TrimSuffix(strings.ToLower(pic.Exif.FocalLength), "mm"))
+		if err != nil {
+			setErr(err)
+		}
+		e.FocalLength = focalLength
+	}
+	return e, firstErr
+}
+
+// ParseExposureTime parses an exposure time like "1/125", "0.5" or "2"
+func ParseExposureTime(s string) (ExposureTime, error) {
+	s = strings.TrimSuffix(strings.TrimSpace(s), "s")
+	if parts := strings.SplitN(s, "/", 2); len(parts) == 2 {
+		num, err1 := strconv.ParseInt(strings.TrimSpace(parts[0]), 10, 64)
+		den, err2 := strconv.ParseInt(strings.TrimSpace(parts[1]), 10, 64)
+		if err1 != nil || err2 != nil || num <= 0 || den <= 0 {
+			return ExposureTime{}, ErrInvalidValue{"exposure time", s}
+		}
+		return reduce(num, den), nil
+	}
+
+	secs, err := strconv.ParseFloat(s, 64)
+	if err != nil || secs <= 0 {
+		return ExposureTime{}, ErrInvalidValue{"exposure time", s}
+	}
+	if secs >= 1 {
+		return ExposureTime{int64(math.Round(secs)), 1}, nil
+	}
+	// express sub-second exposures the way cameras do, as 1/x
+	return ExposureTime{1, int64(math.Round(1 / secs))}, nil
+}
+
+func reduce(num, den int64) ExposureTime {
+	a, b := num, den
+	for b != 0 {
+		a, b = b, a%b
+	}
+	return ExposureTime{num / a, den / a}
+}
+
+func parseNumber(field, s string) (float64, error) {
+	n, err := strconv.ParseFloat(strings.TrimSpace(s), 64)
+	if err != nil || n < 0 {
+		return 0, ErrInvalidValue{field, s}
+	}
+	return n, nil
+}
+
+// makes maps lower cased camera makes, as written by cameras, to their common names
+var makes = map[string]string{
+	"canon":                       "Canon",
+	"nikon":                       "Nikon",
+	"nikon corporation":           "Nikon",
+	"sony":                        "Sony",
+	"fujifilm":                    "Fujifilm",
+	"olympus":                     "Olympus",
+	"olympus corporation":         "Olympus",
+	"olympus imaging corp.":       "Olympus",
+	"om digital solutions":        "OM System",
+	"panasonic":                   "Panasonic",
+	"leica":                       "Leica",
+	"leica camera ag":             "Leica",
+	"pentax":                      "Pentax",
+	"ricoh imaging company, ltd.": "Ricoh",
+	"apple":                       "Apple",
+	"samsung":                     "Samsung",
+	"google":                      "Google",
+	"hasselblad":                  "Hasselblad",
+	"dji":                         "DJI",
+	"gopro":                       "GoPro",
+}
+
+// normalizeCamera cleans up camera makes and models, which cameras write inconsistently,
+// e.g. make "NIKON CORPORATION" with model "NIKON D750" becomes "Nikon", "D750"
+func normalizeCamera(mk, model string) (string, string) {
+	mk = strings.Join(strings.Fields(mk), " ")
+	model = strings.Join(strings.Fields(model), " ")
+	if normalized, ok := makes[strings.ToLower(mk)]; ok {
+		mk = normalized
+	}
+	// drop the make from the model, or just its first word for makes like "Xiaomi Inc."
+	for _, prefix := range []string{mk, strings.SplitN(mk, " ", 2)[0]} {
+		if prefix != "" && len(model) > len(prefix) && strings.EqualFold(model[:len(prefix)], prefix) {
+			model = strings.TrimSpace(model[len(prefix):])
+			break
+		}
+	}
+	return mk, model
+}
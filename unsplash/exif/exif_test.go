@@ -0,0 +1,73 @@
+package exif
+
+import (
+	"testing"
+
+	"github.com/eddogola/unsplash-go/unsplash/client"
+)
+
+func testPhoto(mk, model, exposure, aperture, focal string, iso int) client.Photo {
+	var p client.Photo
+	p.Exif.Make = mk
+	p.Exif.Model = model
+	p.Exif.ExposureTime = exposure
+	p.Exif.Aperture = aperture
+	p.Exif.FocalLength = focal
+	p.Exif.ISO = iso
+	return p
+}
+
+func TestParse(t *testing.T) {
+	p := testPhoto("NIKON CORPORATION", "NIKON D750", "1/250", "2.8", "50.0", 100)
+	e, err := Parse(&p)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := Exif{"Nikon", "D750", ExposureTime{1, 250}, 2.8, 50, 100}
+	if e != expected {
+		t.Errorf("expected %+v but got %+v", expected, e)
+	}
+
+	exposures := map[string]ExposureTime{"2/500": {1, 250}, "0.5": {1, 2}, "30": {30, 1}, "0.008": {1, 125}}
+	for s, expected := range exposures {
+		got, err := ParseExposureTime(s)
+		if err != nil || got != expected {
+			t.Errorf("expected %v for %v but got %v, %v", expected, s, got, err)
+		}
+	}
+
+	p = testPhoto("Canon", "Canon EOS R5", "fast", "f/1.8", "35mm", 0)
+	e, err = Parse(&p)
+	if _, ok := err.(ErrInvalidValue); !ok {
+		t.Errorf("expected an ErrInvalidValue but got %v", err)
+	}
+	if e.Camera() != "Canon EOS R5" || e.FNumber != 1.8 || e.FocalLength != 35 {
+		t.Errorf("expected the valid values to be parsed, got %+v", e)
+	}
+}
+
+func TestAnalyze(t *testing.T) {
+	pics := []client.Photo{
+		testPhoto("Canon", "Canon EOS R5", "1/125", "2.8", "24.0", 100),
+		testPhoto("Canon", "EOS R5", "1/250", "4.0", "85.0", 400),
+		testPhoto("SONY", "ILCE-7M3", "1/125", "2.8", "50.0", 200),
+		{},
+	}
+	r := Analyze(pics)
+	if r.Photos != 4 || r.WithExif != 3 {
+		t.Errorf("expected 4 photos, 3 with exif, but got %v and %v", r.Photos, r.WithExif)
+	}
+	if r.Cameras[0] != (Count{"Canon EOS R5", 2}) {
+		t.Errorf("expected Canon EOS R5 to be the most used camera, got %v", r.Cameras)
+	}
+	if r.Apertures[0] != (Count{"f/2.8", 2}) || r.ExposureTimes[0] != (Count{"1/125s", 2}) {
+		t.Errorf("unexpected apertures %v or exposure times %v", r.Apertures, r.ExposureTimes)
+	}
+	if r.FocalLengths[1] != (Count{"wide", 1}) || r.FocalLengths[2] != (Count{"standard", 1}) ||
+		r.FocalLengths[3] != (Count{"short telephoto", 1}) {
+		t.Errorf("unexpected focal length histogram %v", r.FocalLengths)
+	}
+	if r.MedianISO != 200 || r.MedianFocalLength != 50 {
+		t.Errorf("expected median ISO 200 and focal length 50, got %v and %v", r.MedianISO, r.MedianFocalLength)
+	}
+}
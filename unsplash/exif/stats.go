@@ -0,0 +1,204 @@
+package exif
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/eddogola/unsplash-go/unsplash/client"
+)
+
+// Count defines the number of photos sharing a value, e.g. a camera
+type Count struct {
+	Value string `json:"value"`
+	Count int    `json:"count"`
+}
+
+// FocalLengthRange defines a range of focal lengths, in millimetres, that lenses are commonly grouped into
+type FocalLengthRange struct {
+	Name string  `json:"name"`
+	Min  float64 `json:"min"`
+	Max  float64 `json:"max"` // exclusive, 0 if unbounded
+}
+
+// FocalLengthRanges are the ranges used in a Report's focal length histogram
+var FocalLengthRanges = []FocalLengthRange{
+	{"ultra wide", 0, 17},
+	{"wide", 17, 35},
+	{"standard", 35, 70},
+	{"short telephoto", 70, 135},
+	{"telephoto", 135, 300},
+	{"super telephoto", 300, 0},
+}
+
+// Report summarizes the gear and settings used in a set of photos
+type Report struct {
+	Photos   int `json:"photos"`
+	WithExif int `json:"with_exif"`
+	// Cameras, Apertures, ExposureTimes and ISOs are sorted from the most used
+	Cameras       []Count `json:"cameras"`
+	FocalLengths  []Count `json:"focal_lengths"` // one Count per FocalLengthRanges entry, in order
+	Apertures     []Count `json:"apertures"`
+	ExposureTimes []Count `json:"exposure_times"`
+	ISOs          []Count `json:"isos"`
+	// Median settings, 0 if none of the photos had the setting
+	MedianFNumber      float64 `json:"median_f_number"`
+	MedianFocalLength  float64 `json:"median_focal_length"`
+	MedianExposureTime float64 `json:"median_exposure_time"` // in seconds
+	MedianISO          float64 `json:"median_iso"`
+}
+
+// Aggregator accumulates EXIF data from photos into a Report
+type Aggregator struct {
+	photos, withExif int
+	cameras          map[string]int
+	apertures        map[string]int
+	exposures        map[string]int
+	isos             map[string]int
+	focalRanges      []int
+	fNumbers         []float64
+	focalLengths     []float64
+	exposureTimes    []float64
+	isoValues        []float64
+}
+
+// NewAggregator constructs an empty Aggregator
+func NewAggregator() *Aggregator {
+	return &Aggregator{
+		cameras:     make(map[string]int),
+		apertures:   make(map[string]int),
+		exposures:   make(map[string]int),
+		isos:        make(map[string]int),
+		focalRanges: make([]int, len(FocalLengthRanges)),
+	}
+}
+
+// Add adds the photos' EXIF data to the aggregate.
+// Values that cannot be parsed are left out.
+func (a *Aggregator) Add(pics ...client.Photo) {
+	for i := range pics {
+		a.photos++
+		e, _ := Parse(&pics[i])
+		if e == (Exif{}) {
+			continue
+		}
+		a.withExif++
+		if camera := e.Camera(); camera != "" {
+			a.cameras[camera]++
+		}
+		if e.FNumber > 0 {
+			a.apertures[fmt.Sprintf("f/%g", e.FNumber)]++
+			a.fNumbers = append(a.fNumbers, e.FNumber)
+		}
+		if !e.ExposureTime.IsZero() {
+			a.exposures[e.ExposureTime.String()]++
+			a.exposureTimes = append(a.exposureTimes, e.ExposureTime.Seconds())
+		}
+		if e.ISO > 0 {
+			a.isos[fmt.Sprint(e.ISO)]++
+			a.isoValues = append(a.isoValues, float64(e.ISO))
+		}
+		if e.FocalLength > 0 {
+			a.focalLengths = append(a.focalLengths, e.FocalLength)
+			for j, r := range FocalLengthRanges {
+				if e.FocalLength >= r.Min && (r.Max == 0 || e.FocalLength < r.Max) {
+					a.focalRanges[j]++
+					break
+				}
+			}
+		}
+	}
+}
+
+// Report returns a summary of the photos added so far
+func (a *Aggregator) Report() *Report {
+	r := &Report{
+		Photos:             a.photos,
+		WithExif:           a.withExif,
+		Cameras:            sortedCounts(a.cameras),
+		Apertures:          sortedCounts(a.apertures),
+		ExposureTimes:      sortedCounts(a.exposures),
+		ISOs:               sortedCounts(a.isos),
+		MedianFNumber:      median(a.fNumbers),
+		MedianFocalLength:  median(a.focalLengths),
+		MedianExposureTime: median(a.exposureTimes),
+		MedianISO:          median(a.isoValues),
+	}
+	for i, fr := range FocalLengthRanges {
+		r.FocalLengths = append(r.FocalLengths, Count{fr.Name, a.focalRanges[i]})
+	}
+	return r
+}
+
+// Analyze summarizes the EXIF data of the given photos
+func Analyze(pics []client.Photo) *Report {
+	a := NewAggregator()
+	a.Add(pics...)
+	return a.Report()
+}
+
+// UserPhotosClient defines client methods used to get a user's photos
+type UserPhotosClient interface {
+	GetUserPhotos(context.Context, string, client.QueryParams) ([]client.Photo, error)
+	GetPhoto(context.Context, string) (*client.Photo, error)
+}
+
+// AnalyzeUser summarizes the EXIF data of all photos uploaded by the user, requesting
+// up to maxPages pages of the user's photos, or all pages if maxPages is 0.
+// Photos in lists are usually returned without EXIF data, so if hydrate is true,
+// every photo without EXIF data is requested individually, at the cost of a request per photo.
+func AnalyzeUser(ctx context.Context, c UserPhotosClient, username string, maxPages int, hydrate bool) (*Report, error) {
+	const perPage = 30
+	a := NewAggregator()
+	for page := 1; maxPages == 0 || page <= maxPages; page++ {
+		pics, err := c.GetUserPhotos(ctx, username, client.QueryParams{
+			"page":     fmt.Sprint(page),
+			"per_page": fmt.Sprint(perPage),
+		})
+		if err != nil {
+			return nil, err
+		}
+		for i := range pics {
+			if hydrate && pics[i].Exif.Make == "" && pics[i].Exif.Model == "" {
+				pic, err := c.GetPhoto(ctx, pics[i].ID)
+				if err != nil {
+					return nil, err
+				}
+				pics[i] = *pic
+			}
+		}
+		a.Add(pics...)
+		if len(pics) < perPage {
+			break
+		}
+	}
+	return a.Report(), nil
+}
+
+// sortedCounts sorts counts from the most common value, then by value
+func sortedCounts(m map[string]int) []Count {
+	counts := make([]Count, 0, len(m))
+	for val, n := range m {
+		counts = append(counts, Count{val, n})
+	}
+	sort.Slice(counts, func(i, j int) bool {
+		if counts[i].Count != counts[j].Count {
+			return counts[i].Count > counts[j].Count
+		}
+		return counts[i].Value < counts[j].Value
+	})
+	return counts
+}
+
+func median(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+	mid := len(sorted) / 2
+	if len(sorted)%2 == 0 {
+		return (sorted[mid-1] + sorted[mid]) / 2
+	}
+	return sorted[mid]
+}
@@ -0,0 +1,163 @@
+package geo
+
+import (
+	"encoding/xml"
+	"fmt"
+	"html"
+	"io"
+
+	"github.com/eddogola/unsplash-go/unsplash/attribution"
+	"github.com/eddogola/unsplash-go/unsplash/client"
+)
+
+// Exporter exports photo locations, with attribution properties built by its Attributor
+type Exporter struct {
+	Attributor *attribution.Attributor
+}
+
+// NewExporter constructs a new Exporter, given the application name used in attribution links
+func NewExporter(appName string) *Exporter {
+	return &Exporter{Attributor: attribution.New(appName)}
+}
+
+// FeatureCollection defines a GeoJSON FeatureCollection
+// https://tools.ietf.org/html/rfc7946
+type FeatureCollection struct {
+	Type     string    `json:"type"`
+	BBox     []float64 `json:"bbox,omitempty"`
+	Features []Feature `json:"features"`
+}
+
+// Feature defines a GeoJSON Feature
+type Feature struct {
+	Type       string                 `json:"type"`
+	ID         string                 `json:"id"`
+	Geometry   Point                  `json:"geometry"`
+	Properties map[string]interface{} `json:"properties"`
+}
+
+// Point defines a GeoJSON Point geometry. Coordinates are in longitude, latitude order.
+type Point struct {
+	Type        string     `json:"type"`
+	Coordinates [2]float64 `json:"coordinates"`
+}
+
+// GeoJSON returns a FeatureCollection with a Point Feature for every photo with a position.
+// Marshal it with encoding/json.
+func (e *Exporter) GeoJSON(pics []client.Photo) *FeatureCollection {
+	fc := &FeatureCollection{Type: "FeatureCollection", Features: []Feature{}}
+	if box, ok := Bounds(pics); ok {
+		fc.BBox = []float64{box.MinLongitude, box.MinLatitude, box.MaxLongitude, box.MaxLatitude}
+	}
+	for i := range pics {
+		pic := &pics[i]
+		if !HasPosition(pic) {
+			continue
+		}
+		pos := pic.Location.Position
+		fc.Features = append(fc.Features, Feature{
+			Type:       "Feature",
+			ID:         pic.ID,
+			Geometry:   Point{"Point", [2]float64{pos.Longitude, pos.Latitude}},
+			Properties: e.properties(pic),
+		})
+	}
+	return fc
+}
+
+func (e *Exporter) properties(pic *client.Photo) map[string]interface{} {
+	props := map[string]interface{}{
+		"id":          pic.ID,
+		"description": description(pic),
+		"thumb":       pic.URLs.Thumb,
+		"small":       pic.URLs.Small,
+		"color":       pic.Color,
+		"location":    pic.Location.Name,
+		"city":        pic.Location.City,
+		"country":     pic.Location.Country,
+	}
+	if e.Attributor != nil {
+		props["photographer"] = pic.User.Name
+		props["photographer_url"] = e.Attributor.PhotographerURL(pic)
+		props["photo_url"] = e.Attributor.PhotoURL(pic)
+		props["attribution"] = e.Attributor.Text(pic)
+		props["attribution_html"] = e.Attributor.HTML(pic)
+	}
+	return props
+}
+
+type kml struct {
+	XMLName  xml.Name    `xml:"http://www.opengis.net/kml/2.2 kml"`
+	Document kmlDocument `xml:"Document"`
+}
+
+type kmlDocument struct {
+	Name       string         `xml:"name"`
+	Placemarks []kmlPlacemark `xml:"Placemark"`
+}
+
+type kmlPlacemark struct {
+	ID          string   `xml:"id,attr"`
+	Name        string   `xml:"name"`
+	Description kmlCDATA `xml:"description"`
+	Point       struct {
+		Coordinates string `xml:"coordinates"`
+	} `xml:"Point"`
+}
+
+type kmlCDATA struct {
+	Text string `xml:",cdata"`
+}
+
+// WriteKML writes a KML document named name, with a Placemark for every photo with a position.
+// Each Placemark's description shows the photo's thumbnail and attribution.
+func (e *Exporter) WriteKML(w io.Writer, name string, pics []client.Photo) error {
+	doc := kml{Document: kmlDocument{Name: name}}
+	for i := range pics {
+		pic := &pics[i]
+		if !HasPosition(pic) {
+			continue
+		}
+		pm := kmlPlacemark{ID: pic.ID, Name: placemarkName(pic)}
+		pm.Description.Text = e.kmlDescription(pic)
+		pos := pic.Location.Position
+		pm.Point.Coordinates = fmt.Sprintf("%g,%g", pos.Longitude, pos.Latitude)
+		doc.Document.Placemarks = append(doc.Document.Placemarks, pm)
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(doc); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+func (e *Exporter) kmlDescription(pic *client.Photo) string {
+	desc := fmt.Sprintf(`<img src="%s" alt="%s">`, html.EscapeString(pic.URLs.Thumb), html.EscapeString(pic.AltDescription))
+	if e.Attributor != nil {
+		desc += "<p>" + e.Attributor.HTML(pic) + "</p>"
+	}
+	return desc
+}
+
+func placemarkName(pic *client.Photo) string {
+	if pic.Location.Name != "" {
+		return pic.Location.Name
+	}
+	if d := description(pic); d != "" {
+		return d
+	}
+	return pic.ID
+}
+
+func description(pic *client.Photo) string {
+	if pic.Description != "" {
+		return pic.Description
+	}
+	return pic.AltDescription
+}
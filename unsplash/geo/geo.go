@@ -0,0 +1,102 @@
+// Package geo exports photo locations as GeoJSON or KML, and filters photos by location.
+//
+// Photos without a position, i.e. with a latitude and longitude of 0, are skipped.
+package geo
+
+import (
+	"math"
+
+	"github.com/eddogola/unsplash-go/unsplash/client"
+)
+
+// earthRadius is the mean radius of the Earth in kilometres
+const earthRadius = 6371.0
+
+// HasPosition reports whether the Photo has a known position
+func HasPosition(pic *client.Photo) bool {
+	pos := pic.Location.Position
+	return pos.Latitude != 0 || pos.Longitude != 0
+}
+
+// WithPosition returns the photos that have a known position
+func WithPosition(pics []client.Photo) []client.Photo {
+	return filter(pics, func(lat, lon float64) bool { return true })
+}
+
+// BoundingBox defines an area between two latitudes and two longitudes.
+// If MinLongitude is greater than MaxLongitude, the box crosses the antimeridian.
+type BoundingBox struct {
+	MinLatitude  float64 `json:"min_latitude"`
+	MinLongitude float64 `json:"min_longitude"`
+	MaxLatitude  float64 `json:"max_latitude"`
+	MaxLongitude float64 `json:"max_longitude"`
+}
+
+// Contains reports whether the position is within the box
+func (b BoundingBox) Contains(lat, lon float64) bool {
+	if lat < b.MinLatitude || lat > b.MaxLatitude {
+		return false
+	}
+	if b.MinLongitude <= b.MaxLongitude {
+		return lon >= b.MinLongitude && lon <= b.MaxLongitude
+	}
+	return lon >= b.MinLongitude || lon <= b.MaxLongitude
+}
+
+// Bounds returns the smallest box containing every photo with a position.
+// ok is false if none of the photos has a position.
+func Bounds(pics []client.Photo) (box BoundingBox, ok bool) {
+	for i := range pics {
+		if !HasPosition(&pics[i]) {
+			continue
+		}
+		pos := pics[i].Location.Position
+		if !ok {
+			box = BoundingBox{pos.Latitude, pos.Longitude, pos.Latitude, pos.Longitude}
+			ok = true
+			continue
+		}
+		box.MinLatitude = math.Min(box.MinLatitude, pos.Latitude)
+		box.MaxLatitude = math.Max(box.MaxLatitude, pos.Latitude)
+		box.MinLongitude = math.Min(box.MinLongitude, pos.Longitude)
+		box.MaxLongitude = math.Max(box.MaxLongitude, pos.Longitude)
+	}
+	return box, ok
+}
+
+// WithinBoundingBox returns the photos positioned within the box
+func WithinBoundingBox(pics []client.Photo, box BoundingBox) []client.Photo {
+	return filter(pics, box.Contains)
+}
+
+// WithinRadius returns the photos positioned within radius kilometres of the given position
+func WithinRadius(pics []client.Photo, lat, lon, radius float64) []client.Photo {
+	return filter(pics, func(pLat, pLon float64) bool {
+		return Distance(lat, lon, pLat, pLon) <= radius
+	})
+}
+
+// Distance returns the great-circle distance in kilometres between two positions
+func Distance(lat1, lon1, lat2, lon2 float64) float64 {
+	// haversine formula
+	dLat := radians(lat2 - lat1)
+	dLon := radians(lon2 - lon1)
+	a := math.Sin(dLat/2)*math.Sin(dLat/2) +
+		math.Cos(radians(lat1))*math.Cos(radians(lat2))*math.Sin(dLon/2)*math.Sin(dLon/2)
+	return 2 * earthRadius * math.Asin(math.Min(1, math.Sqrt(a)))
+}
+
+func radians(deg float64) float64 {
+	return deg * math.Pi / 180
+}
+
+func filter(pics []client.Photo, keep func(lat, lon float64) bool) []client.Photo {
+	var kept []client.Photo
+	for i := range pics {
+		pos := pics[i].Location.Position
+		if HasPosition(&pics[i]) && keep(pos.Latitude, pos.Longitude) {
+			kept = append(kept, pics[i])
+		}
+	}
+	return kept
+}
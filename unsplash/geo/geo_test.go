@@ -0,0 +1,96 @@
+package geo
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"strings"
+	"testing"
+
+	"github.com/eddogola/unsplash-go/unsplash/client"
+)
+
+func testPhoto(id string, lat, lon float64) client.Photo {
+	var p client.Photo
+	p.ID = id
+	p.User.Name = "Jane"
+	p.URLs.Thumb = "https://images.unsplash.com/" + id + "?w=200"
+	p.Location.Position.Latitude = lat
+	p.Location.Position.Longitude = lon
+	return p
+}
+
+var testPhotos = []client.Photo{
+	testPhoto("nairobi", -1.286389, 36.817223),
+	testPhoto("mombasa", -4.043477, 39.668206),
+	testPhoto("nowhere", 0, 0),
+	testPhoto("fiji", -17.7134, 178.0650),
+	testPhoto("samoa", -13.7590, -172.1046),
+}
+
+func TestFilters(t *testing.T) {
+	t.Run("radius", func(t *testing.T) {
+		got := WithinRadius(testPhotos, -1.3, 36.8, 50)
+		if len(got) != 1 || got[0].ID != "nairobi" {
+			t.Errorf("expected only nairobi within 50km, got %v", got)
+		}
+		if d := Distance(-1.286389, 36.817223, -4.043477, 39.668206); d < 430 || d > 450 {
+			t.Errorf("expected about 440km between Nairobi and Mombasa, got %v", d)
+		}
+	})
+
+	t.Run("bounding box", func(t *testing.T) {
+		got := WithinBoundingBox(testPhotos, BoundingBox{-5, 30, 5, 42})
+		if len(got) != 2 {
+			t.Errorf("expected 2 photos in Kenya, got %v", len(got))
+		}
+		// a box crossing the antimeridian
+		got = WithinBoundingBox(testPhotos, BoundingBox{-20, 170, -10, -170})
+		if len(got) != 2 || got[0].ID != "fiji" || got[1].ID != "samoa" {
+			t.Errorf("expected fiji and samoa across the antimeridian, got %v", got)
+		}
+	})
+}
+
+func TestExport(t *testing.T) {
+	e := NewExporter("my_app")
+
+	t.Run("geojson", func(t *testing.T) {
+		data, err := json.Marshal(e.GeoJSON(testPhotos))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		var fc FeatureCollection
+		if err := json.Unmarshal(data, &fc); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(fc.Features) != 4 {
+			t.Fatalf("expected 4 features but got %v", len(fc.Features))
+		}
+		f := fc.Features[0]
+		if f.Geometry.Coordinates != [2]float64{36.817223, -1.286389} {
+			t.Errorf("expected longitude, latitude coordinates but got %v", f.Geometry.Coordinates)
+		}
+		if !strings.Contains(f.Properties["photographer_url"].(string), "utm_source=my_app") {
+			t.Errorf("expected attribution properties, got %v", f.Properties)
+		}
+	})
+
+	t.Run("kml", func(t *testing.T) {
+		var buf bytes.Buffer
+		if err := e.WriteKML(&buf, "Kenya", testPhotos[:3]); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		var doc kml
+		if err := xml.Unmarshal(buf.Bytes(), &doc); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(doc.Document.Placemarks) != 2 {
+			t.Fatalf("expected 2 placemarks but got %v", len(doc.Document.Placemarks))
+		}
+		pm := doc.Document.Placemarks[1]
+		if pm.Point.Coordinates != "39.668206,-4.043477" || !strings.Contains(pm.Description.Text, "<img src=") {
+			t.Errorf("unexpected placemark %+v", pm)
+		}
+	})
+}
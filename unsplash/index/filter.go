@@ -0,0 +1,87 @@
+package index
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/eddogola/unsplash-go/unsplash/client"
+	"github.com/eddogola/unsplash-go/unsplash/colors"
+)
+
+// Photo orientations, as accepted by the `orientation` query parameter when searching photos
+const (
+	Landscape = "landscape"
+	Portrait  = "portrait"
+	Squarish  = "squarish"
+)
+
+// squarishTolerance is how far from 1 a photo's aspect ratio can be for it to be squarish
+const squarishTolerance = 0.1
+
+// Orientation returns the orientation of a photo with the dimensions, or an empty string if
+// they're unknown
+func Orientation(width, height int) string {
+	if width <= 0 || height <= 0 {
+		return ""
+	}
+	ratio := float64(width) / float64(height)
+	switch {
+	case ratio > 1+squarishTolerance:
+		return Landscape
+	case ratio < 1-squarishTolerance:
+		return Portrait
+	}
+	return Squarish
+}
+
+// Filter restricts search results. Fields left empty don't restrict the results.
+type Filter struct {
+	// Color is one of the color names in package colors, e.g. colors.Blue,
+	// matched against the name of the photo's dominant color
+	Color string
+	// Orientation is Landscape, Portrait or Squarish
+	Orientation string
+	// MinWidth and MinHeight are the minimum dimensions, in pixels
+	MinWidth, MinHeight int
+	// Country is matched against the photo's location's country, ignoring case
+	Country string
+}
+
+func (f Filter) validate() error {
+	switch f.Color {
+	case "", colors.BlackAndWhite, colors.Black, colors.White, colors.Yellow, colors.Orange, colors.Red,
+		colors.Purple, colors.Magenta, colors.Green, colors.Teal, colors.Blue:
+	default:
+		return fmt.Errorf("unknown color %q", f.Color)
+	}
+	switch f.Orientation {
+	case "", Landscape, Portrait, Squarish:
+	default:
+		return fmt.Errorf("unknown orientation %q, use landscape, portrait or squarish", f.Orientation)
+	}
+	return nil
+}
+
+func (f Filter) matches(pic *client.Photo) bool {
+	if f.MinWidth > 0 && pic.Width < f.MinWidth || f.MinHeight > 0 && pic.Height < f.MinHeight {
+		return false
+	}
+	if f.Orientation != "" && Orientation(pic.Width, pic.Height) != f.Orientation {
+		return false
+	}
+	if f.Country != "" && !strings.EqualFold(strings.TrimSpace(pic.Location.Country), strings.TrimSpace(f.Country)) {
+		return false
+	}
+	if f.Color != "" {
+		c, err := colors.PhotoColor(pic)
+		if err != nil {
+			return false
+		}
+		name := colors.Name(c)
+		// black and white photos also match the black and white colors
+		if name != f.Color && !(f.Color == colors.BlackAndWhite && (name == colors.Black || name == colors.White)) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,179 @@
+// Package index searches photos offline, without API calls.
+//
+// An Index is an in-memory inverted index over the text of photos already fetched: their
+// descriptions and alt text, tags, photographer names and usernames, and locations. Photos
+// are added and removed incrementally, and the index can be saved to disk and opened again.
+//
+// Queries support boolean operators, phrases and fields, and results are ranked by BM25:
+//
+//	misty forest                 both words
+//	"misty forest"               the phrase
+//	forest OR woods -snow        either word, without snow
+//	(lake OR river) tag:autumn   in a tag
+//	user:jane location:"new york"
+//
+// Results can be filtered by color, orientation, resolution and country.
+package index
+
+import (
+	"sort"
+	"strings"
+	"sync"
+	"unicode"
+
+	"github.com/eddogola/unsplash-go/unsplash/client"
+)
+
+// Fields that query terms can be limited to, e.g. `tag:forest`
+const (
+	FieldDescription = "description" // description and alt text
+	FieldTag         = "tag"
+	FieldUser        = "user" // photographer's name and username
+	FieldLocation    = "location"
+)
+
+var fields = []string{FieldDescription, FieldTag, FieldUser, FieldLocation}
+
+// spanGap separates the positions of a document's spans, so that phrases don't match across them
+const spanGap = 10
+
+// BM25 parameters
+const (
+	k1 = 1.2
+	b  = 0.75
+)
+
+// Index is an inverted index of photos. It's safe for concurrent use.
+type Index struct {
+	mu       sync.RWMutex
+	docs     map[string]*doc             // by photo ID
+	postings map[string]map[string][]int // term -> photo ID -> positions
+	total    int                         // sum of the docs' lengths
+}
+
+// doc defines an indexed photo
+type doc struct {
+	Photo  client.Photo
+	Length int      // number of terms
+	Spans  []span   // the positions of each field's text
+	Terms  []string // distinct terms, to remove the photo's postings
+}
+
+// span defines the positions of one piece of a field's text, e.g. one tag
+type span struct {
+	Field      string
+	Start, End int
+}
+
+// New constructs an empty Index
+func New() *Index {
+	return &Index{docs: make(map[string]*doc), postings: make(map[string]map[string][]int)}
+}
+
+// Len returns the number of photos indexed
+func (ix *Index) Len() int {
+	ix.mu.RLock()
+	defer ix.mu.RUnlock()
+	return len(ix.docs)
+}
+
+// Photo returns the indexed photo with the ID
+func (ix *Index) Photo(id string) (client.Photo, bool) {
+	ix.mu.RLock()
+	defer ix.mu.RUnlock()
+	d, ok := ix.docs[id]
+	if !ok {
+		return client.Photo{}, false
+	}
+	return d.Photo, true
+}
+
+// Add indexes photos, replacing photos already indexed with the same IDs
+func (ix *Index) Add(pics ...client.Photo) {
+	ix.mu.Lock()
+	defer ix.mu.Unlock()
+	for _, pic := range pics {
+		ix.remove(pic.ID)
+		ix.add(pic)
+	}
+}
+
+// Remove removes the photos with the IDs from the index, ignoring IDs not indexed
+func (ix *Index) Remove(ids ...string) {
+	ix.mu.Lock()
+	defer ix.mu.Unlock()
+	for _, id := range ids {
+		ix.remove(id)
+	}
+}
+
+func (ix *Index) add(pic client.Photo) {
+	d := &doc{Photo: pic}
+	positions := make(map[string][]int)
+	pos := 0
+	addText := func(field, text string) {
+		terms := tokenize(text)
+		if len(terms) == 0 {
+			return
+		}
+		d.Spans = append(d.Spans, span{field, pos, pos + len(terms)})
+		for i, t := range terms {
+			positions[t] = append(positions[t], pos+i)
+		}
+		d.Length += len(terms)
+		pos += len(terms) + spanGap
+	}
+	addText(FieldDescription, pic.Description)
+	addText(FieldDescription, pic.AltDescription)
+	for _, tag := range pic.Tags {
+		addText(FieldTag, tag.Title)
+	}
+	addText(FieldUser, pic.User.Name)
+	addText(FieldUser, pic.User.Username)
+	addText(FieldLocation, pic.Location.Name)
+	addText(FieldLocation, pic.Location.City)
+	addText(FieldLocation, pic.Location.Country)
+
+	for t, p := range positions {
+		if ix.postings[t] == nil {
+			ix.postings[t] = make(map[string][]int)
+		}
+		ix.postings[t][pic.ID] = p
+		d.Terms = append(d.Terms, t)
+	}
+	sort.Strings(d.Terms)
+	ix.docs[pic.ID] = d
+	ix.total += d.Length
+}
+
+func (ix *Index) remove(id string) {
+	d, ok := ix.docs[id]
+	if !ok {
+		return
+	}
+	for _, t := range d.Terms {
+		delete(ix.postings[t], id)
+		if len(ix.postings[t]) == 0 {
+			delete(ix.postings, t)
+		}
+	}
+	delete(ix.docs, id)
+	ix.total -= d.Length
+}
+
+// tokenize splits text into lower case terms of letters and digits
+func tokenize(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+}
+
+// inField reports whether the position is in one of the doc's spans of the field
+func (d *doc) inField(field string, pos int) bool {
+	for _, s := range d.Spans {
+		if s.Field == field && pos >= s.Start && pos < s.End {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,239 @@
+package index
+
+import (
+	"bytes"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/eddogola/unsplash-go/unsplash/client"
+	"github.com/eddogola/unsplash-go/unsplash/colors"
+)
+
+func photo(id, description string, tags ...string) client.Photo {
+	pic := client.Photo{ID: id, Description: description, Width: 6000, Height: 4000, Color: "#2980b9"}
+	for _, tag := range tags {
+		pic.Tags = append(pic.Tags, client.Tag{Title: tag})
+	}
+	return pic
+}
+
+func testIndex() *Index {
+	forest := photo("forest", "A misty forest at dawn", "forest", "fog")
+	forest.User.Name, forest.User.Username = "Jane Doe", "janed"
+	forest.Location.Name, forest.Location.Country = "Black Forest", "Germany"
+	woods := photo("woods", "Forest path in the woods, forest trail in autumn", "autumn")
+	woods.Likes = 10
+	woods.Width, woods.Height = 3000, 4500
+	woods.Color = "#c0392b"
+	snow := photo("snow", "Snowy forest", "snow", "winter")
+	snow.Width, snow.Height = 1000, 1050
+	lake := photo("lake", "Misty lake forest", "lake")
+	lake.Location.City, lake.Location.Country = "New York", "United States"
+
+	ix := New()
+	ix.Add(forest, woods, snow, lake)
+	return ix
+}
+
+func ids(results []Result) []string {
+	var res []string
+	for _, r := range results {
+		res = append(res, r.Photo.ID)
+	}
+	return res
+}
+
+func search(t *testing.T, ix *Index, query string, filter Filter) []string {
+	t.Helper()
+	results, err := ix.Search(query, filter, 0)
+	if err != nil {
+		t.Fatalf("unexpected error searching %q: %v", query, err)
+	}
+	return ids(results)
+}
+
+func TestIndex(t *testing.T) {
+	ix := testIndex()
+
+	t.Run("queries", func(t *testing.T) {
+		cases := map[string][]string{
+			"misty":                      {"forest", "lake"},
+			"MISTY forest":               {"forest", "lake"},
+			`"misty forest"`:             {"forest"},
+			`"forest misty"`:             nil,
+			"snow OR autumn":             {"snow", "woods"},
+			"forest -snow -autumn -lake": {"forest"},
+			"forest NOT (snow OR woods)": {"forest", "lake"},
+			"tag:forest":                 {"forest"},
+			"tag:fog AND dawn":           {"forest"},
+			"user:jane":                  {"forest"},
+			"user:janed":                 {"forest"},
+			`location:"new york"`:        {"lake"},
+			"location:germany":           {"forest"},
+			"description:germany":        nil,
+			"forest:trail":               {"woods"}, // not a field, the phrase "forest trail"
+			"nothing":                    nil,
+		}
+		for query, expected := range cases {
+			got := search(t, ix, query, Filter{})
+			if len(got) == 0 && len(expected) == 0 {
+				continue
+			}
+			if len(got) != len(expected) {
+				t.Errorf("%q: expected %v but got %v", query, expected, got)
+				continue
+			}
+			seen := make(map[string]bool)
+			for _, id := range got {
+				seen[id] = true
+			}
+			for _, id := range expected {
+				if !seen[id] {
+					t.Errorf("%q: expected %v but got %v", query, expected, got)
+					break
+				}
+			}
+		}
+	})
+
+	t.Run("ranking", func(t *testing.T) {
+		results, err := ix.Search("forest", Filter{}, 0)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		// forest mentions forest in its description, tags and location
+		if results[0].Photo.ID != "forest" {
+			t.Errorf("expected forest first but got %v", ids(results))
+		}
+		// woods mentions it twice in a long description, snow once in a short one
+		if results[1].Photo.ID != "woods" {
+			t.Errorf("expected woods second but got %v", ids(results))
+		}
+		for i := 1; i < len(results); i++ {
+			if results[i].Score > results[i-1].Score {
+				t.Errorf("results not ordered by score: %v", results)
+			}
+		}
+		if got := search(t, ix, "", Filter{}); got[0] != "woods" {
+			t.Errorf("expected an empty query to order by likes but got %v", got)
+		}
+		if results, _ := ix.Search("forest", Filter{}, 2); len(results) != 2 {
+			t.Errorf("expected 2 results but got %d", len(results))
+		}
+	})
+
+	t.Run("filters", func(t *testing.T) {
+		cases := []struct {
+			filter   Filter
+			expected []string
+		}{
+			{Filter{Orientation: Portrait}, []string{"woods"}},
+			{Filter{Orientation: Squarish}, []string{"snow"}},
+			{Filter{Color: colors.Red}, []string{"woods"}},
+			{Filter{MinWidth: 4000}, []string{"forest", "lake"}},
+			{Filter{MinHeight: 4500}, []string{"woods"}},
+			{Filter{Country: "germany"}, []string{"forest"}},
+			{Filter{Orientation: Landscape, Country: "United States"}, []string{"lake"}},
+		}
+		for _, c := range cases {
+			got := search(t, ix, "forest", c.filter)
+			if len(got) == 2 && got[0] > got[1] {
+				got[0], got[1] = got[1], got[0]
+			}
+			if !reflect.DeepEqual(got, c.expected) {
+				t.Errorf("%+v: expected %v but got %v", c.filter, c.expected, got)
+			}
+		}
+		for _, f := range []Filter{{Color: "beige"}, {Orientation: "diagonal"}} {
+			if _, err := ix.Search("forest", f, 0); err == nil {
+				t.Errorf("expected an error filtering by %+v", f)
+			}
+		}
+	})
+
+	t.Run("invalid queries", func(t *testing.T) {
+		for _, query := range []string{`"misty forest`, "(forest", "forest)", "forest NOT", "tag:", "OR forest", "forest OR", "NOT AND"} {
+			_, err := ix.Search(query, Filter{}, 0)
+			if _, ok := err.(ErrInvalidQuery); !ok {
+				t.Errorf("expected ErrInvalidQuery searching %q but got %v", query, err)
+			}
+		}
+	})
+}
+
+func TestIncrementalUpdates(t *testing.T) {
+	ix := testIndex()
+	if ix.Len() != 4 {
+		t.Fatalf("expected 4 photos but got %d", ix.Len())
+	}
+
+	ix.Remove("forest", "unknown")
+	if got := search(t, ix, "misty", Filter{}); !reflect.DeepEqual(got, []string{"lake"}) {
+		t.Errorf("expected removed photo not to match but got %v", got)
+	}
+	if got := search(t, ix, "dawn", Filter{}); got != nil {
+		t.Errorf("expected removed photo's terms to be gone but got %v", got)
+	}
+	if _, ok := ix.Photo("forest"); ok {
+		t.Error("expected removed photo not to be found")
+	}
+
+	// adding a photo again replaces it
+	ix.Add(photo("lake", "Calm lake"))
+	if got := search(t, ix, "misty", Filter{}); got != nil {
+		t.Errorf("expected replaced photo's old terms to be gone but got %v", got)
+	}
+	if got := search(t, ix, "calm", Filter{}); !reflect.DeepEqual(got, []string{"lake"}) {
+		t.Errorf("expected replaced photo's new terms to match but got %v", got)
+	}
+	if ix.Len() != 3 {
+		t.Errorf("expected 3 photos but got %d", ix.Len())
+	}
+}
+
+func TestSaveAndLoad(t *testing.T) {
+	ix := testIndex()
+	path := filepath.Join(t.TempDir(), "photos.idx")
+	if err := ix.SaveFile(path); err != nil {
+		t.Fatalf("unexpected error saving: %v", err)
+	}
+	loaded, err := OpenFile(path)
+	if err != nil {
+		t.Fatalf("unexpected error opening: %v", err)
+	}
+	for _, query := range []string{"forest", `"misty forest"`, "tag:autumn", "user:jane"} {
+		expected, _ := ix.Search(query, Filter{}, 0)
+		got, _ := loaded.Search(query, Filter{}, 0)
+		if !reflect.DeepEqual(ids(got), ids(expected)) {
+			t.Errorf("%q: expected %v but got %v", query, ids(expected), ids(got))
+		}
+		for i := range got {
+			if got[i].Score != expected[i].Score {
+				t.Errorf("%q: expected score %v but got %v", query, expected[i].Score, got[i].Score)
+			}
+		}
+	}
+	pic, ok := loaded.Photo("forest")
+	if !ok || pic.Location.Name != "Black Forest" {
+		t.Errorf("expected loaded photo details but got %+v", pic)
+	}
+
+	// the loaded index keeps updating
+	loaded.Add(photo("sea", "Misty sea"))
+	if got := search(t, loaded, "sea", Filter{}); !reflect.DeepEqual(got, []string{"sea"}) {
+		t.Errorf("expected new photo to match but got %v", got)
+	}
+
+	var buf bytes.Buffer
+	if err := New().Save(&buf); err != nil {
+		t.Fatalf("unexpected error saving: %v", err)
+	}
+	empty, err := Load(&buf)
+	if err != nil || empty.Len() != 0 {
+		t.Errorf("expected an empty index but got %v, %v", empty, err)
+	}
+	if _, err := Load(bytes.NewReader([]byte("not an index"))); err == nil {
+		t.Error("expected an error loading garbage")
+	}
+}
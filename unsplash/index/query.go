@@ -0,0 +1,395 @@
+package index
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/eddogola/unsplash-go/unsplash/client"
+)
+
+// ErrInvalidQuery is raised when a query cannot be parsed
+type ErrInvalidQuery string
+
+func (e ErrInvalidQuery) Error() string {
+	return "invalid query: " + string(e)
+}
+
+// Result defines a photo matching a query
+type Result struct {
+	Photo client.Photo
+	Score float64 // BM25 score, 0 for photos matched only by filters or negations
+}
+
+// Search returns the photos matching query and filter, best match first. Photos with the same score
+// are ordered by likes, most first. An empty query matches every photo. limit bounds the number of
+// results, unless it's 0.
+func (ix *Index) Search(query string, filter Filter, limit int) ([]Result, error) {
+	n, err := parseQuery(query)
+	if err != nil {
+		return nil, err
+	}
+	if err := filter.validate(); err != nil {
+		return nil, err
+	}
+
+	ix.mu.RLock()
+	defer ix.mu.RUnlock()
+	var matches map[string]float64
+	if n == nil {
+		matches = ix.all()
+	} else {
+		matches = n.eval(ix)
+	}
+	results := make([]Result, 0, len(matches))
+	for id, score := range matches {
+		if filter.matches(&ix.docs[id].Photo) {
+			results = append(results, Result{ix.docs[id].Photo, score})
+		}
+	}
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].Score != results[j].Score {
+			return results[i].Score > results[j].Score
+		}
+		pi, pj := &results[i].Photo, &results[j].Photo
+		if pi.Likes != pj.Likes {
+			return pi.Likes > pj.Likes
+		}
+		return pi.ID < pj.ID
+	})
+	if limit > 0 && len(results) > limit {
+		results = results[:limit]
+	}
+	return results, nil
+}
+
+// node is a parsed query, evaluated to the scores of the photos it matches.
+// The caller must hold the index's read lock.
+type node interface {
+	eval(ix *Index) map[string]float64
+}
+
+// termNode matches a term, or a phrase of several terms, optionally in a field
+type termNode struct {
+	field string
+	terms []string
+}
+
+type andNode []node
+type orNode []node
+type notNode struct{ n node }
+
+func (ix *Index) all() map[string]float64 {
+	res := make(map[string]float64, len(ix.docs))
+	for id := range ix.docs {
+		res[id] = 0
+	}
+	return res
+}
+
+func (n termNode) eval(ix *Index) map[string]float64 {
+	freqs := ix.frequencies(n)
+	res := make(map[string]float64, len(freqs))
+	if len(freqs) == 0 {
+		return res
+	}
+	docs := float64(len(ix.docs))
+	avgLength := float64(ix.total) / docs
+	idf := math.Log(1 + (docs-float64(len(freqs))+0.5)/(float64(len(freqs))+0.5))
+	for id, tf := range freqs {
+		length := float64(ix.docs[id].Length)
+		res[id] = idf * tf * (k1 + 1) / (tf + k1*(1-b+b*length/avgLength))
+	}
+	return res
+}
+
+// frequencies returns the number of times the term or phrase occurs in each photo matching it
+func (ix *Index) frequencies(n termNode) map[string]float64 {
+	freqs := make(map[string]float64)
+	first := ix.postings[n.terms[0]]
+outer:
+	for id, positions := range first {
+		rest := make([][]int, len(n.terms)-1)
+		for i, t := range n.terms[1:] {
+			if rest[i] = ix.postings[t][id]; rest[i] == nil {
+				continue outer
+			}
+		}
+		d := ix.docs[id]
+		count := 0
+		for _, p := range positions {
+			if n.field != "" && !d.inField(n.field, p) {
+				continue
+			}
+			if followedBy(p, rest) {
+				count++
+			}
+		}
+		if count > 0 {
+			freqs[id] = float64(count)
+		}
+	}
+	return freqs
+}
+
+// followedBy reports whether each list in rest holds the position after the previous one's
+func followedBy(p int, rest [][]int) bool {
+	for i, positions := range rest {
+		want := p + i + 1
+		j := sort.SearchInts(positions, want)
+		if j == len(positions) || positions[j] != want {
+			return false
+		}
+	}
+	return true
+}
+
+func (n andNode) eval(ix *Index) map[string]float64 {
+	res := n[0].eval(ix)
+	for _, child := range n[1:] {
+		scores := child.eval(ix)
+		for id, score := range res {
+			s, ok := scores[id]
+			if !ok {
+				delete(res, id)
+				continue
+			}
+			res[id] = score + s
+		}
+	}
+	return res
+}
+
+func (n orNode) eval(ix *Index) map[string]float64 {
+	res := make(map[string]float64)
+	for _, child := range n {
+		for id, score := range child.eval(ix) {
+			res[id] += score
+		}
+	}
+	return res
+}
+
+func (n notNode) eval(ix *Index) map[string]float64 {
+	res := ix.all()
+	for id := range n.n.eval(ix) {
+		delete(res, id)
+	}
+	return res
+}
+
+// token kinds of the query language
+const (
+	tokWord = iota
+	tokPhrase
+	tokLeft
+	tokRight
+	tokOr
+	tokAnd
+	tokNot
+)
+
+type token struct {
+	kind  int
+	field string
+	text  string
+}
+
+// lex splits a query into tokens
+func lex(query string) ([]token, error) {
+	var tokens []token
+	rs := []rune(query)
+	for i := 0; i < len(rs); {
+		r := rs[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '(':
+			tokens = append(tokens, token{kind: tokLeft})
+			i++
+		case r == ')':
+			tokens = append(tokens, token{kind: tokRight})
+			i++
+		case r == '-' && i+1 < len(rs) && !unicode.IsSpace(rs[i+1]):
+			tokens = append(tokens, token{kind: tokNot})
+			i++
+		default:
+			start := i
+			for i < len(rs) && !unicode.IsSpace(rs[i]) && rs[i] != '(' && rs[i] != ')' && rs[i] != '"' {
+				i++
+			}
+			word := string(rs[start:i])
+			var field string
+			if j := strings.IndexRune(word, ':'); j > 0 && isField(strings.ToLower(word[:j])) {
+				field, word = strings.ToLower(word[:j]), word[j+1:]
+			}
+			if word == "" && i < len(rs) && rs[i] == '"' {
+				end := i + 1
+				for end < len(rs) && rs[end] != '"' {
+					end++
+				}
+				if end == len(rs) {
+					return nil, ErrInvalidQuery("unterminated phrase")
+				}
+				tokens = append(tokens, token{tokPhrase, field, string(rs[i+1 : end])})
+				i = end + 1
+				continue
+			}
+			switch {
+			case word == "OR" && field == "":
+				tokens = append(tokens, token{kind: tokOr})
+			case word == "AND" && field == "":
+				tokens = append(tokens, token{kind: tokAnd})
+			case word == "NOT" && field == "":
+				tokens = append(tokens, token{kind: tokNot})
+			case word == "" && field != "":
+				return nil, ErrInvalidQuery(fmt.Sprintf("nothing to search for in field %q", field))
+			case word != "":
+				tokens = append(tokens, token{tokWord, field, word})
+			}
+		}
+	}
+	return tokens, nil
+}
+
+func isField(name string) bool {
+	for _, f := range fields {
+		if name == f {
+			return true
+		}
+	}
+	return false
+}
+
+// parser parses the query language:
+//
+//	or      = and { "OR" and }
+//	and     = unary { ["AND"] unary }
+//	unary   = ( "-" | "NOT" ) unary | primary
+//	primary = "(" or ")" | [field ":"] ( word | `"` phrase `"` )
+type parser struct {
+	tokens []token
+	pos    int
+}
+
+// parseQuery parses query, returning nil if it has no terms
+func parseQuery(query string) (node, error) {
+	tokens, err := lex(query)
+	if err != nil {
+		return nil, err
+	}
+	if len(tokens) == 0 {
+		return nil, nil
+	}
+	p := &parser{tokens: tokens}
+	n, err := p.or()
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.tokens) {
+		return nil, ErrInvalidQuery("unexpected )")
+	}
+	return n, nil
+}
+
+func (p *parser) peek() int {
+	if p.pos == len(p.tokens) {
+		return -1
+	}
+	return p.tokens[p.pos].kind
+}
+
+func (p *parser) or() (node, error) {
+	var children orNode
+	for {
+		n, err := p.and()
+		if err != nil {
+			return nil, err
+		}
+		if n == nil && (len(children) > 0 || p.peek() == tokOr) {
+			return nil, ErrInvalidQuery("OR needs terms on both sides")
+		}
+		if n != nil {
+			children = append(children, n)
+		}
+		if p.peek() != tokOr {
+			break
+		}
+		p.pos++
+	}
+	switch len(children) {
+	case 0:
+		return nil, nil
+	case 1:
+		return children[0], nil
+	}
+	return children, nil
+}
+
+func (p *parser) and() (node, error) {
+	var children andNode
+	for {
+		switch p.peek() {
+		case -1, tokRight, tokOr:
+			if len(children) == 0 {
+				return nil, nil
+			}
+			if len(children) == 1 {
+				return children[0], nil
+			}
+			return children, nil
+		case tokAnd:
+			p.pos++
+			continue
+		}
+		n, err := p.unary()
+		if err != nil {
+			return nil, err
+		}
+		if n != nil {
+			children = append(children, n)
+		}
+	}
+}
+
+func (p *parser) unary() (node, error) {
+	if p.peek() == tokNot {
+		p.pos++
+		if k := p.peek(); k == -1 || k == tokRight || k == tokOr {
+			return nil, ErrInvalidQuery("nothing to exclude after - or NOT")
+		}
+		n, err := p.unary()
+		if err != nil || n == nil {
+			return nil, err
+		}
+		return notNode{n}, nil
+	}
+	return p.primary()
+}
+
+func (p *parser) primary() (node, error) {
+	t := p.tokens[p.pos]
+	p.pos++
+	switch t.kind {
+	case tokLeft:
+		n, err := p.or()
+		if err != nil {
+			return nil, err
+		}
+		if p.peek() != tokRight {
+			return nil, ErrInvalidQuery("missing )")
+		}
+		p.pos++
+		return n, nil
+	case tokWord, tokPhrase:
+		terms := tokenize(t.text)
+		if len(terms) == 0 {
+			// punctuation only
+			return nil, nil
+		}
+		return termNode{t.field, terms}, nil
+	}
+	return nil, ErrInvalidQuery("unexpected " + map[int]string{tokRight: ")", tokOr: "OR", tokAnd: "AND"}[t.kind])
+}
@@ -0,0 +1,95 @@
+package index
+
+import (
+	"encoding/gob"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+)
+
+// formatVersion is bumped whenever the saved format changes, so that old files are rejected
+// rather than misread
+const formatVersion = 1
+
+// ErrVersion is raised when opening an index saved in another format
+type ErrVersion int
+
+func (e ErrVersion) Error() string {
+	return fmt.Sprintf("index saved in format version %d, want %d: build it again", int(e), formatVersion)
+}
+
+// snapshot is the saved form of an Index
+type snapshot struct {
+	Docs     map[string]*doc
+	Postings map[string]map[string][]int
+}
+
+// Save writes the index to w
+func (ix *Index) Save(w io.Writer) error {
+	ix.mu.RLock()
+	defer ix.mu.RUnlock()
+	enc := gob.NewEncoder(w)
+	if err := enc.Encode(formatVersion); err != nil {
+		return err
+	}
+	return enc.Encode(snapshot{ix.docs, ix.postings})
+}
+
+// Load reads an index written by Save
+func Load(r io.Reader) (*Index, error) {
+	dec := gob.NewDecoder(r)
+	var version int
+	if err := dec.Decode(&version); err != nil {
+		return nil, fmt.Errorf("error reading index: %v", err)
+	}
+	if version != formatVersion {
+		return nil, ErrVersion(version)
+	}
+	var s snapshot
+	if err := dec.Decode(&s); err != nil {
+		return nil, fmt.Errorf("error reading index: %v", err)
+	}
+	ix := New()
+	for id, d := range s.Docs {
+		ix.docs[id] = d
+		ix.total += d.Length
+	}
+	for t, p := range s.Postings {
+		ix.postings[t] = p
+	}
+	return ix, nil
+}
+
+// SaveFile writes the index to the file at path, replacing it atomically
+func (ix *Index) SaveFile(path string) error {
+	tmp, err := ioutil.TempFile(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	if err := ix.Save(tmp); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return nil
+}
+
+// OpenFile reads an index saved with SaveFile
+func OpenFile(path string) (*Index, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return Load(f)
+}
@@ -2,8 +2,10 @@ package unsplash
 
 import (
 	"context"
+	"io"
 
 	"github.com/eddogola/unsplash-go/unsplash/client"
+	"github.com/eddogola/unsplash-go/unsplash/utils"
 )
 
 // PhotosServiceClient defines client methods used to get or work
@@ -14,6 +16,9 @@ type PhotosServiceClient interface {
 	GetRandomPhoto(context.Context, client.QueryParams) (interface{}, error)
 	GetPhotoStats(context.Context, string, client.QueryParams) (*client.PhotoStats, error)
 	SearchPhotos(context.Context, client.QueryParams) (*client.PhotoSearchResult, error)
+	TrackDownload(context.Context, *client.Photo) (*client.DownloadResponse, error)
+	DownloadPhoto(context.Context, *client.Photo, string, io.Writer, client.ProgressFunc) (int64, error)
+	DownloadPhotoToFile(context.Context, *client.Photo, string, string, client.ProgressFunc) (int64, error)
 	UpdatePhoto(context.Context, string, map[string]string) (*client.Photo, error)
 	LikePhoto(context.Context, string) (*client.LikeResponse, error)
 	UnlikePhoto(context.Context, string) error
@@ -62,6 +67,45 @@ func (ps *PhotosService) Search(searchQuery string, queryParams client.QueryPara
 	return ps.client.SearchPhotos(ctx, queryParams)
 }
 
+// TrackDownload triggers a download event on the given Photo, as required by the API guidelines
+// whenever a photo is downloaded.
+func (ps *PhotosService) TrackDownload(pic *client.Photo) (*client.DownloadResponse, error) {
+	ctx := context.Background()
+	return ps.client.TrackDownload(ctx, pic)
+}
+
+// Download tracks a download of the given Photo, then streams the image to w.
+// If rOptions is not nil, the image is resized using the options, otherwise the full size image is downloaded.
+// progress, if not nil, is called as the image is written to w.
+// Use the client's DownloadPhoto to be able to cancel the download using a context.
+func (ps *PhotosService) Download(pic *client.Photo, w io.Writer, rOptions *utils.ResizeOptions, progress client.ProgressFunc) (int64, error) {
+	ctx := context.Background()
+	link, err := downloadLink(pic, rOptions)
+	if err != nil {
+		return 0, err
+	}
+	return ps.client.DownloadPhoto(ctx, pic, link, w, progress)
+}
+
+// DownloadToFile works like Download, writing the image to the file at path
+func (ps *PhotosService) DownloadToFile(pic *client.Photo, path string, rOptions *utils.ResizeOptions, progress client.ProgressFunc) (int64, error) {
+	ctx := context.Background()
+	link, err := downloadLink(pic, rOptions)
+	if err != nil {
+		return 0, err
+	}
+	return ps.client.DownloadPhotoToFile(ctx, pic, link, path, progress)
+}
+
+// downloadLink returns the link to download a photo from, given resize options.
+// An empty link is returned if no options are given, for the client to use the full size image.
+func downloadLink(pic *client.Photo, rOptions *utils.ResizeOptions) (string, error) {
+	if rOptions == nil {
+		return "", nil
+	}
+	return utils.BuildPhotoURL(pic, *rOptions)
+}
+
 // methods requiring private authentication
 
 // Update uses the data provided to update info on the requested Photo
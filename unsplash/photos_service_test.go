@@ -1,12 +1,20 @@
 package unsplash
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"reflect"
 	"testing"
 
 	"github.com/eddogola/unsplash-go/unsplash/client"
+	"github.com/eddogola/unsplash-go/unsplash/utils"
 )
 
 var pics = []client.Photo{
@@ -46,6 +54,16 @@ func (m *mockPhotoServiceClient) GetPhotoStats(ctx context.Context, photoID stri
 func (m *mockPhotoServiceClient) SearchPhotos(ctx context.Context, queryParams client.QueryParams) (*client.PhotoSearchResult, error) {
 	return &client.PhotoSearchResult{Results: pics}, nil
 }
+func (m *mockPhotoServiceClient) TrackDownload(ctx context.Context, pic *client.Photo) (*client.DownloadResponse, error) {
+	return &client.DownloadResponse{URL: pic.URLs.Full}, nil
+}
+func (m *mockPhotoServiceClient) DownloadPhoto(ctx context.Context, pic *client.Photo, link string, w io.Writer, progress client.ProgressFunc) (int64, error) {
+	n, err := io.WriteString(w, link)
+	return int64(n), err
+}
+func (m *mockPhotoServiceClient) DownloadPhotoToFile(ctx context.Context, pic *client.Photo, link, path string, progress client.ProgressFunc) (int64, error) {
+	return 0, nil
+}
 func (m *mockPhotoServiceClient) UpdatePhoto(ctx context.Context, photoID string, updatedData map[string]string) (*client.Photo, error) {
 	return &pic, nil
 }
@@ -128,6 +146,64 @@ func TestPhotosService(t *testing.T) {
 		}
 	})
 
+	t.Run("download photo with resize options", func(t *testing.T) {
+		var buf bytes.Buffer
+		p := client.Photo{ID: "someID"}
+		p.URLs.Raw = "https://images.unsplash.com/photo-1?ixid=abc"
+		_, err := mockUnsplash.Photos.Download(&p, &buf, &utils.ResizeOptions{Width: "200"}, nil)
+		checkErrorIsNil(t, err)
+
+		expected := "https://images.unsplash.com/photo-1?ixid=abc&w=200"
+		if buf.String() != expected {
+			t.Errorf("expected %v but got %v", expected, buf.String())
+		}
+	})
+
+	t.Run("download photo tracks download then streams image", func(t *testing.T) {
+		var tracked bool
+		image := bytes.Repeat([]byte("a"), 100*1024)
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.URL.Path {
+			case "/photos/someID/download":
+				tracked = true
+				fmt.Fprint(w, `{"url": "https://example.com"}`)
+			case "/image":
+				if !tracked {
+					t.Errorf("image requested before download was tracked")
+				}
+				w.Write(image)
+			default:
+				http.NotFound(w, r)
+			}
+		}))
+		defer server.Close()
+
+		p := client.Photo{ID: "someID"}
+		p.Links.DownloadLocation = server.URL + "/photos/someID/download"
+		p.URLs.Full = server.URL + "/image"
+		var buf bytes.Buffer
+		var lastWritten int64
+		unsplash := New(client.New("", server.Client(), client.NewConfig()))
+		n, err := unsplash.Photos.Download(&p, &buf, nil, func(written, total int64) {
+			lastWritten = written
+		})
+		checkErrorIsNil(t, err)
+		if n != int64(len(image)) || lastWritten != n {
+			t.Errorf("expected %v bytes written but got %v, last progress %v", len(image), n, lastWritten)
+		}
+		if !bytes.Equal(buf.Bytes(), image) {
+			t.Errorf("downloaded image does not match")
+		}
+	})
+
+	t.Run("download photo without download location", func(t *testing.T) {
+		p := client.Photo{ID: "someID"}
+		_, err := realUnsplash.Photos.Download(&p, ioutil.Discard, nil, nil)
+		if err != client.ErrDownloadLocationEmpty("someID") {
+			t.Errorf("expected error %v but got %v", client.ErrDownloadLocationEmpty("someID"), err)
+		}
+	})
+
 	t.Run("like photo with non-private client", func(t *testing.T) {
 		res, err := realUnsplash.Photos.Like("someID")
 		if res != nil {
@@ -157,6 +233,64 @@ func TestPhotosService(t *testing.T) {
 	})
 }
 
+func TestPhotoRawJSON(t *testing.T) {
+	data := []byte(`{
+		"id": "whackID",
+		"exif": {"name": "Canon, EOS 5D"},
+		"location": {"name": "Nairobi, Kenya"},
+		"topics": [{"id": "t1", "slug": "nature", "visibility": "featured"}],
+		"user": {"username": "cole", "social": {"twitter_username": "jcole"}},
+		"future_field": {"answer": 42}
+	}`)
+	var p client.Photo
+	err := json.Unmarshal(data, &p)
+	checkErrorIsNil(t, err)
+
+	if p.Exif.Name != "Canon, EOS 5D" || p.Location.Name != "Nairobi, Kenya" {
+		t.Errorf("expected exif and location names to be parsed, got %q and %q", p.Exif.Name, p.Location.Name)
+	}
+	if len(p.Topics) != 1 || p.Topics[0].Visibility != "featured" {
+		t.Errorf("expected photo topics to be parsed, got %v", p.Topics)
+	}
+	if p.User.Social.TwitterUsername != "jcole" {
+		t.Errorf("expected user social links to be parsed, got %v", p.User.Social)
+	}
+
+	var future struct {
+		Answer int `json:"answer"`
+	}
+	err = p.Field("future_field", &future)
+	checkErrorIsNil(t, err)
+	if future.Answer != 42 {
+		t.Errorf("expected 42 but got %v", future.Answer)
+	}
+
+	// nested resources' fields are reached through the outer resource's Raw
+	if p.User.Raw != nil {
+		t.Errorf("expected the nested user not to keep a copy of its JSON, got %s", p.User.Raw)
+	}
+	var user struct {
+		Username string `json:"username"`
+	}
+	err = p.Field("user", &user)
+	checkErrorIsNil(t, err)
+	if user.Username != "cole" {
+		t.Errorf("expected cole but got %v", user.Username)
+	}
+
+	var col client.Collection
+	err = json.Unmarshal([]byte(`{"id": "c1", "cover_photo": `+string(data)+`, "user": {"username": "jane"}}`), &col)
+	checkErrorIsNil(t, err)
+	if col.Raw == nil || col.CoverPhoto.Raw != nil || col.User.Raw != nil {
+		t.Errorf("expected only the collection to keep its JSON")
+	}
+
+	err = p.Field("absent", &future)
+	if err != client.ErrFieldNotFound("absent") {
+		t.Errorf("expected error %v but got %v", client.ErrFieldNotFound("absent"), err)
+	}
+}
+
 func checkErrorIsNil(t *testing.T, err error) {
 	t.Helper()
 	if err != nil {
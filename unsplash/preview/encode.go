@@ -0,0 +1,197 @@
+package preview
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"image"
+	"image/color/palette"
+	"image/draw"
+	"image/png"
+	"io"
+	"sort"
+)
+
+// WriteHalfBlocks draws img width cells wide using upper half block characters, the foreground
+// color filling the top half of each cell and the background color the bottom half
+func WriteHalfBlocks(w io.Writer, img image.Image, width int) error {
+	b := img.Bounds()
+	if b.Empty() || width < 1 {
+		return nil
+	}
+	height := (width*b.Dy()/b.Dx() + 1) &^ 1 // round up to fill whole cells
+	if height < 2 {
+		height = 2
+	}
+	small := resize(img, width, height)
+
+	bw := bufio.NewWriter(w)
+	for y := 0; y < height; y += 2 {
+		var prevTop, prevBottom [3]uint8
+		for x := 0; x < width; x++ {
+			i := small.PixOffset(x, y)
+			j := small.PixOffset(x, y+1)
+			top := [3]uint8{small.Pix[i], small.Pix[i+1], small.Pix[i+2]}
+			bottom := [3]uint8{small.Pix[j], small.Pix[j+1], small.Pix[j+2]}
+			// only write colors that change from the previous cell
+			if x == 0 || top != prevTop {
+				fmt.Fprintf(bw, "\x1b[38;2;%d;%d;%dm", top[0], top[1], top[2])
+			}
+			if x == 0 || bottom != prevBottom {
+				fmt.Fprintf(bw, "\x1b[48;2;%d;%d;%dm", bottom[0], bottom[1], bottom[2])
+			}
+			bw.WriteString("▀")
+			prevTop, prevBottom = top, bottom
+		}
+		bw.WriteString("\x1b[0m\n")
+	}
+	return bw.Flush()
+}
+
+// WriteSixel draws img width pixels wide as DEC sixel graphics, dithered to the 216 web-safe colors
+func WriteSixel(w io.Writer, img image.Image, width int) error {
+	b := img.Bounds()
+	if b.Empty() || width < 1 {
+		return nil
+	}
+	height := width * b.Dy() / b.Dx()
+	if height < 1 {
+		height = 1
+	}
+	pal := image.NewPaletted(image.Rect(0, 0, width, height), palette.WebSafe)
+	draw.FloydSteinberg.Draw(pal, pal.Bounds(), resize(img, width, height), image.Point{})
+
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, "\x1bPq\"1;1;%d;%d", width, height)
+	// define only the colors used, in percentages
+	used := make([]bool, len(pal.Palette))
+	for _, idx := range pal.Pix {
+		used[idx] = true
+	}
+	for i, c := range pal.Palette {
+		if !used[i] {
+			continue
+		}
+		r, g, b := rgb(c)
+		fmt.Fprintf(bw, "#%d;2;%d;%d;%d", i, int(r)*100/255, int(g)*100/255, int(b)*100/255)
+	}
+	// each band of six rows is written once per color it uses, with a bit set for every
+	// pixel in the column having the color
+	for y0 := 0; y0 < height; y0 += 6 {
+		bands := make(map[uint8][]byte)
+		for y := y0; y < y0+6 && y < height; y++ {
+			for x := 0; x < width; x++ {
+				idx := pal.ColorIndexAt(x, y)
+				if bands[idx] == nil {
+					bands[idx] = make([]byte, width)
+				}
+				bands[idx][x] |= 1 << uint(y-y0)
+			}
+		}
+		indexes := make([]int, 0, len(bands))
+		for idx := range bands {
+			indexes = append(indexes, int(idx))
+		}
+		sort.Ints(indexes)
+		for n, idx := range indexes {
+			if n > 0 {
+				bw.WriteByte('$') // back to the start of the band
+			}
+			fmt.Fprintf(bw, "#%d", idx)
+			writeSixelRun(bw, bands[uint8(idx)])
+		}
+		bw.WriteByte('-') // next band
+	}
+	bw.WriteString("\x1b\\\n")
+	return bw.Flush()
+}
+
+// writeSixelRun writes the sixels in masks, run-length encoding repeated sixels
+func writeSixelRun(w *bufio.Writer, masks []byte) {
+	for x := 0; x < len(masks); {
+		n := 1
+		for x+n < len(masks) && masks[x+n] == masks[x] {
+			n++
+		}
+		ch := masks[x] + 63
+		if n > 3 {
+			fmt.Fprintf(w, "!%d%c", n, ch)
+		} else {
+			for i := 0; i < n; i++ {
+				w.WriteByte(ch)
+			}
+		}
+		x += n
+	}
+}
+
+// kittyChunkSize is the maximum size of base64 encoded image data sent in one kitty escape sequence
+const kittyChunkSize = 4096
+
+// WriteKitty draws img width cells wide using the kitty graphics protocol.
+// The image is sent as PNG, scaled down if wider than needed, and scaled to width by the terminal.
+func WriteKitty(w io.Writer, img image.Image, width int) error {
+	b := img.Bounds()
+	if b.Empty() || width < 1 {
+		return nil
+	}
+	if max := width * cellWidth; b.Dx() > max {
+		height := max * b.Dy() / b.Dx()
+		if height < 1 {
+			height = 1
+		}
+		img = resize(img, max, height)
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return err
+	}
+	data := base64.StdEncoding.EncodeToString(buf.Bytes())
+
+	bw := bufio.NewWriter(w)
+	for i := 0; i < len(data); i += kittyChunkSize {
+		end := i + kittyChunkSize
+		more := 1
+		if end >= len(data) {
+			end, more = len(data), 0
+		}
+		if i == 0 {
+			fmt.Fprintf(bw, "\x1b_Ga=T,f=100,c=%d,m=%d;%s\x1b\\", width, more, data[i:end])
+		} else {
+			fmt.Fprintf(bw, "\x1b_Gm=%d;%s\x1b\\", more, data[i:end])
+		}
+	}
+	bw.WriteString("\n")
+	return bw.Flush()
+}
+
+// resize scales img to width by height pixels, averaging the source pixels covered by each pixel
+func resize(img image.Image, width, height int) *image.NRGBA {
+	b := img.Bounds()
+	dst := image.NewNRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		sy0 := b.Min.Y + y*b.Dy()/height
+		sy1 := b.Min.Y + (y+1)*b.Dy()/height
+		if sy1 <= sy0 {
+			sy1 = sy0 + 1
+		}
+		for x := 0; x < width; x++ {
+			sx0 := b.Min.X + x*b.Dx()/width
+			sx1 := b.Min.X + (x+1)*b.Dx()/width
+			if sx1 <= sx0 {
+				sx1 = sx0 + 1
+			}
+			var r, g, bl, n uint32
+			for sy := sy0; sy < sy1; sy++ {
+				for sx := sx0; sx < sx1; sx++ {
+					cr, cg, cb := rgb(img.At(sx, sy))
+					r, g, bl, n = r+uint32(cr), g+uint32(cg), bl+uint32(cb), n+1
+				}
+			}
+			i := dst.PixOffset(x, y)
+			dst.Pix[i], dst.Pix[i+1], dst.Pix[i+2], dst.Pix[i+3] = uint8(r/n), uint8(g/n), uint8(bl/n), 0xff
+		}
+	}
+	return dst
+}
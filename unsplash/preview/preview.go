@@ -0,0 +1,204 @@
+// Package preview draws photos in the terminal, for browsing results from the command line
+// and for debugging.
+//
+// Photos are drawn with ANSI true-color half-block characters, which work in most terminals,
+// or with the sixel or kitty graphics protocols where the terminal supports them.
+// When the image cannot be fetched, the photo's BlurHash, or failing that its dominant Color,
+// is drawn instead.
+package preview
+
+import (
+	"context"
+	"fmt"
+	"image"
+	"image/color"
+	_ "image/jpeg" // Unsplash serves photos as JPEG by default
+	_ "image/png"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/eddogola/unsplash-go/unsplash/blurhash"
+	"github.com/eddogola/unsplash-go/unsplash/client"
+	"github.com/eddogola/unsplash-go/unsplash/colors"
+)
+
+// Protocol defines how images are drawn in the terminal
+type Protocol string
+
+// Supported protocols
+const (
+	Auto       Protocol = ""           // detect the protocol using Detect
+	HalfBlocks Protocol = "halfblocks" // ANSI true-color upper half blocks, two pixels per cell
+	Sixel      Protocol = "sixel"      // DEC sixel graphics
+	Kitty      Protocol = "kitty"      // kitty terminal graphics protocol
+)
+
+// ErrUnknownProtocol is raised when an unsupported protocol is requested
+type ErrUnknownProtocol string
+
+func (e ErrUnknownProtocol) Error() string {
+	return fmt.Sprintf("unknown preview protocol %q: use halfblocks, sixel or kitty", string(e))
+}
+
+// ParseProtocol returns the named protocol, "auto" or an empty name for Auto
+func ParseProtocol(name string) (Protocol, error) {
+	switch p := Protocol(strings.ToLower(name)); p {
+	case "auto":
+		return Auto, nil
+	case Auto, HalfBlocks, Sixel, Kitty:
+		return p, nil
+	}
+	return Auto, ErrUnknownProtocol(name)
+}
+
+// Detect guesses the protocol supported by the terminal from environment variables read using getenv,
+// e.g. os.Getenv. Terminals are only known to support a graphics protocol by name, so HalfBlocks is
+// returned for any other terminal.
+func Detect(getenv func(string) string) Protocol {
+	term := strings.ToLower(getenv("TERM"))
+	program := strings.ToLower(getenv("TERM_PROGRAM"))
+	switch {
+	case getenv("KITTY_WINDOW_ID") != "", strings.Contains(term, "kitty"),
+		program == "wezterm", program == "ghostty", strings.Contains(term, "ghostty"):
+		return Kitty
+	case strings.Contains(term, "sixel"), strings.HasPrefix(term, "mlterm"), strings.HasPrefix(term, "foot"),
+		strings.HasPrefix(term, "contour"), program == "iterm.app":
+		return Sixel
+	}
+	return HalfBlocks
+}
+
+// cellWidth is the assumed width of a terminal cell in pixels, used to size images drawn
+// with graphics protocols
+const cellWidth = 10
+
+// Renderer draws photos in the terminal
+type Renderer struct {
+	// Client is used to fetch images, defaults to http.DefaultClient
+	Client *http.Client
+	// Protocol used to draw images, detected from the environment if Auto
+	Protocol Protocol
+	// Width is the width of drawn images in terminal cells, defaults to 40
+	Width int
+	// Offline draws the photo's BlurHash or Color without fetching the image
+	Offline bool
+}
+
+// New constructs a Renderer drawing images Width cells wide, using the protocol detected from the environment
+func New(width int) *Renderer {
+	return &Renderer{Protocol: Detect(os.Getenv), Width: width}
+}
+
+// Render draws pic to w. The image is fetched from URLs.Thumb, or URLs.Small when drawn wider than
+// a thumbnail, falling back to the photo's BlurHash and then Color if it cannot be fetched.
+// Placeholders are always drawn with half blocks.
+func (r *Renderer) Render(ctx context.Context, w io.Writer, pic *client.Photo) error {
+	protocol := r.Protocol
+	if protocol == Auto {
+		protocol = Detect(os.Getenv)
+	}
+	width := r.width()
+
+	if !r.Offline {
+		img, err := r.fetch(ctx, pic, protocol)
+		if err == nil {
+			return Draw(w, img, protocol, width)
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+	}
+	img, err := Placeholder(pic, width)
+	if err != nil {
+		return err
+	}
+	return Draw(w, img, HalfBlocks, width)
+}
+
+func (r *Renderer) width() int {
+	if r.Width < 1 {
+		return 40
+	}
+	return r.Width
+}
+
+// fetch downloads and decodes the smallest image wide enough to be drawn
+func (r *Renderer) fetch(ctx context.Context, pic *client.Photo, protocol Protocol) (image.Image, error) {
+	pixels := r.width()
+	if protocol != HalfBlocks {
+		pixels *= cellWidth
+	}
+	link := pic.URLs.Thumb
+	if pixels > 200 || link == "" { // thumbnails are 200 pixels wide
+		link = pic.URLs.Small
+	}
+	if link == "" {
+		return nil, fmt.Errorf("photo %s has no image URLs", pic.ID)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, link, nil)
+	if err != nil {
+		return nil, err
+	}
+	hc := r.Client
+	if hc == nil {
+		hc = http.DefaultClient
+	}
+	resp, err := hc.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code fetching %s: %d", link, resp.StatusCode)
+	}
+	img, _, err := image.Decode(resp.Body)
+	return img, err
+}
+
+// Placeholder returns an image standing in for pic, decoded from its BlurHash,
+// or filled with its Color if it has no valid BlurHash. width is the width of the image in pixels.
+func Placeholder(pic *client.Photo, width int) (image.Image, error) {
+	height := width
+	if pic.Width > 0 && pic.Height > 0 {
+		height = width * pic.Height / pic.Width
+	}
+	if height < 1 {
+		height = 1
+	}
+	if pic.BlurHash != "" {
+		if img, err := blurhash.Decode(pic.BlurHash, width, height, 1); err == nil {
+			return img, nil
+		}
+	}
+	c, err := colors.PhotoColor(pic)
+	if err != nil {
+		return nil, err
+	}
+	img := image.NewNRGBA(image.Rect(0, 0, width, height))
+	for i := 0; i < len(img.Pix); i += 4 {
+		img.Pix[i], img.Pix[i+1], img.Pix[i+2], img.Pix[i+3] = c.R, c.G, c.B, 0xff
+	}
+	return img, nil
+}
+
+// Draw writes img to w using protocol, width terminal cells wide
+func Draw(w io.Writer, img image.Image, protocol Protocol, width int) error {
+	switch protocol {
+	case HalfBlocks, Auto:
+		return WriteHalfBlocks(w, img, width)
+	case Sixel:
+		return WriteSixel(w, img, width*cellWidth)
+	case Kitty:
+		return WriteKitty(w, img, width)
+	}
+	return ErrUnknownProtocol(protocol)
+}
+
+// rgb returns the 8-bit color components of c, composited over black
+func rgb(c color.Color) (r, g, b uint8) {
+	cr, cg, cb, _ := c.RGBA()
+	return uint8(cr >> 8), uint8(cg >> 8), uint8(cb >> 8)
+}
@@ -0,0 +1,176 @@
+package preview
+
+import (
+	"bytes"
+	"context"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/eddogola/unsplash-go/unsplash/client"
+)
+
+func solid(w, h int, c color.RGBA) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			img.Set(x, y, c)
+		}
+	}
+	return img
+}
+
+func TestHalfBlocks(t *testing.T) {
+	// top half red, bottom half blue
+	img := solid(8, 4, color.RGBA{255, 0, 0, 255})
+	for y := 2; y < 4; y++ {
+		for x := 0; x < 8; x++ {
+			img.Set(x, y, color.RGBA{0, 0, 255, 255})
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := WriteHalfBlocks(&buf, img, 4); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	if len(lines) != 1 {
+		t.Fatalf("expected 4 by 2 pixels to be drawn on one line, got %d lines", len(lines))
+	}
+	if strings.Count(lines[0], "▀") != 4 {
+		t.Errorf("expected 4 cells, got %q", lines[0])
+	}
+	if !strings.HasPrefix(lines[0], "\x1b[38;2;255;0;0m\x1b[48;2;0;0;255m") || strings.Count(lines[0], "\x1b[38;2") != 1 {
+		t.Errorf("expected red over blue, set once for the whole line, got %q", lines[0])
+	}
+	if !strings.HasSuffix(lines[0], "\x1b[0m") {
+		t.Errorf("expected colors to be reset at the end of the line, got %q", lines[0])
+	}
+}
+
+func TestSixel(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteSixel(&buf, solid(20, 12, color.RGBA{255, 255, 255, 255}), 20); err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+	if !strings.HasPrefix(out, "\x1bPq\"1;1;20;12") || !strings.HasSuffix(out, "\x1b\\\n") {
+		t.Errorf("expected a sixel sequence with raster attributes, got %q", out)
+	}
+	// white is the last web-safe color, the only one defined, drawn as two full bands run-length encoded
+	if !strings.Contains(out, "#215;2;100;100;100#215") || strings.Count(out, "#215!20~-") != 2 {
+		t.Errorf("expected two run-length encoded bands of white, got %q", out[strings.LastIndex(out, ";"):])
+	}
+}
+
+func TestKitty(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteKitty(&buf, solid(400, 200, color.RGBA{10, 20, 30, 255}), 20); err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+	if !strings.HasPrefix(out, "\x1b_Ga=T,f=100,c=20,m=") || !strings.Contains(out, "\x1b\\") {
+		t.Errorf("expected a kitty graphics sequence, got %q", out)
+	}
+
+	// a panorama scaled down to under a pixel tall is kept a pixel tall
+	buf.Reset()
+	if err := WriteKitty(&buf, solid(4000, 2, color.RGBA{10, 20, 30, 255}), 5); err != nil || buf.Len() == 0 {
+		t.Errorf("expected a wide image to be drawn, got %v", err)
+	}
+}
+
+func TestDetect(t *testing.T) {
+	tt := []struct {
+		env      map[string]string
+		expected Protocol
+	}{
+		{map[string]string{"TERM": "xterm-256color"}, HalfBlocks},
+		{map[string]string{"TERM": "xterm-kitty"}, Kitty},
+		{map[string]string{"TERM": "xterm-256color", "TERM_PROGRAM": "WezTerm"}, Kitty},
+		{map[string]string{"TERM": "foot"}, Sixel},
+		{map[string]string{}, HalfBlocks},
+	}
+	for _, tc := range tt {
+		if got := Detect(func(k string) string { return tc.env[k] }); got != tc.expected {
+			t.Errorf("%v: expected %q, got %q", tc.env, tc.expected, got)
+		}
+	}
+	if _, err := ParseProtocol("ascii"); err == nil {
+		t.Error("expected unknown protocol to fail")
+	}
+}
+
+func TestRender(t *testing.T) {
+	var requested string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requested = r.URL.Path
+		if r.URL.Path == "/missing" {
+			http.NotFound(w, r)
+			return
+		}
+		jpeg.Encode(w, solid(200, 100, color.RGBA{0, 255, 0, 255}), nil)
+	}))
+	defer ts.Close()
+
+	pic := &client.Photo{ID: "abc", Width: 400, Height: 200, Color: "#ff0000"}
+	pic.URLs.Thumb = ts.URL + "/thumb"
+	pic.URLs.Small = ts.URL + "/small"
+	r := &Renderer{Protocol: HalfBlocks, Width: 10}
+
+	t.Run("fetches the thumbnail", func(t *testing.T) {
+		var buf bytes.Buffer
+		if err := r.Render(context.Background(), &buf, pic); err != nil {
+			t.Fatal(err)
+		}
+		if requested != "/thumb" {
+			t.Errorf("expected the thumbnail to be fetched, got %s", requested)
+		}
+		if !strings.Contains(buf.String(), ";255;") || strings.Contains(buf.String(), "255;0;0") {
+			t.Errorf("expected the fetched green image, got %q", buf.String())
+		}
+	})
+
+	t.Run("fetches the small image for graphics protocols", func(t *testing.T) {
+		kitty := &Renderer{Protocol: Kitty, Width: 40}
+		if err := kitty.Render(context.Background(), &bytes.Buffer{}, pic); err != nil {
+			t.Fatal(err)
+		}
+		if requested != "/small" {
+			t.Errorf("expected the small image to be fetched, got %s", requested)
+		}
+	})
+
+	t.Run("falls back to the color", func(t *testing.T) {
+		missing := *pic
+		missing.URLs.Thumb = ts.URL + "/missing"
+		var buf bytes.Buffer
+		if err := r.Render(context.Background(), &buf, &missing); err != nil {
+			t.Fatal(err)
+		}
+		if !strings.Contains(buf.String(), "\x1b[38;2;255;0;0m") {
+			t.Errorf("expected the photo's color, got %q", buf.String())
+		}
+	})
+
+	t.Run("offline draws the blurhash", func(t *testing.T) {
+		requested = ""
+		hashed := *pic
+		hashed.BlurHash = "LEHV6nWB2yk8pyo0adR*.7kCMdnj"
+		offline := &Renderer{Protocol: Kitty, Width: 10, Offline: true}
+		var buf bytes.Buffer
+		if err := offline.Render(context.Background(), &buf, &hashed); err != nil {
+			t.Fatal(err)
+		}
+		if requested != "" {
+			t.Errorf("expected no request, got %s", requested)
+		}
+		if lines := strings.Count(buf.String(), "\n"); lines != 3 || strings.Contains(buf.String(), "255;0;0") {
+			t.Errorf("expected 3 lines of half blocks drawn from the blurhash, got %q", buf.String())
+		}
+	})
+}
@@ -0,0 +1,102 @@
+package proxy
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+// cache holds responses in memory, evicting the least recently used when full
+type cache struct {
+	mu      sync.Mutex
+	order   *list.List // of *cacheEntry, most recently used first
+	entries map[string]*list.Element
+}
+
+type cacheEntry struct {
+	key string
+	res *response
+}
+
+func newCache() *cache {
+	return &cache{order: list.New(), entries: make(map[string]*list.Element)}
+}
+
+// get returns the unexpired response cached for key, or nil
+func (c *cache) get(key string, now time.Time) *response {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	el, ok := c.entries[key]
+	if !ok {
+		return nil
+	}
+	entry := el.Value.(*cacheEntry)
+	if now.After(entry.res.expires) {
+		c.order.Remove(el)
+		delete(c.entries, key)
+		return nil
+	}
+	c.order.MoveToFront(el)
+	return entry.res
+}
+
+// add caches res for key, evicting entries so no more than size are held
+func (c *cache) add(key string, res *response, size int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if el, ok := c.entries[key]; ok {
+		el.Value.(*cacheEntry).res = res
+		c.order.MoveToFront(el)
+	} else {
+		c.entries[key] = c.order.PushFront(&cacheEntry{key, res})
+	}
+	for c.order.Len() > size {
+		el := c.order.Back()
+		c.order.Remove(el)
+		delete(c.entries, el.Value.(*cacheEntry).key)
+	}
+}
+
+// len returns the number of responses cached, including expired ones not yet evicted
+func (c *cache) len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.order.Len()
+}
+
+// group coalesces identical requests: while a request for a key is in flight, requests
+// for the same key wait for it and share its response
+type group struct {
+	mu    sync.Mutex
+	calls map[string]*call
+}
+
+type call struct {
+	done chan struct{}
+	res  *response
+	err  error
+}
+
+// do calls fn, or waits for the call in flight for key, returning its result.
+// shared is true if the result came from another request's call.
+func (g *group) do(key string, fn func() (*response, error)) (res *response, err error, shared bool) {
+	g.mu.Lock()
+	if g.calls == nil {
+		g.calls = make(map[string]*call)
+	}
+	if c, ok := g.calls[key]; ok {
+		g.mu.Unlock()
+		<-c.done
+		return c.res, c.err, true
+	}
+	c := &call{done: make(chan struct{})}
+	g.calls[key] = c
+	g.mu.Unlock()
+
+	c.res, c.err = fn()
+	g.mu.Lock()
+	delete(g.calls, key)
+	g.mu.Unlock()
+	close(c.done)
+	return c.res, c.err, false
+}
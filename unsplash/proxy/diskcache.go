@@ -0,0 +1,129 @@
+package proxy
+
+import (
+	"container/list"
+	"crypto/sha256"
+	"encoding/hex"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+// diskCache holds files in a directory, evicting the least recently used when over its size.
+// Files are named after a hash of their key, so entries cached before a restart are found again.
+type diskCache struct {
+	dir      string
+	maxBytes int64
+
+	mu      sync.Mutex
+	order   *list.List // of *diskEntry, most recently used first
+	entries map[string]*list.Element
+	size    int64
+}
+
+type diskEntry struct {
+	name string
+	size int64
+}
+
+// openDiskCache opens the cache in dir, creating dir if needed, and evicting files if it holds
+// more than maxBytes. Files already in dir are ordered by their modification time.
+func openDiskCache(dir string, maxBytes int64) (*diskCache, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+	infos, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(infos, func(i, j int) bool { return infos[i].ModTime().After(infos[j].ModTime()) })
+	c := &diskCache{dir: dir, maxBytes: maxBytes, order: list.New(), entries: make(map[string]*list.Element)}
+	for _, info := range infos {
+		if info.IsDir() {
+			continue
+		}
+		if filepath.Ext(info.Name()) == ".tmp" {
+			// left by a write that was interrupted
+			os.Remove(filepath.Join(dir, info.Name()))
+			continue
+		}
+		c.entries[info.Name()] = c.order.PushBack(&diskEntry{info.Name(), info.Size()})
+		c.size += info.Size()
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.evict()
+	return c, nil
+}
+
+// fileName returns the name of the file holding key, with the extension ext
+func fileName(key, ext string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:]) + "." + ext
+}
+
+// get returns the path of the file cached for key, or an empty string
+func (c *diskCache) get(key, ext string) string {
+	name := fileName(key, ext)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	el, ok := c.entries[name]
+	if !ok {
+		return ""
+	}
+	c.order.MoveToFront(el)
+	path := filepath.Join(c.dir, name)
+	// the modification time orders the files when the cache is opened again
+	now := time.Now()
+	os.Chtimes(path, now, now)
+	return path
+}
+
+// put writes data to the file for key, evicting files to stay within the cache's size
+func (c *diskCache) put(key, ext string, data []byte) error {
+	name := fileName(key, ext)
+	tmp, err := ioutil.TempFile(c.dir, "*.tmp")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := os.Rename(tmp.Name(), filepath.Join(c.dir, name)); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	if el, ok := c.entries[name]; ok {
+		c.size -= el.Value.(*diskEntry).size
+		c.order.Remove(el)
+	}
+	c.entries[name] = c.order.PushFront(&diskEntry{name, int64(len(data))})
+	c.size += int64(len(data))
+	c.evict()
+	return nil
+}
+
+// evict removes the least recently used files until the cache is within its size.
+// The caller must hold c.mu.
+func (c *diskCache) evict() {
+	for c.size > c.maxBytes && c.order.Len() > 0 {
+		el := c.order.Back()
+		entry := el.Value.(*diskEntry)
+		c.order.Remove(el)
+		delete(c.entries, entry.name)
+		c.size -= entry.size
+		os.Remove(filepath.Join(c.dir, entry.name))
+	}
+}
@@ -0,0 +1,334 @@
+package proxy
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"math"
+	"net/http"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/eddogola/unsplash-go/unsplash/client"
+	"github.com/eddogola/unsplash-go/unsplash/utils"
+)
+
+// DefaultImageQuality is the imgix quality images are requested at
+const DefaultImageQuality = 75
+
+// maxImageSize bounds the size of an image fetched from Unsplash's image servers
+const maxImageSize = 64 << 20
+
+// maxPhotos bounds the number of photos whose details are kept in memory
+const maxPhotos = 10000
+
+// photoID matches the photo IDs served, keeping other paths out of API requests and cache keys
+var photoID = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+// missingPhotoTTL is how long a photo the API didn't find is reported missing without asking again
+const missingPhotoTTL = 10 * time.Minute
+
+// ImageHandler is an http.Handler serving resized photos at `/{photoID}?w=&h=&fm=&dpr=`.
+//
+// Requested sizes are rounded up to the nearest allowed size, and formats not allowed are replaced,
+// so the many variations apps ask for map onto a few images, each fetched once from Unsplash's
+// image servers and cached on disk. Without `fm`, the first allowed format the browser accepts is
+// served. Mount it under a prefix with http.StripPrefix.
+type ImageHandler struct {
+	client *client.Client
+	// Sizes are the allowed widths and heights, in pixels. If empty, utils.DefaultWidths are used.
+	Sizes []int
+	// Formats are the allowed imgix formats, in order of preference.
+	// The last one is served to browsers that accept none of the others.
+	// If empty, utils.DefaultFormats are used.
+	Formats []string
+	// Quality is the imgix quality, from 0 to 100, images are requested at
+	Quality int
+	// TrackDownloads tracks a download of the photo, as required by the API guidelines,
+	// whenever an image is served. Enable it if the handler serves downloads, rather than
+	// images displayed in an app.
+	TrackDownloads bool
+	// ErrorLog logs errors that don't stop an image from being served, e.g. failing to
+	// track a download. If nil, the log package's standard logger is used.
+	ErrorLog *log.Logger
+
+	cache *diskCache
+	group group
+
+	photosMu sync.Mutex
+	photos   map[string]*client.Photo
+	missing  map[string]time.Time // when photos the API didn't find can be requested again
+}
+
+// NewImageHandler constructs an ImageHandler requesting photos with c, and caching images in dir,
+// up to maxBytes. Images cached in dir by an earlier handler are served again.
+func NewImageHandler(c *client.Client, dir string, maxBytes int64) (*ImageHandler, error) {
+	cache, err := openDiskCache(dir, maxBytes)
+	if err != nil {
+		return nil, err
+	}
+	return &ImageHandler{
+		client:  c,
+		Sizes:   utils.DefaultWidths,
+		Formats: utils.DefaultFormats,
+		Quality: DefaultImageQuality,
+		cache:   cache,
+		photos:  make(map[string]*client.Photo),
+		missing: make(map[string]time.Time),
+	}, nil
+}
+
+func (h *ImageHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	id := strings.Trim(r.URL.Path, "/")
+	if !photoID.MatchString(id) {
+		http.NotFound(w, r)
+		return
+	}
+	opts, err := h.Options(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	key := id + "?" + opts.Params().Encode()
+	ext := opts.ImageFormat
+
+	if h.TrackDownloads {
+		pic, err := h.photo(r.Context(), id)
+		if err != nil {
+			writeImageError(w, err)
+			return
+		}
+		if _, err := h.client.TrackDownload(r.Context(), pic); err != nil {
+			h.logf("error tracking download of photo %s: %v", id, err)
+		}
+	}
+
+	vary := r.URL.Query().Get("fm") == ""
+	if path := h.cache.get(key, ext); path != "" {
+		if f, err := os.Open(path); err == nil {
+			defer f.Close()
+			serveImage(w, r, ext, vary, "HIT", f)
+			return
+		}
+		// evicted since, fetch it again
+	}
+
+	res, err, _ := h.group.do(key, func() (*response, error) {
+		data, err := h.fetch(id, opts)
+		if err != nil {
+			return nil, err
+		}
+		if err := h.cache.put(key, ext, data); err != nil {
+			h.logf("error caching image of photo %s: %v", id, err)
+		}
+		return &response{status: http.StatusOK, body: data}, nil
+	})
+	if err != nil {
+		writeImageError(w, err)
+		return
+	}
+	serveImage(w, r, ext, vary, "MISS", bytes.NewReader(res.body))
+}
+
+// serveImage serves an image in the imgix format, handling range and conditional requests.
+// Images are cached by browsers and CDNs for good, as a photo's images never change.
+// If vary is set, the format was picked using the Accept header.
+func serveImage(w http.ResponseWriter, r *http.Request, format string, vary bool, cache string, content io.ReadSeeker) {
+	if mime := utils.MIMEType(format); mime != "" {
+		w.Header().Set("Content-Type", mime)
+	}
+	w.Header().Set("Cache-Control", "public, max-age=31536000, immutable")
+	if vary {
+		w.Header().Set("Vary", "Accept")
+	}
+	w.Header().Set("X-Cache", cache)
+	http.ServeContent(w, r, "", time.Time{}, content)
+}
+
+// Options maps the imgix parameters in r's query onto the allowed sizes and formats.
+// `w` and `h` are multiplied by `dpr`, then rounded up to the nearest allowed size, or down to
+// the largest. Without either, the largest size is used. When both are set the image is
+// cropped to fill them, otherwise it's scaled to fit.
+func (h *ImageHandler) Options(r *http.Request) (utils.ResizeOptions, error) {
+	q := r.URL.Query()
+	dpr := 1.0
+	if v := q.Get("dpr"); v != "" {
+		var err error
+		if dpr, err = strconv.ParseFloat(v, 64); err != nil || dpr < 1 || dpr > 5 {
+			return utils.ResizeOptions{}, utils.ErrInvalidOption{Param: "dpr", Value: v, Reason: "must be a number from 1 to 5"}
+		}
+	}
+	sizes := h.Sizes
+	if len(sizes) == 0 {
+		sizes = utils.DefaultWidths
+	}
+	sizes = append([]int(nil), sizes...)
+	sort.Ints(sizes)
+	dimension := func(param string) (string, error) {
+		v := q.Get(param)
+		if v == "" {
+			return "", nil
+		}
+		n, err := strconv.ParseFloat(v, 64)
+		if err != nil || n <= 0 || math.IsInf(n, 0) {
+			return "", utils.ErrInvalidOption{Param: param, Value: v, Reason: "must be a number greater than 0"}
+		}
+		return strconv.Itoa(snap(sizes, n*dpr)), nil
+	}
+
+	opts := utils.ResizeOptions{Quality: strconv.Itoa(h.Quality), Fit: "max"}
+	var err error
+	if opts.Width, err = dimension("w"); err != nil {
+		return opts, err
+	}
+	if opts.Height, err = dimension("h"); err != nil {
+		return opts, err
+	}
+	switch {
+	case opts.Width == "" && opts.Height == "":
+		opts.Width = strconv.Itoa(sizes[len(sizes)-1])
+	case opts.Width != "" && opts.Height != "":
+		opts.Fit = "crop"
+	}
+	opts.ImageFormat = h.format(q.Get("fm"), r.Header.Get("Accept"))
+	return opts, nil
+}
+
+// snap returns the smallest size at least n, or the largest size
+func snap(sizes []int, n float64) int {
+	for _, size := range sizes {
+		if float64(size) >= n {
+			return size
+		}
+	}
+	return sizes[len(sizes)-1]
+}
+
+// format returns the requested format if it's allowed, or, if no format is requested, the first
+// allowed format the browser accepts. Otherwise it falls back to the last allowed format.
+func (h *ImageHandler) format(requested, accept string) string {
+	formats := h.Formats
+	if len(formats) == 0 {
+		formats = utils.DefaultFormats
+	}
+	fallback := formats[len(formats)-1]
+	if requested != "" {
+		for _, f := range formats {
+			if strings.EqualFold(f, requested) {
+				return f
+			}
+		}
+		return fallback
+	}
+	for _, f := range formats {
+		if mime := utils.MIMEType(f); mime != "" && strings.Contains(accept, mime) {
+			return f
+		}
+	}
+	return fallback
+}
+
+// photo returns the photo's details, requesting them from the API the first time.
+// Photos the API didn't find are reported missing for missingPhotoTTL without asking again.
+func (h *ImageHandler) photo(ctx context.Context, id string) (*client.Photo, error) {
+	h.photosMu.Lock()
+	pic, ok := h.photos[id]
+	retry, missing := h.missing[id]
+	h.photosMu.Unlock()
+	if ok {
+		return pic, nil
+	}
+	if missing && time.Now().Before(retry) {
+		return nil, errPhotoNotFound
+	}
+	pic, err := h.client.GetPhoto(ctx, id)
+	if e, ok := err.(client.ErrStatusCode); ok && e.StatusCode() == http.StatusNotFound {
+		h.photosMu.Lock()
+		defer h.photosMu.Unlock()
+		if len(h.missing) >= maxPhotos {
+			h.missing = make(map[string]time.Time)
+		}
+		h.missing[id] = time.Now().Add(missingPhotoTTL)
+		return nil, errPhotoNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	h.photosMu.Lock()
+	defer h.photosMu.Unlock()
+	if len(h.photos) >= maxPhotos {
+		h.photos = make(map[string]*client.Photo)
+	}
+	h.photos[id] = pic
+	delete(h.missing, id)
+	return pic, nil
+}
+
+// fetch requests the photo's image from Unsplash's image servers.
+// It doesn't use the request's context, as other requests may be waiting for the image.
+func (h *ImageHandler) fetch(id string, opts utils.ResizeOptions) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), DefaultTimeout)
+	defer cancel()
+	pic, err := h.photo(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	link, err := utils.BuildPhotoURL(pic, opts)
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, link, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := h.client.HTTPClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("image server responded with %s", resp.Status)
+	}
+	data, err := ioutil.ReadAll(io.LimitReader(resp.Body, maxImageSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > maxImageSize {
+		return nil, fmt.Errorf("image of photo %s is larger than %d bytes", id, maxImageSize)
+	}
+	return data, nil
+}
+
+func (h *ImageHandler) logf(format string, args ...interface{}) {
+	if h.ErrorLog != nil {
+		h.ErrorLog.Printf(format, args...)
+		return
+	}
+	log.Printf(format, args...)
+}
+
+// errPhotoNotFound is returned for photos the API didn't find
+var errPhotoNotFound = errors.New("photo not found")
+
+// writeImageError responds with 404 for photos that don't exist, and 502 for other errors
+func writeImageError(w http.ResponseWriter, err error) {
+	if err == errPhotoNotFound {
+		http.Error(w, "photo not found", http.StatusNotFound)
+		return
+	}
+	http.Error(w, "error fetching image: "+err.Error(), http.StatusBadGateway)
+}
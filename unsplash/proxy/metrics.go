@@ -0,0 +1,88 @@
+package proxy
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"sync"
+	"sync/atomic"
+)
+
+// metrics counts the proxy's activity, exposed at MetricsPath in the Prometheus text format
+type metrics struct {
+	hits, misses, coalesced      int64
+	upstreamTotal, upstreamFails int64
+	unauthorizedTotal            int64
+
+	mu        sync.Mutex
+	requests  map[string]int64 // by consumer name
+	rejection map[string]int64 // quota rejections, by consumer name
+}
+
+func (m *metrics) hit()           { atomic.AddInt64(&m.hits, 1) }
+func (m *metrics) miss()          { atomic.AddInt64(&m.misses, 1) }
+func (m *metrics) coalesce()      { atomic.AddInt64(&m.coalesced, 1) }
+func (m *metrics) upstream()      { atomic.AddInt64(&m.upstreamTotal, 1) }
+func (m *metrics) upstreamError() { atomic.AddInt64(&m.upstreamFails, 1) }
+func (m *metrics) unauthorized()  { atomic.AddInt64(&m.unauthorizedTotal, 1) }
+
+func (m *metrics) request(consumer string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.requests == nil {
+		m.requests = make(map[string]int64)
+	}
+	m.requests[consumer]++
+}
+
+func (m *metrics) rejected(consumer string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.rejection == nil {
+		m.rejection = make(map[string]int64)
+	}
+	m.rejection[consumer]++
+}
+
+func (s *Server) writeMetrics(w http.ResponseWriter) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+	m := &s.metrics
+	counter(w, "unsplash_proxy_cache_hits_total", "Requests served from the cache.", atomic.LoadInt64(&m.hits))
+	counter(w, "unsplash_proxy_cache_misses_total", "Cacheable requests not found in the cache.", atomic.LoadInt64(&m.misses))
+	counter(w, "unsplash_proxy_coalesced_total", "Requests that shared the response of an identical request in flight.", atomic.LoadInt64(&m.coalesced))
+	counter(w, "unsplash_proxy_upstream_requests_total", "Requests made to the Unsplash API.", atomic.LoadInt64(&m.upstreamTotal))
+	counter(w, "unsplash_proxy_upstream_errors_total", "Requests to the Unsplash API that failed, or responded with a server error.", atomic.LoadInt64(&m.upstreamFails))
+	counter(w, "unsplash_proxy_unauthorized_total", "Requests rejected for a missing or invalid API key.", atomic.LoadInt64(&m.unauthorizedTotal))
+	gauge(w, "unsplash_proxy_cache_entries", "Responses held in the cache.", int64(s.cache.len()))
+
+	m.mu.Lock()
+	byConsumer(w, "unsplash_proxy_requests_total", "Requests accepted, by consumer.", m.requests)
+	byConsumer(w, "unsplash_proxy_quota_rejections_total", "Requests rejected for exceeding the hourly quota, by consumer.", m.rejection)
+	m.mu.Unlock()
+
+	if rl, ok := s.client.RateLimit(); ok {
+		gauge(w, "unsplash_proxy_ratelimit_limit", "Requests per hour allowed by the Unsplash API.", int64(rl.Limit))
+		gauge(w, "unsplash_proxy_ratelimit_remaining", "Requests left in the current hour, as last reported by the Unsplash API.", int64(rl.Remaining))
+	}
+}
+
+func counter(w io.Writer, name, help string, v int64) {
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s counter\n%s %d\n", name, help, name, name, v)
+}
+
+func gauge(w io.Writer, name, help string, v int64) {
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s gauge\n%s %d\n", name, help, name, name, v)
+}
+
+func byConsumer(w io.Writer, name, help string, values map[string]int64) {
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s counter\n", name, help, name)
+	consumers := make([]string, 0, len(values))
+	for c := range values {
+		consumers = append(consumers, c)
+	}
+	sort.Strings(consumers)
+	for _, c := range consumers {
+		fmt.Fprintf(w, "%s{consumer=%q} %d\n", name, c, values[c])
+	}
+}
@@ -0,0 +1,247 @@
+// Package proxy implements a caching reverse proxy for the Unsplash API, so that apps on a
+// private network can share one Client-ID without embedding it.
+//
+// The proxy serves the API's v1 GET paths, e.g. `/photos/random` or `/search/photos?query=fog`,
+// forwarding requests with the credentials of a client.Client. Successful responses are cached,
+// and identical requests made while one is in flight share its response. Consumers identify
+// themselves with API keys issued by the proxy, each with its own hourly quota.
+//
+// Consumers can keep using this package's client: configure it with their API key as its
+// Client-ID, and an http.Client whose transport sends requests to the proxy.
+//
+// ImageHandler serves photos' images, normalizing the requested size and format so that
+// variations of an image are fetched once, and caching them on disk. Serve it with
+// Server.Protect so that its requests need an API key and count against quotas.
+package proxy
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/eddogola/unsplash-go/unsplash/client"
+)
+
+// Paths served by the proxy itself, rather than forwarded to the API
+const (
+	MetricsPath = "/_proxy/metrics"
+	HealthPath  = "/_proxy/health"
+)
+
+// Defaults used by New
+const (
+	DefaultCacheTTL  = 10 * time.Minute
+	DefaultCacheSize = 1000
+	DefaultTimeout   = 30 * time.Second
+)
+
+// Server is an http.Handler forwarding API requests
+type Server struct {
+	client *client.Client
+	// CacheTTL is how long successful responses are cached; 0 disables caching
+	CacheTTL time.Duration
+	// CacheSize is the most responses cached, the least recently used are evicted first
+	CacheSize int
+	// Timeout bounds each request to the API
+	Timeout time.Duration
+
+	consumers map[string]Consumer // by key
+	quotas    *quotas
+	cache     *cache
+	group     group
+	metrics   metrics
+}
+
+// New constructs a Server forwarding requests with c's credentials.
+// If consumers is empty, requests don't need an API key, and have no quota.
+func New(c *client.Client, consumers []Consumer) *Server {
+	s := &Server{
+		client:    c,
+		CacheTTL:  DefaultCacheTTL,
+		CacheSize: DefaultCacheSize,
+		Timeout:   DefaultTimeout,
+		consumers: make(map[string]Consumer),
+		quotas:    newQuotas(),
+		cache:     newCache(),
+	}
+	for _, consumer := range consumers {
+		s.consumers[consumer.Key] = consumer
+	}
+	return s
+}
+
+// response defines an API response, as cached and shared between identical requests
+type response struct {
+	status  int
+	header  http.Header
+	body    []byte
+	expires time.Time
+}
+
+// forwardedHeaders are the API response headers passed on to consumers.
+// Rate limit headers are replaced by the consumer's quota.
+var forwardedHeaders = []string{"Content-Type", "Link", "X-Total", "X-Per-Page"}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.URL.Path {
+	case MetricsPath:
+		s.writeMetrics(w)
+		return
+	case HealthPath:
+		w.Write([]byte("ok\n"))
+		return
+	}
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		writeError(w, http.StatusMethodNotAllowed, "only GET requests are forwarded by the proxy")
+		return
+	}
+
+	if !s.admit(w, r) {
+		return
+	}
+
+	p, query := path.Clean(r.URL.Path), r.URL.Query()
+	// credentials are injected by the proxy, never forwarded
+	query.Del("client_id")
+	key := p
+	if len(query) > 0 {
+		key += "?" + query.Encode()
+	}
+
+	var res *response
+	var err error
+	if ttl := s.CacheTTL; ttl > 0 && cacheable(p) {
+		if res = s.cache.get(key, time.Now()); res != nil {
+			s.metrics.hit()
+			w.Header().Set("X-Cache", "HIT")
+		} else {
+			s.metrics.miss()
+			w.Header().Set("X-Cache", "MISS")
+			var shared bool
+			res, err, shared = s.group.do(key, func() (*response, error) {
+				res, err := s.fetch(key)
+				if err == nil && res.status == http.StatusOK {
+					res.expires = time.Now().Add(ttl)
+					s.cache.add(key, res, s.CacheSize)
+				}
+				return res, err
+			})
+			if shared {
+				s.metrics.coalesce()
+			}
+		}
+	} else {
+		res, err = s.fetch(key)
+	}
+	if err != nil {
+		writeError(w, http.StatusBadGateway, "error requesting the Unsplash API: "+err.Error())
+		return
+	}
+
+	for _, h := range forwardedHeaders {
+		if v := res.header.Get(h); v != "" {
+			w.Header().Set(h, v)
+		}
+	}
+	if link := res.header.Get("Link"); link != "" {
+		// point pagination links at the proxy
+		w.Header().Set("Link", strings.Replace(link, client.BaseEndpoint, baseURL(r), -1))
+	}
+	w.WriteHeader(res.status)
+	if r.Method != http.MethodHead {
+		w.Write(res.body)
+	}
+}
+
+// Protect returns a handler passing requests on to h once their consumer is authenticated and
+// the request is counted against its quota, like the requests the Server forwards.
+// Use it for handlers spending the Server's API calls, e.g. an ImageHandler.
+func (s *Server) Protect(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if s.admit(w, r) {
+			h.ServeHTTP(w, r)
+		}
+	})
+}
+
+// admit authenticates r's consumer and counts r against its quota. If r is refused, it responds
+// with an error and returns false.
+func (s *Server) admit(w http.ResponseWriter, r *http.Request) bool {
+	consumer, ok := s.authenticate(r)
+	if !ok {
+		s.metrics.unauthorized()
+		writeError(w, http.StatusUnauthorized, "missing or invalid API key")
+		return false
+	}
+	limit, remaining, ok := s.quotas.take(consumer, time.Now())
+	if consumer.Limit > 0 {
+		w.Header().Set("X-Ratelimit-Limit", strconv.Itoa(limit))
+		w.Header().Set("X-Ratelimit-Remaining", strconv.Itoa(remaining))
+	}
+	if !ok {
+		s.metrics.rejected(consumer.Name)
+		writeError(w, http.StatusTooManyRequests, "hourly quota exceeded")
+		return false
+	}
+	s.metrics.request(consumer.Name)
+	return true
+}
+
+// fetch requests the API path and query in key.
+// It doesn't use the consumer's context, as other consumers may be waiting for the response.
+func (s *Server) fetch(key string) (*response, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), s.Timeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.TrimSuffix(client.BaseEndpoint, "/")+key, nil)
+	if err != nil {
+		return nil, err
+	}
+	s.metrics.upstream()
+	resp, err := s.client.Do(req)
+	if err != nil {
+		s.metrics.upstreamError()
+		return nil, err
+	}
+	defer resp.Body.Close()
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		s.metrics.upstreamError()
+		return nil, err
+	}
+	if resp.StatusCode >= 500 {
+		s.metrics.upstreamError()
+	}
+	return &response{status: resp.StatusCode, header: resp.Header, body: body}, nil
+}
+
+// cacheable reports whether responses for the API path can be cached.
+// Random photos must differ on every request, and download tracking must reach the API every time.
+func cacheable(p string) bool {
+	return !strings.HasPrefix(p, "/photos/random") && !strings.HasSuffix(p, "/download")
+}
+
+// baseURL returns the proxy's address, as the consumer sees it, ending in a slash
+func baseURL(r *http.Request) string {
+	scheme := "http"
+	if r.TLS != nil {
+		scheme = "https"
+	}
+	if proto := r.Header.Get("X-Forwarded-Proto"); proto != "" {
+		scheme = proto
+	}
+	return (&url.URL{Scheme: scheme, Host: r.Host, Path: "/"}).String()
+}
+
+// writeError writes an error in the API's format
+func writeError(w http.ResponseWriter, status int, msg string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string][]string{"errors": {msg}})
+}
@@ -0,0 +1,386 @@
+package proxy
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/eddogola/unsplash-go/unsplash/client"
+)
+
+// redirectTransport sends every request to the test server
+type redirectTransport struct {
+	target *url.URL
+}
+
+func (t redirectTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	req.URL.Scheme = t.target.Scheme
+	req.URL.Host = t.target.Host
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+// setup starts a proxy in front of handler, standing in for the API
+func setup(t *testing.T, handler http.HandlerFunc, consumers ...Consumer) (*Server, *httptest.Server) {
+	upstream := httptest.NewServer(handler)
+	t.Cleanup(upstream.Close)
+	target, _ := url.Parse(upstream.URL)
+	c := client.New("secret", &http.Client{Transport: redirectTransport{target}}, client.NewConfig())
+	s := New(c, consumers)
+	ts := httptest.NewServer(s)
+	t.Cleanup(ts.Close)
+	return s, ts
+}
+
+func get(t *testing.T, link string, header ...string) (*http.Response, string) {
+	req, _ := http.NewRequest(http.MethodGet, link, nil)
+	for i := 0; i+1 < len(header); i += 2 {
+		req.Header.Set(header[i], header[i+1])
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	body, _ := ioutil.ReadAll(resp.Body)
+	return resp, string(body)
+}
+
+func TestCaching(t *testing.T) {
+	var requests int64
+	var gotAuth, gotQuery string
+	_, ts := setup(t, func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt64(&requests, 1)
+		gotAuth, gotQuery = r.Header.Get("Authorization"), r.URL.RawQuery
+		if r.URL.Path == "/photos/missing" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("X-Ratelimit-Limit", "50")
+		w.Header().Set("X-Ratelimit-Remaining", "49")
+		w.Header().Set("Link", `<https://api.unsplash.com/photos?page=2>; rel="next"`)
+		w.Write([]byte(`{"id": "abc"}`))
+	})
+
+	resp, body := get(t, ts.URL+"/photos/abc?client_id=leaked")
+	if body != `{"id": "abc"}` || resp.Header.Get("X-Cache") != "MISS" {
+		t.Errorf("expected the API response, got %q, X-Cache %q", body, resp.Header.Get("X-Cache"))
+	}
+	if gotAuth != "Client-ID secret" || gotQuery != "" {
+		t.Errorf("expected the proxy's credentials only, got authorization %q and query %q", gotAuth, gotQuery)
+	}
+	if link := resp.Header.Get("Link"); link != `<`+ts.URL+`/photos?page=2>; rel="next"` {
+		t.Errorf("expected pagination links to point at the proxy, got %q", link)
+	}
+	if resp.Header.Get("X-Ratelimit-Limit") != "" {
+		t.Errorf("expected the API's rate limit headers not to be forwarded")
+	}
+
+	resp, body = get(t, ts.URL+"/photos/abc")
+	if body != `{"id": "abc"}` || resp.Header.Get("X-Cache") != "HIT" || requests != 1 {
+		t.Errorf("expected a cached response, got %q, X-Cache %q after %d requests", body, resp.Header.Get("X-Cache"), requests)
+	}
+
+	for _, uncached := range []string{"/photos/random", "/photos/abc/download", "/photos/missing"} {
+		before := requests
+		get(t, ts.URL+uncached)
+		get(t, ts.URL+uncached)
+		if requests-before != 2 {
+			t.Errorf("expected %s not to be cached, made %d requests", uncached, requests-before)
+		}
+	}
+
+	resp, err := http.Post(ts.URL+"/collections", "application/json", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusMethodNotAllowed {
+		t.Errorf("expected POST requests to be rejected, got %d", resp.StatusCode)
+	}
+}
+
+func TestCoalescing(t *testing.T) {
+	var requests int64
+	release := make(chan struct{})
+	s, ts := setup(t, func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt64(&requests, 1)
+		<-release
+		w.Write([]byte(`[]`))
+	})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, body := get(t, ts.URL+"/topics"); body != "[]" {
+				t.Errorf("expected the shared response, got %q", body)
+			}
+		}()
+	}
+	// wait for every request to miss the cache, and for them to reach the in-flight request
+	for atomic.LoadInt64(&s.metrics.misses) < 5 {
+		time.Sleep(time.Millisecond)
+	}
+	time.Sleep(20 * time.Millisecond)
+	close(release)
+	wg.Wait()
+	if requests != 1 || s.metrics.coalesced != 4 {
+		t.Errorf("expected identical requests to share one API request, made %d, %d coalesced", requests, s.metrics.coalesced)
+	}
+}
+
+func TestQuotas(t *testing.T) {
+	_, ts := setup(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{}`))
+	}, Consumer{Name: "app", Key: "key1", Limit: 2}, Consumer{Name: "batch", Key: "key2"})
+
+	if resp, _ := get(t, ts.URL+"/photos"); resp.StatusCode != http.StatusUnauthorized {
+		t.Errorf("expected requests without a key to be rejected, got %d", resp.StatusCode)
+	}
+	if resp, _ := get(t, ts.URL+"/photos", "Authorization", "Client-ID wrong"); resp.StatusCode != http.StatusUnauthorized {
+		t.Errorf("expected requests with an unknown key to be rejected, got %d", resp.StatusCode)
+	}
+
+	resp, _ := get(t, ts.URL+"/photos", "Authorization", "Client-ID key1")
+	if resp.StatusCode != http.StatusOK || resp.Header.Get("X-Ratelimit-Remaining") != "1" {
+		t.Errorf("expected the consumer's quota in the response, got %d, remaining %q", resp.StatusCode, resp.Header.Get("X-Ratelimit-Remaining"))
+	}
+	get(t, ts.URL+"/photos?client_id=key1")
+	if resp, _ = get(t, ts.URL+"/photos", "X-Api-Key", "key1"); resp.StatusCode != http.StatusTooManyRequests {
+		t.Errorf("expected the third request to exceed the quota, got %d", resp.StatusCode)
+	}
+	for i := 0; i < 3; i++ {
+		if resp, _ = get(t, ts.URL+"/photos", "X-Api-Key", "key2"); resp.StatusCode != http.StatusOK {
+			t.Errorf("expected unlimited requests, got %d", resp.StatusCode)
+		}
+	}
+
+	_, metrics := get(t, ts.URL+MetricsPath)
+	for _, want := range []string{
+		`unsplash_proxy_requests_total{consumer="app"} 2`,
+		`unsplash_proxy_requests_total{consumer="batch"} 3`,
+		`unsplash_proxy_quota_rejections_total{consumer="app"} 1`,
+		`unsplash_proxy_unauthorized_total 2`,
+		`unsplash_proxy_cache_hits_total 4`,
+		`unsplash_proxy_upstream_requests_total 1`,
+	} {
+		if !strings.Contains(metrics, want) {
+			t.Errorf("expected metrics to contain %q, got\n%s", want, metrics)
+		}
+	}
+}
+
+func TestProtect(t *testing.T) {
+	s, _ := setup(t, func(w http.ResponseWriter, r *http.Request) {
+		t.Error("unexpected request to the API")
+	}, Consumer{Name: "app", Key: "key1", Limit: 1})
+	served := 0
+	ts := httptest.NewServer(s.Protect(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		served++
+	})))
+	t.Cleanup(ts.Close)
+
+	if resp, _ := get(t, ts.URL+"/images/abc"); resp.StatusCode != http.StatusUnauthorized {
+		t.Errorf("expected requests without a key to be rejected, got %d", resp.StatusCode)
+	}
+	if resp, _ := get(t, ts.URL+"/images/abc?client_id=key1"); resp.StatusCode != http.StatusOK || resp.Header.Get("X-Ratelimit-Remaining") != "0" {
+		t.Errorf("expected the request to be counted against the quota, got %d, remaining %q", resp.StatusCode, resp.Header.Get("X-Ratelimit-Remaining"))
+	}
+	if resp, _ := get(t, ts.URL+"/images/abc", "X-Api-Key", "key1"); resp.StatusCode != http.StatusTooManyRequests {
+		t.Errorf("expected the second request to exceed the quota, got %d", resp.StatusCode)
+	}
+	if served != 1 {
+		t.Errorf("expected only the admitted request to be served, got %d", served)
+	}
+}
+
+func TestQuotaWindow(t *testing.T) {
+	q := newQuotas()
+	c := Consumer{Name: "app", Limit: 1}
+	now := time.Now()
+	if _, _, ok := q.take(c, now); !ok {
+		t.Fatal("expected the first request to be allowed")
+	}
+	if _, _, ok := q.take(c, now.Add(time.Minute)); ok {
+		t.Error("expected the second request in the hour to be rejected")
+	}
+	if _, remaining, ok := q.take(c, now.Add(time.Hour)); !ok || remaining != 0 {
+		t.Errorf("expected the quota to reset after an hour, got %v, %d remaining", ok, remaining)
+	}
+}
+
+func TestCacheEviction(t *testing.T) {
+	c := newCache()
+	now := time.Now()
+	for _, key := range []string{"a", "b", "c"} {
+		c.add(key, &response{expires: now.Add(time.Minute)}, 2)
+	}
+	if c.get("a", now) != nil || c.get("c", now) == nil || c.len() != 2 {
+		t.Errorf("expected the least recently used response to be evicted")
+	}
+	if c.get("b", now.Add(2*time.Minute)) != nil || c.len() != 1 {
+		t.Errorf("expected expired responses to be dropped")
+	}
+}
+
+// setupImages starts an ImageHandler caching in dir, with a fake API serving photo abc
+// and an image server describing the image requested
+func setupImages(t *testing.T, dir string) (*ImageHandler, *httptest.Server, map[string]int) {
+	requests := make(map[string]int)
+	var mu sync.Mutex
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		requests[r.URL.Path]++
+		mu.Unlock()
+		switch r.URL.Path {
+		case "/photos/abc":
+			w.Write([]byte(`{"id": "abc", "urls": {"raw": "https://images.unsplash.com/photo-abc?ixid=track"},
+				"links": {"download_location": "https://api.unsplash.com/photos/abc/download"}}`))
+		case "/photos/abc/download":
+			w.Write([]byte(`{"url": "https://images.unsplash.com/photo-abc"}`))
+		case "/photo-abc":
+			q := r.URL.Query()
+			fmt.Fprintf(w, "w=%s h=%s fm=%s fit=%s ixid=%s", q.Get("w"), q.Get("h"), q.Get("fm"), q.Get("fit"), q.Get("ixid"))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(upstream.Close)
+	target, _ := url.Parse(upstream.URL)
+	c := client.New("secret", &http.Client{Transport: redirectTransport{target}}, client.NewConfig())
+	h, err := NewImageHandler(c, dir, 1<<20)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ts := httptest.NewServer(h)
+	t.Cleanup(ts.Close)
+	return h, ts, requests
+}
+
+func TestImageOptions(t *testing.T) {
+	h, _, _ := setupImages(t, t.TempDir())
+	for _, tc := range []struct {
+		query, accept string
+		want          string
+	}{
+		{"w=500", "", "w=640 fm=jpg fit=max"},
+		{"w=600&fm=webp", "", "w=640 fm=webp fit=max"},
+		{"w=5000", "", "w=1920 fm=jpg fit=max"},
+		{"w=300&dpr=2", "", "w=640 fm=jpg fit=max"},
+		{"h=100", "", "h=320 fm=jpg fit=max"},
+		{"w=700&h=400", "", "w=960 h=640 fm=jpg fit=crop"},
+		{"", "image/avif,image/webp,*/*", "w=1920 fm=avif fit=max"},
+		{"fm=png", "image/webp", "w=1920 fm=jpg fit=max"},
+	} {
+		r := httptest.NewRequest(http.MethodGet, "/abc?"+tc.query, nil)
+		r.Header.Set("Accept", tc.accept)
+		opts, err := h.Options(r)
+		if err != nil {
+			t.Errorf("%s: %v", tc.query, err)
+			continue
+		}
+		got := fmt.Sprintf("w=%s fm=%s fit=%s", opts.Width, opts.ImageFormat, opts.Fit)
+		if opts.Width == "" {
+			got = fmt.Sprintf("h=%s fm=%s fit=%s", opts.Height, opts.ImageFormat, opts.Fit)
+		} else if opts.Height != "" {
+			got = fmt.Sprintf("w=%s h=%s fm=%s fit=%s", opts.Width, opts.Height, opts.ImageFormat, opts.Fit)
+		}
+		if got != tc.want {
+			t.Errorf("%s: expected %s, got %s", tc.query, tc.want, got)
+		}
+	}
+	// empty sizes and formats fall back to the defaults
+	h.Sizes, h.Formats = []int{}, nil
+	if opts, err := h.Options(httptest.NewRequest(http.MethodGet, "/abc?fm=webp", nil)); err != nil || opts.Width != "1920" || opts.ImageFormat != "webp" {
+		t.Errorf("expected the default sizes and formats, got %+v, %v", opts, err)
+	}
+	for _, invalid := range []string{"w=wide", "h=-1", "dpr=9"} {
+		if _, err := h.Options(httptest.NewRequest(http.MethodGet, "/abc?"+invalid, nil)); err == nil {
+			t.Errorf("expected %s to be invalid", invalid)
+		}
+	}
+}
+
+func TestImageHandler(t *testing.T) {
+	dir := t.TempDir()
+	h, ts, requests := setupImages(t, dir)
+
+	resp, body := get(t, ts.URL+"/abc?w=500&fm=webp")
+	if body != "w=640 h= fm=webp fit=max ixid=track" || resp.Header.Get("Content-Type") != "image/webp" || resp.Header.Get("X-Cache") != "MISS" {
+		t.Errorf("expected the normalized image, got %q, %q, X-Cache %q", body, resp.Header.Get("Content-Type"), resp.Header.Get("X-Cache"))
+	}
+	resp, body = get(t, ts.URL+"/abc?w=620&fm=webp&q=90")
+	if body != "w=640 h= fm=webp fit=max ixid=track" || resp.Header.Get("X-Cache") != "HIT" {
+		t.Errorf("expected the cached image, got %q, X-Cache %q", body, resp.Header.Get("X-Cache"))
+	}
+	if requests["/photo-abc"] != 1 || requests["/photos/abc"] != 1 || requests["/photos/abc/download"] != 0 {
+		t.Errorf("expected one photo and image request and no tracking, got %v", requests)
+	}
+	for i := 0; i < 2; i++ {
+		if resp, _ = get(t, ts.URL+"/missing?w=500"); resp.StatusCode != http.StatusNotFound {
+			t.Errorf("expected missing photos to be not found, got %d", resp.StatusCode)
+		}
+	}
+	for _, id := range []string{"..", "a.b", "a%2Fb", "..%2Fphotos", "a%5Cb", "a%20b"} {
+		if resp, _ = get(t, ts.URL+"/"+id+"?w=500"); resp.StatusCode != http.StatusNotFound {
+			t.Errorf("expected photo ID %q to be rejected, got %d", id, resp.StatusCode)
+		}
+	}
+	if len(requests) != 3 {
+		t.Errorf("expected invalid photo IDs not to reach the API, got %v", requests)
+	}
+	if requests["/photos/missing"] != 1 {
+		t.Errorf("expected a missing photo to be looked up once, got %d requests", requests["/photos/missing"])
+	}
+	if resp, _ = get(t, ts.URL+"/abc?w=wide"); resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("expected invalid sizes to be rejected, got %d", resp.StatusCode)
+	}
+
+	h.TrackDownloads = true
+	get(t, ts.URL+"/abc?w=500&fm=webp")
+	if requests["/photos/abc/download"] != 1 {
+		t.Errorf("expected the download to be tracked, got %v", requests)
+	}
+
+	// a new handler finds the images cached on disk
+	_, ts2, requests2 := setupImages(t, dir)
+	if resp, _ = get(t, ts2.URL+"/abc?w=640&fm=webp"); resp.Header.Get("X-Cache") != "HIT" || len(requests2) != 0 {
+		t.Errorf("expected the image cached on disk to be served, got X-Cache %q after %v", resp.Header.Get("X-Cache"), requests2)
+	}
+}
+
+func TestDiskCacheEviction(t *testing.T) {
+	dir := t.TempDir()
+	c, err := openDiskCache(dir, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.put("a", "jpg", []byte("aaaa"))
+	c.put("b", "jpg", []byte("bbbb"))
+	c.get("a", "jpg")
+	c.put("c", "jpg", []byte("cccc"))
+	if c.get("b", "jpg") != "" || c.get("a", "jpg") == "" || c.get("c", "jpg") == "" {
+		t.Errorf("expected the least recently used file to be evicted")
+	}
+	if files, _ := ioutil.ReadDir(dir); len(files) != 2 {
+		t.Errorf("expected the evicted file to be removed, got %d files", len(files))
+	}
+
+	reopened, err := openDiskCache(dir, 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if reopened.get("c", "jpg") == "" || reopened.get("a", "jpg") != "" {
+		t.Errorf("expected the most recently used file to be kept on reopening with a smaller size")
+	}
+}
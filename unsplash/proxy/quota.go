@@ -0,0 +1,99 @@
+package proxy
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Consumer defines an app allowed to use the proxy
+type Consumer struct {
+	Name string `json:"name"`
+	// Key is the API key the consumer sends in an `X-Api-Key` header, or as its Client-ID, in an
+	// `Authorization: Client-ID <key>` header or a `client_id` query parameter
+	Key string `json:"key"`
+	// Limit is the requests allowed per hour, including those served from the cache; 0 is unlimited
+	Limit int `json:"limit"`
+}
+
+// LoadConsumers reads consumers from a JSON file holding an array of consumers
+func LoadConsumers(path string) ([]Consumer, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var consumers []Consumer
+	if err := json.Unmarshal(data, &consumers); err != nil {
+		return nil, fmt.Errorf("error parsing consumers: %v", err)
+	}
+	names := make(map[string]bool)
+	keys := make(map[string]bool)
+	for i, c := range consumers {
+		if c.Name == "" || c.Key == "" {
+			return nil, fmt.Errorf("consumer %d needs a name and a key", i+1)
+		}
+		if names[c.Name] || keys[c.Key] {
+			return nil, fmt.Errorf("consumer %q: names and keys must be unique", c.Name)
+		}
+		names[c.Name], keys[c.Key] = true, true
+	}
+	return consumers, nil
+}
+
+// anonymous is the consumer of requests when no consumers are configured
+var anonymous = Consumer{Name: "anonymous"}
+
+// authenticate returns the consumer making r, and false if its API key is missing or unknown
+func (s *Server) authenticate(r *http.Request) (Consumer, bool) {
+	if len(s.consumers) == 0 {
+		return anonymous, true
+	}
+	key := r.Header.Get("X-Api-Key")
+	if auth := r.Header.Get("Authorization"); key == "" && strings.HasPrefix(auth, "Client-ID ") {
+		key = strings.TrimPrefix(auth, "Client-ID ")
+	}
+	if key == "" {
+		key = r.URL.Query().Get("client_id")
+	}
+	c, ok := s.consumers[key]
+	return c, ok
+}
+
+// quotas counts each consumer's requests in the current hour
+type quotas struct {
+	mu      sync.Mutex
+	windows map[string]*window // by consumer name
+}
+
+type window struct {
+	start time.Time
+	used  int
+}
+
+func newQuotas() *quotas {
+	return &quotas{windows: make(map[string]*window)}
+}
+
+// take counts a request by c at now, returning c's limit, the requests it has left,
+// and false if its quota is used up, in which case the request isn't counted
+func (q *quotas) take(c Consumer, now time.Time) (limit, remaining int, ok bool) {
+	if c.Limit <= 0 {
+		return 0, 0, true
+	}
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	w, exists := q.windows[c.Name]
+	if !exists || now.Sub(w.start) >= time.Hour {
+		w = &window{start: now}
+		q.windows[c.Name] = w
+	}
+	if w.used >= c.Limit {
+		return c.Limit, 0, false
+	}
+	w.used++
+	return c.Limit, c.Limit - w.used, true
+}
@@ -0,0 +1,392 @@
+package unsplash
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/eddogola/unsplash-go/unsplash/client"
+	"github.com/eddogola/unsplash-go/unsplash/colors"
+)
+
+// Search queries can be typed as a single line of text, mixing search terms and options:
+//
+//	mountain lake orientation:landscape color:blue -people lang:de collection:123 page:2
+//
+// Terms, including phrases in double quotes and words prefixed with `-`, make up the search query
+// sent to the API. `key:value` words set options. Quote a term containing a colon, e.g. `"10:30"`,
+// to search for it rather than set an option.
+
+// maxPerPage is the most results the API returns per page
+const maxPerPage = 30
+
+// ErrSearchSyntax is raised when a search query can't be parsed. It points at the bad token.
+type ErrSearchSyntax struct {
+	Query  string
+	Offset int    // byte offset of Token in Query
+	Token  string // empty when something is missing at the end of Query
+	Reason string
+}
+
+func (e ErrSearchSyntax) Error() string {
+	if e.Token == "" {
+		return "invalid search query: " + e.Reason
+	}
+	return fmt.Sprintf("invalid search query at %q (offset %d): %s", e.Token, e.Offset, e.Reason)
+}
+
+// Marker returns the query, with a line underneath pointing at the bad token
+func (e ErrSearchSyntax) Marker() string {
+	width := utf8.RuneCountInString(e.Token)
+	if width == 0 {
+		width = 1
+	}
+	pad := utf8.RuneCountInString(e.Query[:e.Offset])
+	return e.Query + "\n" + strings.Repeat(" ", pad) + "^" + strings.Repeat("~", width-1)
+}
+
+// PhotoSearchOptions defines the parameters of a photo search.
+// https://unsplash.com/documentation#search-photos
+type PhotoSearchOptions struct {
+	Query         string
+	Page          int
+	PerPage       int
+	OrderBy       string   // relevant or latest
+	Collections   []string // IDs of collections to narrow the search to
+	ContentFilter string   // low or high
+	Color         string   // one of the color names in package colors
+	Orientation   string   // landscape, portrait or squarish
+	Lang          string   // ISO 639-1 code of the query's language
+}
+
+// CollectionSearchOptions defines the parameters of a collection search.
+// https://unsplash.com/documentation#search-collections
+type CollectionSearchOptions struct {
+	Query   string
+	Page    int
+	PerPage int
+}
+
+// UserSearchOptions defines the parameters of a user search.
+// https://unsplash.com/documentation#search-users
+type UserSearchOptions struct {
+	Query   string
+	Page    int
+	PerPage int
+}
+
+// ParsePhotoSearch parses a photo search query. Its options are `orientation`, `color`, `lang`,
+// `collection` (comma separated IDs, may be repeated), `order_by`, `content_filter`, `page` and
+// `per_page`.
+func ParsePhotoSearch(query string) (*PhotoSearchOptions, error) {
+	var o PhotoSearchOptions
+	options := pageOptions(&o.Page, &o.PerPage)
+	options["orientation"] = enumOption("orientation", &o.Orientation, "landscape", "portrait", "squarish")
+	options["color"] = enumOption("color", &o.Color, colors.BlackAndWhite, colors.Black, colors.White, colors.Yellow,
+		colors.Orange, colors.Red, colors.Purple, colors.Magenta, colors.Green, colors.Teal, colors.Blue)
+	options["order_by"] = enumOption("order_by", &o.OrderBy, "relevant", "latest")
+	options["content_filter"] = enumOption("content_filter", &o.ContentFilter, "low", "high")
+	options["lang"] = searchOption{name: "lang", set: func(v string) error {
+		v = strings.ToLower(v)
+		if len(v) != 2 || !isLetters(v) {
+			return fmt.Errorf("lang must be a two letter ISO 639-1 code, e.g. de")
+		}
+		o.Lang = v
+		return nil
+	}}
+	collection := searchOption{name: "collection", repeatable: true, set: func(v string) error {
+		for _, id := range strings.Split(v, ",") {
+			if id == "" {
+				return fmt.Errorf("collection must be comma separated collection IDs")
+			}
+			o.Collections = append(o.Collections, id)
+		}
+		return nil
+	}}
+	options["collection"], options["collections"] = collection, collection
+
+	var err error
+	if o.Query, err = parseSearch(query, options); err != nil {
+		return nil, err
+	}
+	return &o, nil
+}
+
+// ParseCollectionSearch parses a collection search query. Its options are `page` and `per_page`.
+func ParseCollectionSearch(query string) (*CollectionSearchOptions, error) {
+	var o CollectionSearchOptions
+	var err error
+	if o.Query, err = parseSearch(query, pageOptions(&o.Page, &o.PerPage)); err != nil {
+		return nil, err
+	}
+	return &o, nil
+}
+
+// ParseUserSearch parses a user search query. Its options are `page` and `per_page`.
+func ParseUserSearch(query string) (*UserSearchOptions, error) {
+	var o UserSearchOptions
+	var err error
+	if o.Query, err = parseSearch(query, pageOptions(&o.Page, &o.PerPage)); err != nil {
+		return nil, err
+	}
+	return &o, nil
+}
+
+// QueryParams returns the options as the query parameters of PhotosService.Search
+func (o PhotoSearchOptions) QueryParams() client.QueryParams {
+	qp := pageParams(o.Query, o.Page, o.PerPage)
+	set := map[string]string{
+		"order_by":       o.OrderBy,
+		"collections":    strings.Join(o.Collections, ","),
+		"content_filter": o.ContentFilter,
+		"color":          o.Color,
+		"orientation":    o.Orientation,
+		"lang":           o.Lang,
+	}
+	for k, v := range set {
+		if v != "" {
+			qp[k] = v
+		}
+	}
+	return qp
+}
+
+// QueryParams returns the options as the query parameters of CollectionsService.Search
+func (o CollectionSearchOptions) QueryParams() client.QueryParams {
+	return pageParams(o.Query, o.Page, o.PerPage)
+}
+
+// QueryParams returns the options as the query parameters of UsersService.Search
+func (o UserSearchOptions) QueryParams() client.QueryParams {
+	return pageParams(o.Query, o.Page, o.PerPage)
+}
+
+// String formats the options in the search query mini-language, to be parsed by ParsePhotoSearch
+func (o PhotoSearchOptions) String() string {
+	opts := []string{
+		formatOption("orientation", o.Orientation),
+		formatOption("color", o.Color),
+		formatOption("lang", o.Lang),
+		formatOption("collection", strings.Join(o.Collections, ",")),
+		formatOption("order_by", o.OrderBy),
+		formatOption("content_filter", o.ContentFilter),
+	}
+	return formatSearch(o.Query, append(opts, formatPage(o.Page, o.PerPage)...)...)
+}
+
+// String formats the options in the search query mini-language, to be parsed by ParseCollectionSearch
+func (o CollectionSearchOptions) String() string {
+	return formatSearch(o.Query, formatPage(o.Page, o.PerPage)...)
+}
+
+// String formats the options in the search query mini-language, to be parsed by ParseUserSearch
+func (o UserSearchOptions) String() string {
+	return formatSearch(o.Query, formatPage(o.Page, o.PerPage)...)
+}
+
+// searchOption defines an option of the mini-language, set from its value
+type searchOption struct {
+	name       string // the option's name in errors, shared by its aliases
+	repeatable bool
+	set        func(value string) error
+}
+
+func pageOptions(page, perPage *int) map[string]searchOption {
+	return map[string]searchOption{
+		"page":     intOption("page", page, 1, 0),
+		"per_page": intOption("per_page", perPage, 1, maxPerPage),
+	}
+}
+
+// intOption sets dst to a number from min to max, or any number from min if max is 0
+func intOption(name string, dst *int, min, max int) searchOption {
+	return searchOption{name: name, set: func(v string) error {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < min || max > 0 && n > max {
+			if max > 0 {
+				return fmt.Errorf("%s must be a number from %d to %d", name, min, max)
+			}
+			return fmt.Errorf("%s must be a number from %d", name, min)
+		}
+		*dst = n
+		return nil
+	}}
+}
+
+// enumOption sets dst to one of the allowed values, ignoring case
+func enumOption(name string, dst *string, allowed ...string) searchOption {
+	return searchOption{name: name, set: func(v string) error {
+		for _, a := range allowed {
+			if strings.EqualFold(v, a) {
+				*dst = a
+				return nil
+			}
+		}
+		return fmt.Errorf("%s must be one of %s", name, strings.Join(allowed, ", "))
+	}}
+}
+
+// searchToken defines a term or an option of a search query
+type searchToken struct {
+	text   string
+	offset int
+	key    string // set for options
+	value  string
+}
+
+// lexSearch splits a search query into terms and options, separated by whitespace
+// outside double quotes
+func lexSearch(query string) ([]searchToken, error) {
+	var tokens []searchToken
+	i := 0
+	for i < len(query) {
+		r, size := utf8.DecodeRuneInString(query[i:])
+		if unicode.IsSpace(r) {
+			i += size
+			continue
+		}
+		start := i
+		quoted := false
+		for i < len(query) {
+			r, size := utf8.DecodeRuneInString(query[i:])
+			if unicode.IsSpace(r) && !quoted {
+				break
+			}
+			if r == '"' {
+				quoted = !quoted
+			}
+			i += size
+		}
+		t := searchToken{text: query[start:i], offset: start}
+		if quoted {
+			return nil, ErrSearchSyntax{query, start, t.text, "unterminated quote"}
+		}
+		if j := strings.IndexByte(t.text, ':'); j > 0 && !strings.Contains(t.text, `"`) && isKey(t.text[:j]) {
+			t.key, t.value = strings.ToLower(t.text[:j]), t.text[j+1:]
+		}
+		tokens = append(tokens, t)
+	}
+	return tokens, nil
+}
+
+// parseSearch sets the options in query, returning its terms
+func parseSearch(query string, options map[string]searchOption) (string, error) {
+	tokens, err := lexSearch(query)
+	if err != nil {
+		return "", err
+	}
+	var terms []string
+	seen := make(map[string]bool)
+	for _, t := range tokens {
+		if t.key == "" {
+			terms = append(terms, unquoteTerm(t.text))
+			continue
+		}
+		opt, ok := options[t.key]
+		switch {
+		case !ok:
+			return "", ErrSearchSyntax{query, t.offset, t.text, fmt.Sprintf("unknown option %q, quote the term to search for it", t.key)}
+		case t.value == "":
+			return "", ErrSearchSyntax{query, t.offset, t.text, fmt.Sprintf("missing value of %s", opt.name)}
+		case seen[opt.name] && !opt.repeatable:
+			return "", ErrSearchSyntax{query, t.offset, t.text, fmt.Sprintf("%s set more than once", opt.name)}
+		}
+		if err := opt.set(t.value); err != nil {
+			return "", ErrSearchSyntax{query, t.offset, t.text, err.Error()}
+		}
+		seen[opt.name] = true
+	}
+	if len(terms) == 0 {
+		return "", ErrSearchSyntax{query, len(query), "", "no search terms"}
+	}
+	return strings.Join(terms, " "), nil
+}
+
+// unquoteTerm removes the quotes around a single word, which are only needed to keep it from
+// being read as an option. Quotes around phrases are kept for the API.
+func unquoteTerm(term string) string {
+	prefix := ""
+	if strings.HasPrefix(term, "-") {
+		prefix, term = "-", term[1:]
+	}
+	if len(term) < 2 || term[0] != '"' || term[len(term)-1] != '"' {
+		return prefix + term
+	}
+	inner := term[1 : len(term)-1]
+	if inner == "" || strings.ContainsAny(inner, `"`) || strings.IndexFunc(inner, unicode.IsSpace) >= 0 {
+		return prefix + term
+	}
+	return prefix + inner
+}
+
+// formatSearch joins the query's terms and the options, quoting terms that would be read as options
+func formatSearch(query string, options ...string) string {
+	var words []string
+	tokens, err := lexSearch(query)
+	if err != nil {
+		words = append(words, query)
+	}
+	for _, t := range tokens {
+		if t.key != "" {
+			words = append(words, `"`+t.text+`"`)
+			continue
+		}
+		words = append(words, t.text)
+	}
+	for _, opt := range options {
+		if opt != "" {
+			words = append(words, opt)
+		}
+	}
+	return strings.Join(words, " ")
+}
+
+func formatOption(key, value string) string {
+	if value == "" {
+		return ""
+	}
+	return key + ":" + value
+}
+
+func formatPage(page, perPage int) []string {
+	var opts []string
+	if page > 0 {
+		opts = append(opts, "page:"+strconv.Itoa(page))
+	}
+	if perPage > 0 {
+		opts = append(opts, "per_page:"+strconv.Itoa(perPage))
+	}
+	return opts
+}
+
+func pageParams(query string, page, perPage int) client.QueryParams {
+	qp := client.QueryParams{"query": query}
+	if page > 0 {
+		qp["page"] = strconv.Itoa(page)
+	}
+	if perPage > 0 {
+		qp["per_page"] = strconv.Itoa(perPage)
+	}
+	return qp
+}
+
+// isKey reports whether s can be an option's key: letters and underscores
+func isKey(s string) bool {
+	for _, r := range s {
+		if r != '_' && !unicode.IsLetter(r) {
+			return false
+		}
+	}
+	return true
+}
+
+func isLetters(s string) bool {
+	for _, r := range s {
+		if !unicode.IsLetter(r) {
+			return false
+		}
+	}
+	return s != ""
+}
@@ -0,0 +1,130 @@
+package unsplash
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/eddogola/unsplash-go/unsplash/client"
+)
+
+func TestSearchQuery(t *testing.T) {
+	t.Run("parse photo search", func(t *testing.T) {
+		got, err := ParsePhotoSearch(`mountain lake orientation:landscape color:Blue -people lang:DE collection:123 page:2 "misty morning"`)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		expected := &PhotoSearchOptions{
+			Query:       `mountain lake -people "misty morning"`,
+			Page:        2,
+			Collections: []string{"123"},
+			Color:       "blue",
+			Orientation: "landscape",
+			Lang:        "de",
+		}
+		if !reflect.DeepEqual(got, expected) {
+			t.Errorf("expected %+v but got %+v", expected, got)
+		}
+		params := client.QueryParams{
+			"query":       `mountain lake -people "misty morning"`,
+			"page":        "2",
+			"collections": "123",
+			"color":       "blue",
+			"orientation": "landscape",
+			"lang":        "de",
+		}
+		if !reflect.DeepEqual(got.QueryParams(), params) {
+			t.Errorf("expected %v but got %v", params, got.QueryParams())
+		}
+	})
+
+	t.Run("parse options", func(t *testing.T) {
+		got, err := ParsePhotoSearch(`"10:30" collections:1,2 collection:3 order_by:latest content_filter:high per_page:30`)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got.Query != "10:30" {
+			t.Errorf("expected the quoted term to be searched but got %q", got.Query)
+		}
+		if !reflect.DeepEqual(got.Collections, []string{"1", "2", "3"}) {
+			t.Errorf("expected collections 1, 2 and 3 but got %v", got.Collections)
+		}
+		if got.OrderBy != "latest" || got.ContentFilter != "high" || got.PerPage != 30 {
+			t.Errorf("unexpected options %+v", got)
+		}
+
+		users, err := ParseUserSearch("jane page:3 per_page:10")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if expected := (&UserSearchOptions{"jane", 3, 10}); !reflect.DeepEqual(users, expected) {
+			t.Errorf("expected %+v but got %+v", expected, users)
+		}
+		if _, err := ParseCollectionSearch("nature color:blue"); err == nil {
+			t.Error("expected photo options to be rejected in collection searches")
+		}
+	})
+
+	t.Run("errors point at the bad token", func(t *testing.T) {
+		cases := []struct {
+			query  string
+			offset int
+			token  string
+		}{
+			{"lake orientation:diagonal", 5, "orientation:diagonal"},
+			{"lake color:beige page:2", 5, "color:beige"},
+			{"lake page:0", 5, "page:0"},
+			{"lake per_page:31", 5, "per_page:31"},
+			{"lake lang:deu", 5, "lang:deu"},
+			{"lake size:large", 5, "size:large"},
+			{"lake page:", 5, "page:"},
+			{"lake page:1 page:2", 12, "page:2"},
+			{`lake "misty morning`, 5, `"misty morning`},
+			{"page:2", 6, ""},
+			{"", 0, ""},
+		}
+		for _, c := range cases {
+			_, err := ParsePhotoSearch(c.query)
+			e, ok := err.(ErrSearchSyntax)
+			if !ok {
+				t.Errorf("%q: expected an ErrSearchSyntax but got %v", c.query, err)
+				continue
+			}
+			if e.Offset != c.offset || e.Token != c.token {
+				t.Errorf("%q: expected %q at %d but got %q at %d", c.query, c.token, c.offset, e.Token, e.Offset)
+			}
+		}
+
+		_, err := ParsePhotoSearch("lake orientation:diagonal")
+		expected := "lake orientation:diagonal\n     ^~~~~~~~~~~~~~~~~~~~"
+		if got := err.(ErrSearchSyntax).Marker(); got != expected {
+			t.Errorf("expected marker\n%s\nbut got\n%s", expected, got)
+		}
+	})
+
+	t.Run("format round-trips", func(t *testing.T) {
+		queries := []string{
+			`mountain lake -people "misty morning" orientation:landscape color:blue lang:de collection:123,456 order_by:latest content_filter:high page:2 per_page:30`,
+			`"10:30" "color:blue" forest`,
+			"forest",
+		}
+		for _, q := range queries {
+			o, err := ParsePhotoSearch(q)
+			if err != nil {
+				t.Fatalf("unexpected error parsing %q: %v", q, err)
+			}
+			again, err := ParsePhotoSearch(o.String())
+			if err != nil {
+				t.Fatalf("unexpected error parsing %q: %v", o.String(), err)
+			}
+			if !reflect.DeepEqual(again, o) {
+				t.Errorf("expected %+v but got %+v", o, again)
+			}
+		}
+		if got := (PhotoSearchOptions{Query: "color:blue sky", Color: "blue"}).String(); got != `"color:blue" sky color:blue` {
+			t.Errorf("expected terms that look like options to be quoted but got %q", got)
+		}
+		if got := (CollectionSearchOptions{Query: "nature", Page: 2}).String(); got != "nature page:2" {
+			t.Errorf("expected nature page:2 but got %q", got)
+		}
+	})
+}
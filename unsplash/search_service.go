@@ -0,0 +1,120 @@
+package unsplash
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/eddogola/unsplash-go/unsplash/client"
+)
+
+// SearchServiceClient defines client methods used to search photos, collections and users
+type SearchServiceClient interface {
+	SearchPhotos(context.Context, client.QueryParams) (*client.PhotoSearchResult, error)
+	SearchCollections(context.Context, client.QueryParams) (*client.CollectionSearchResult, error)
+	SearchUsers(context.Context, client.QueryParams) (*client.UserSearchResult, error)
+}
+
+// SearchService contains an underlying Unsplash client to
+// be used for http methods
+type SearchService struct {
+	client SearchServiceClient
+}
+
+// SearchResult defines the combined results of searching photos, collections and users.
+// The results of a search that failed are nil, and its error is set.
+type SearchResult struct {
+	Photos      *client.PhotoSearchResult
+	Collections *client.CollectionSearchResult
+	Users       *client.UserSearchResult
+	Totals      SearchTotals
+
+	PhotosErr      error
+	CollectionsErr error
+	UsersErr       error
+}
+
+// SearchTotals defines the total number of results of each type, 0 for searches that failed
+type SearchTotals struct {
+	Photos      int `json:"photos"`
+	Collections int `json:"collections"`
+	Users       int `json:"users"`
+}
+
+// ErrSearchFailed is raised when the photo, collection and user searches all fail
+type ErrSearchFailed struct {
+	Photos, Collections, Users error
+}
+
+func (e ErrSearchFailed) Error() string {
+	return fmt.Sprintf("search failed: photos: %v; collections: %v; users: %v", e.Photos, e.Collections, e.Users)
+}
+
+// Partial reports whether some of the searches failed
+func (r *SearchResult) Partial() bool {
+	return r.PhotosErr != nil || r.CollectionsErr != nil || r.UsersErr != nil
+}
+
+// All searches photos, collections and users concurrently. queryParams, e.g. `page` and `per_page`,
+// apply to the photo search; only `page` and `per_page` apply to the collection and user searches.
+// Searches that fail are reported in the result, and an ErrSearchFailed is returned only if all fail.
+func (ss *SearchService) All(searchQuery string, queryParams client.QueryParams) (*SearchResult, error) {
+	ctx := context.Background()
+	photoParams := client.QueryParams{"query": searchQuery}
+	params := client.QueryParams{"query": searchQuery}
+	for k, v := range queryParams {
+		if k == "query" {
+			continue
+		}
+		photoParams[k] = v
+		if k == "page" || k == "per_page" {
+			params[k] = v
+		}
+	}
+
+	var res SearchResult
+	var wg sync.WaitGroup
+	wg.Add(3)
+	go func() {
+		defer wg.Done()
+		res.Photos, res.PhotosErr = ss.client.SearchPhotos(ctx, photoParams)
+	}()
+	go func() {
+		defer wg.Done()
+		res.Collections, res.CollectionsErr = ss.client.SearchCollections(ctx, copyParams(params))
+	}()
+	go func() {
+		defer wg.Done()
+		res.Users, res.UsersErr = ss.client.SearchUsers(ctx, copyParams(params))
+	}()
+	wg.Wait()
+
+	if res.PhotosErr != nil && res.CollectionsErr != nil && res.UsersErr != nil {
+		return nil, ErrSearchFailed{res.PhotosErr, res.CollectionsErr, res.UsersErr}
+	}
+	if res.PhotosErr != nil {
+		res.Photos = nil
+	} else if res.Photos != nil {
+		res.Totals.Photos = res.Photos.Total
+	}
+	if res.CollectionsErr != nil {
+		res.Collections = nil
+	} else if res.Collections != nil {
+		res.Totals.Collections = res.Collections.Total
+	}
+	if res.UsersErr != nil {
+		res.Users = nil
+	} else if res.Users != nil {
+		res.Totals.Users = res.Users.Total
+	}
+	return &res, nil
+}
+
+// copyParams copies query parameters, so that concurrent requests don't share a map
+func copyParams(queryParams client.QueryParams) client.QueryParams {
+	cp := make(client.QueryParams, len(queryParams))
+	for k, v := range queryParams {
+		cp[k] = v
+	}
+	return cp
+}
@@ -0,0 +1,104 @@
+package unsplash
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/eddogola/unsplash-go/unsplash/client"
+)
+
+// mockSearchServiceClient records the query parameters of each search, failing the searches in fail
+type mockSearchServiceClient struct {
+	mu     sync.Mutex
+	params map[string]client.QueryParams
+	fail   map[string]bool
+	delay  time.Duration
+}
+
+func (m *mockSearchServiceClient) record(kind string, queryParams client.QueryParams) error {
+	time.Sleep(m.delay)
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.params == nil {
+		m.params = make(map[string]client.QueryParams)
+	}
+	m.params[kind] = queryParams
+	if m.fail[kind] {
+		return errors.New(kind + " unavailable")
+	}
+	return nil
+}
+
+func (m *mockSearchServiceClient) SearchPhotos(ctx context.Context, queryParams client.QueryParams) (*client.PhotoSearchResult, error) {
+	if err := m.record("photos", queryParams); err != nil {
+		return nil, err
+	}
+	return &client.PhotoSearchResult{Total: 120, TotalPages: 12, Results: pics}, nil
+}
+
+func (m *mockSearchServiceClient) SearchCollections(ctx context.Context, queryParams client.QueryParams) (*client.CollectionSearchResult, error) {
+	if err := m.record("collections", queryParams); err != nil {
+		return nil, err
+	}
+	return &client.CollectionSearchResult{Total: 7, TotalPages: 1}, nil
+}
+
+func (m *mockSearchServiceClient) SearchUsers(ctx context.Context, queryParams client.QueryParams) (*client.UserSearchResult, error) {
+	if err := m.record("users", queryParams); err != nil {
+		return nil, err
+	}
+	return &client.UserSearchResult{Total: 3, TotalPages: 1}, nil
+}
+
+func TestSearchService(t *testing.T) {
+	t.Run("search all types concurrently", func(t *testing.T) {
+		m := &mockSearchServiceClient{delay: 50 * time.Millisecond}
+		mockUnsplash := &Unsplash{Search: &SearchService{client: m}}
+		start := time.Now()
+		res, err := mockUnsplash.Search.All("forest", client.QueryParams{"page": "2", "per_page": "5", "color": "green"})
+		checkErrorIsNil(t, err)
+		checkRsNotNil(t, res)
+		if elapsed := time.Since(start); elapsed >= 150*time.Millisecond {
+			t.Errorf("expected searches to run concurrently but took %v", elapsed)
+		}
+		if expected := (SearchTotals{120, 7, 3}); res.Totals != expected {
+			t.Errorf("expected totals %+v but got %+v", expected, res.Totals)
+		}
+		if res.Partial() || res.Photos == nil || res.Collections == nil || res.Users == nil {
+			t.Errorf("expected every search to succeed but got %+v", res)
+		}
+		for kind, params := range m.params {
+			if params["query"] != "forest" || params["page"] != "2" || params["per_page"] != "5" {
+				t.Errorf("expected %s search to share the query and pagination but got %v", kind, params)
+			}
+			if _, ok := params["color"]; ok != (kind == "photos") {
+				t.Errorf("expected color to be passed only to the photo search, %s got %v", kind, params)
+			}
+		}
+	})
+
+	t.Run("partial failure", func(t *testing.T) {
+		m := &mockSearchServiceClient{fail: map[string]bool{"collections": true}}
+		mockUnsplash := &Unsplash{Search: &SearchService{client: m}}
+		res, err := mockUnsplash.Search.All("forest", nil)
+		checkErrorIsNil(t, err)
+		if !res.Partial() || res.CollectionsErr == nil || res.Collections != nil {
+			t.Errorf("expected the collection search to fail but got %+v", res)
+		}
+		if expected := (SearchTotals{Photos: 120, Users: 3}); res.Totals != expected {
+			t.Errorf("expected totals %+v but got %+v", expected, res.Totals)
+		}
+	})
+
+	t.Run("every search fails", func(t *testing.T) {
+		m := &mockSearchServiceClient{fail: map[string]bool{"photos": true, "collections": true, "users": true}}
+		mockUnsplash := &Unsplash{Search: &SearchService{client: m}}
+		res, err := mockUnsplash.Search.All("forest", nil)
+		if _, ok := err.(ErrSearchFailed); !ok || res != nil {
+			t.Errorf("expected an ErrSearchFailed but got %v, %v", res, err)
+		}
+	})
+}
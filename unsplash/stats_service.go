@@ -0,0 +1,55 @@
+package unsplash
+
+import (
+	"context"
+	"time"
+
+	"github.com/eddogola/unsplash-go/unsplash/client"
+)
+
+// StatsServiceClient defines client methods used to get Unsplash-wide stats
+type StatsServiceClient interface {
+	GetStatsTotal(context.Context) (*client.StatsTotal, error)
+	GetStatsMonth(context.Context) (*client.StatsMonth, error)
+}
+
+// StatsService contains an underlying Unsplash client to
+// be used for http methods
+type StatsService struct {
+	client StatsServiceClient
+}
+
+// StatsSnapshot records both total and monthly Unsplash stats, as they were
+// at the time they were requested
+type StatsSnapshot struct {
+	Time  time.Time          `json:"time"`
+	Total *client.StatsTotal `json:"total"`
+	Month *client.StatsMonth `json:"month"`
+}
+
+// Total returns counts for all of Unsplash
+func (ss *StatsService) Total() (*client.StatsTotal, error) {
+	ctx := context.Background()
+	return ss.client.GetStatsTotal(ctx)
+}
+
+// Month returns the overall Unsplash stats for the past 30 days
+func (ss *StatsService) Month() (*client.StatsMonth, error) {
+	ctx := context.Background()
+	return ss.client.GetStatsMonth(ctx)
+}
+
+// Snapshot requests both total and monthly stats, returning them in a timestamped StatsSnapshot.
+// It's suitable for collecting stats periodically, e.g. using a time.Ticker.
+func (ss *StatsService) Snapshot() (*StatsSnapshot, error) {
+	ctx := context.Background()
+	total, err := ss.client.GetStatsTotal(ctx)
+	if err != nil {
+		return nil, err
+	}
+	month, err := ss.client.GetStatsMonth(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &StatsSnapshot{Time: time.Now().UTC(), Total: total, Month: month}, nil
+}
@@ -0,0 +1,59 @@
+package unsplash
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/eddogola/unsplash-go/unsplash/client"
+)
+
+var statsTotal = client.StatsTotal{Photos: 1000, Downloads: 2000, Photographers: 30}
+
+var statsMonth = client.StatsMonth{Downloads: 100, NewPhotos: 10}
+
+type mockStatsServiceClient struct{}
+
+func (m *mockStatsServiceClient) GetStatsTotal(ctx context.Context) (*client.StatsTotal, error) {
+	return &statsTotal, nil
+}
+
+func (m *mockStatsServiceClient) GetStatsMonth(ctx context.Context) (*client.StatsMonth, error) {
+	return &statsMonth, nil
+}
+
+func TestStatsService(t *testing.T) {
+	mockUnsplash := &Unsplash{
+		Stats: &StatsService{client: &mockStatsServiceClient{}},
+	}
+
+	t.Run("total stats", func(t *testing.T) {
+		res, err := mockUnsplash.Stats.Total()
+		checkErrorIsNil(t, err)
+		checkRsNotNil(t, res)
+		if !reflect.DeepEqual(res, &statsTotal) {
+			t.Errorf("expected %v but got %v", statsTotal, res)
+		}
+	})
+
+	t.Run("monthly stats", func(t *testing.T) {
+		res, err := mockUnsplash.Stats.Month()
+		checkErrorIsNil(t, err)
+		checkRsNotNil(t, res)
+		if !reflect.DeepEqual(res, &statsMonth) {
+			t.Errorf("expected %v but got %v", statsMonth, res)
+		}
+	})
+
+	t.Run("stats snapshot", func(t *testing.T) {
+		res, err := mockUnsplash.Stats.Snapshot()
+		checkErrorIsNil(t, err)
+		checkRsNotNil(t, res)
+		if res.Time.IsZero() {
+			t.Errorf("expected snapshot time to be set")
+		}
+		if !reflect.DeepEqual(res.Total, &statsTotal) || !reflect.DeepEqual(res.Month, &statsMonth) {
+			t.Errorf("expected %v and %v but got %v and %v", statsTotal, statsMonth, res.Total, res.Month)
+		}
+	})
+}
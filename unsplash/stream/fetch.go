@@ -0,0 +1,235 @@
+package stream
+
+import (
+	"context"
+	"sync"
+
+	"github.com/eddogola/unsplash-go/unsplash/client"
+)
+
+// DefaultConcurrency is the number of pages a Fetcher requests at once
+const DefaultConcurrency = 4
+
+// Fetcher requests every page of a list or search endpoint, returning their results together.
+//
+// The first page reports the number of pages: searches in their response, list endpoints in the
+// `X-Total` header. The other pages are then requested concurrently, and their results are put back
+// in order. Results that shift onto the next page while the pages are requested, e.g. because
+// photos were added to a collection, are returned once. Results added beyond the pages reported by
+// the first page are not returned. Endpoints that don't report the number of pages are requested
+// one page at a time.
+type Fetcher struct {
+	client Client
+	// PerPage is the number of results requested per page, from 1 to 30
+	PerPage int
+	// Concurrency is the most pages requested at once
+	Concurrency int
+	// MaxPages bounds the number of pages requested, unless it's 0
+	MaxPages int
+}
+
+// NewFetcher constructs a Fetcher requesting pages of DefaultPerPage results, DefaultConcurrency at once
+func NewFetcher(c Client) *Fetcher {
+	return &Fetcher{client: c, PerPage: DefaultPerPage, Concurrency: DefaultConcurrency}
+}
+
+// Photos returns the editorial feed of photos. queryParams, e.g. `order_by`, are passed
+// to each request; `page` and `per_page` are set by the Fetcher.
+func (f *Fetcher) Photos(ctx context.Context, queryParams client.QueryParams) ([]client.Photo, error) {
+	return f.photos(ctx, list(photoList(f.client), queryParams, f.perPage()))
+}
+
+// CollectionPhotos returns a collection's photos
+func (f *Fetcher) CollectionPhotos(ctx context.Context, id string, queryParams client.QueryParams) ([]client.Photo, error) {
+	return f.photos(ctx, list(collectionPhotos(f.client, id), queryParams, f.perPage()))
+}
+
+// TopicPhotos returns a topic's photos
+func (f *Fetcher) TopicPhotos(ctx context.Context, idOrSlug string, queryParams client.QueryParams) ([]client.Photo, error) {
+	return f.photos(ctx, list(topicPhotos(f.client, idOrSlug), queryParams, f.perPage()))
+}
+
+// UserPhotos returns the photos uploaded by a user
+func (f *Fetcher) UserPhotos(ctx context.Context, username string, queryParams client.QueryParams) ([]client.Photo, error) {
+	return f.photos(ctx, list(userPhotos(f.client, username), queryParams, f.perPage()))
+}
+
+// UserLikedPhotos returns the photos liked by a user
+func (f *Fetcher) UserLikedPhotos(ctx context.Context, username string, queryParams client.QueryParams) ([]client.Photo, error) {
+	return f.photos(ctx, list(userLikedPhotos(f.client, username), queryParams, f.perPage()))
+}
+
+// SearchPhotos returns every result of a photo search
+func (f *Fetcher) SearchPhotos(ctx context.Context, searchQuery string, queryParams client.QueryParams) ([]client.Photo, error) {
+	return f.photos(ctx, search(photoSearch(f.client), searchQuery, queryParams, f.perPage()))
+}
+
+// Collections returns every collection
+func (f *Fetcher) Collections(ctx context.Context, queryParams client.QueryParams) ([]client.Collection, error) {
+	return f.collections(ctx, list(collectionList(f.client), queryParams, f.perPage()))
+}
+
+// UserCollections returns the collections created by a user
+func (f *Fetcher) UserCollections(ctx context.Context, username string, queryParams client.QueryParams) ([]client.Collection, error) {
+	return f.collections(ctx, list(userCollections(f.client, username), queryParams, f.perPage()))
+}
+
+// SearchCollections returns every result of a collection search
+func (f *Fetcher) SearchCollections(ctx context.Context, searchQuery string, queryParams client.QueryParams) ([]client.Collection, error) {
+	return f.collections(ctx, search(collectionSearch(f.client), searchQuery, queryParams, f.perPage()))
+}
+
+// SearchUsers returns every result of a user search
+func (f *Fetcher) SearchUsers(ctx context.Context, searchQuery string, queryParams client.QueryParams) ([]client.User, error) {
+	return f.users(ctx, search(userSearch(f.client), searchQuery, queryParams, f.perPage()))
+}
+
+func (f *Fetcher) photos(ctx context.Context, fetch fetchFunc) ([]client.Photo, error) {
+	pages, err := f.fetchAll(ctx, fetch)
+	if err != nil {
+		return nil, err
+	}
+	var pics []client.Photo
+	seen := make(map[string]bool)
+	for _, p := range pages {
+		for _, pic := range p.([]client.Photo) {
+			if !seen[pic.ID] {
+				seen[pic.ID] = true
+				pics = append(pics, pic)
+			}
+		}
+	}
+	return pics, nil
+}
+
+func (f *Fetcher) collections(ctx context.Context, fetch fetchFunc) ([]client.Collection, error) {
+	pages, err := f.fetchAll(ctx, fetch)
+	if err != nil {
+		return nil, err
+	}
+	var collections []client.Collection
+	seen := make(map[string]bool)
+	for _, p := range pages {
+		for _, c := range p.([]client.Collection) {
+			if !seen[c.ID] {
+				seen[c.ID] = true
+				collections = append(collections, c)
+			}
+		}
+	}
+	return collections, nil
+}
+
+func (f *Fetcher) users(ctx context.Context, fetch fetchFunc) ([]client.User, error) {
+	pages, err := f.fetchAll(ctx, fetch)
+	if err != nil {
+		return nil, err
+	}
+	var users []client.User
+	seen := make(map[string]bool)
+	for _, p := range pages {
+		for _, u := range p.([]client.User) {
+			if !seen[u.ID] {
+				seen[u.ID] = true
+				users = append(users, u)
+			}
+		}
+	}
+	return users, nil
+}
+
+func (f *Fetcher) perPage() int {
+	return validPerPage(f.PerPage)
+}
+
+// fetchAll returns the results of every page, in order
+func (f *Fetcher) fetchAll(ctx context.Context, fetch fetchFunc) ([]interface{}, error) {
+	first, err := fetch(ctx, 1)
+	if err != nil {
+		return nil, err
+	}
+	pages := []interface{}{first.results}
+	if first.last(1, f.perPage()) || f.MaxPages == 1 {
+		return pages, nil
+	}
+	if first.totalPages == 0 {
+		return f.fetchSequentially(ctx, fetch, pages)
+	}
+
+	n := first.totalPages
+	if f.MaxPages > 0 && n > f.MaxPages {
+		n = f.MaxPages
+	}
+	pages = append(pages, make([]interface{}, n-1)...)
+	workers := f.Concurrency
+	if workers < 1 {
+		workers = 1
+	}
+	if workers > n-1 {
+		workers = n - 1
+	}
+
+	fetchCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	numbers := make(chan int)
+	var wg sync.WaitGroup
+	var errOnce sync.Once
+	var fetchErr error
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for number := range numbers {
+				if fetchCtx.Err() != nil {
+					continue
+				}
+				p, err := fetch(fetchCtx, number)
+				if err != nil {
+					// stop requesting pages on the first error
+					errOnce.Do(func() {
+						fetchErr = err
+						cancel()
+					})
+					continue
+				}
+				pages[number-1] = p.results
+			}
+		}()
+	}
+feed:
+	for number := 2; number <= n; number++ {
+		select {
+		case numbers <- number:
+		case <-fetchCtx.Done():
+			break feed
+		}
+	}
+	close(numbers)
+	wg.Wait()
+
+	if fetchErr != nil {
+		return nil, fetchErr
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return pages, nil
+}
+
+// fetchSequentially requests the pages after those in pages one at a time, until the last page
+func (f *Fetcher) fetchSequentially(ctx context.Context, fetch fetchFunc, pages []interface{}) ([]interface{}, error) {
+	for number := len(pages) + 1; f.MaxPages == 0 || number <= f.MaxPages; number++ {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		p, err := fetch(ctx, number)
+		if err != nil {
+			return nil, err
+		}
+		pages = append(pages, p.results)
+		if p.last(number, f.perPage()) {
+			break
+		}
+	}
+	return pages, nil
+}
@@ -0,0 +1,165 @@
+package stream
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/eddogola/unsplash-go/unsplash/client"
+)
+
+// lister requests a page of a list endpoint, returning its results and their number
+type lister func(context.Context, client.QueryParams) (interface{}, int, error)
+
+// searcher requests a page of a search endpoint, returning its results, their number
+// and the number of pages
+type searcher func(context.Context, client.QueryParams) (interface{}, int, int, error)
+
+func photoList(c Client) lister {
+	return func(ctx context.Context, qp client.QueryParams) (interface{}, int, error) {
+		pics, err := c.GetPhotoList(ctx, qp)
+		return pics, len(pics), err
+	}
+}
+
+func collectionPhotos(c Client, id string) lister {
+	return func(ctx context.Context, qp client.QueryParams) (interface{}, int, error) {
+		pics, err := c.GetCollectionPhotos(ctx, id, qp)
+		return pics, len(pics), err
+	}
+}
+
+func topicPhotos(c Client, idOrSlug string) lister {
+	return func(ctx context.Context, qp client.QueryParams) (interface{}, int, error) {
+		pics, err := c.GetTopicPhotos(ctx, idOrSlug, qp)
+		return pics, len(pics), err
+	}
+}
+
+func userPhotos(c Client, username string) lister {
+	return func(ctx context.Context, qp client.QueryParams) (interface{}, int, error) {
+		pics, err := c.GetUserPhotos(ctx, username, qp)
+		return pics, len(pics), err
+	}
+}
+
+func userLikedPhotos(c Client, username string) lister {
+	return func(ctx context.Context, qp client.QueryParams) (interface{}, int, error) {
+		pics, err := c.GetUserLikedPhotos(ctx, username, qp)
+		return pics, len(pics), err
+	}
+}
+
+func collectionList(c Client) lister {
+	return func(ctx context.Context, qp client.QueryParams) (interface{}, int, error) {
+		collections, err := c.GetCollectionsList(ctx, qp)
+		return collections, len(collections), err
+	}
+}
+
+func userCollections(c Client, username string) lister {
+	return func(ctx context.Context, qp client.QueryParams) (interface{}, int, error) {
+		collections, err := c.GetUserCollections(ctx, username, qp)
+		return collections, len(collections), err
+	}
+}
+
+func photoSearch(c Client) searcher {
+	return func(ctx context.Context, qp client.QueryParams) (interface{}, int, int, error) {
+		res, err := c.SearchPhotos(ctx, qp)
+		if err != nil {
+			return nil, 0, 0, err
+		}
+		return res.Results, len(res.Results), res.TotalPages, nil
+	}
+}
+
+func collectionSearch(c Client) searcher {
+	return func(ctx context.Context, qp client.QueryParams) (interface{}, int, int, error) {
+		res, err := c.SearchCollections(ctx, qp)
+		if err != nil {
+			return nil, 0, 0, err
+		}
+		return res.Results, len(res.Results), res.TotalPages, nil
+	}
+}
+
+func userSearch(c Client) searcher {
+	return func(ctx context.Context, qp client.QueryParams) (interface{}, int, int, error) {
+		res, err := c.SearchUsers(ctx, qp)
+		if err != nil {
+			return nil, 0, 0, err
+		}
+		return res.Results, len(res.Results), res.TotalPages, nil
+	}
+}
+
+// page defines a page of results
+type page struct {
+	results    interface{} // a slice of photos, collections or users
+	n          int         // number of results
+	totalPages int         // 0 if the endpoint doesn't report it
+}
+
+// last reports whether the page numbered number is the last one
+func (p page) last(number, perPage int) bool {
+	if p.n == 0 {
+		return true
+	}
+	if p.totalPages > 0 {
+		return number >= p.totalPages
+	}
+	return p.n < perPage
+}
+
+// fetchFunc requests the page numbered number
+type fetchFunc func(ctx context.Context, number int) (page, error)
+
+// list returns a fetchFunc for a list endpoint, which reports the number of results
+// in the `X-Total` header
+func list(get lister, queryParams client.QueryParams, perPage int) fetchFunc {
+	return func(ctx context.Context, number int) (page, error) {
+		total := -1
+		results, n, err := get(client.WithTotal(ctx, &total), params(queryParams, nil, number, perPage))
+		if err != nil {
+			return page{}, err
+		}
+		p := page{results: results, n: n}
+		if total >= 0 {
+			p.totalPages = (total + perPage - 1) / perPage
+		}
+		return p, nil
+	}
+}
+
+// search returns a fetchFunc for a search endpoint
+func search(get searcher, searchQuery string, queryParams client.QueryParams, perPage int) fetchFunc {
+	return func(ctx context.Context, number int) (page, error) {
+		results, n, totalPages, err := get(ctx, params(queryParams, &searchQuery, number, perPage))
+		if err != nil {
+			return page{}, err
+		}
+		return page{results, n, totalPages}, nil
+	}
+}
+
+// params copies queryParams, setting the page and, for searches, the query
+func params(queryParams client.QueryParams, searchQuery *string, number, perPage int) client.QueryParams {
+	qp := make(client.QueryParams, len(queryParams)+3)
+	for k, v := range queryParams {
+		qp[k] = v
+	}
+	if searchQuery != nil {
+		qp["query"] = *searchQuery
+	}
+	qp["page"] = strconv.Itoa(number)
+	qp["per_page"] = strconv.Itoa(perPage)
+	return qp
+}
+
+// validPerPage returns n, or DefaultPerPage if n isn't a number of results the API allows per page
+func validPerPage(n int) int {
+	if n < 1 || n > DefaultPerPage {
+		return DefaultPerPage
+	}
+	return n
+}
@@ -0,0 +1,224 @@
+// Package stream streams every result of the API's list and search endpoints over channels,
+// for jobs processing more results than fit comfortably in memory.
+//
+// Each stream returns a channel of results and a channel of errors. Pages are requested in the
+// background, the next one while the results of the current one are consumed, and requests wait
+// for the consumer to catch up. The results channel is closed once every page has been streamed,
+// a request fails or the context is cancelled; the errors channel then receives the error, if any,
+// and is closed:
+//
+//	pics, errs := stream.New(c).TopicPhotos(ctx, "nature", nil)
+//	for pic := range pics {
+//		// process pic
+//	}
+//	if err := <-errs; err != nil {
+//		// handle the error
+//	}
+//
+// A consumer that stops reading before the results channel is closed must cancel the context,
+// so that the stream's goroutines exit.
+//
+// A Fetcher returns every result at once instead, requesting pages concurrently.
+package stream
+
+import (
+	"context"
+
+	"github.com/eddogola/unsplash-go/unsplash/client"
+)
+
+// DefaultPerPage is the number of results requested per page, the most the API allows
+const DefaultPerPage = 30
+
+// Client defines client methods used to list and search resources
+type Client interface {
+	GetPhotoList(context.Context, client.QueryParams) ([]client.Photo, error)
+	GetCollectionsList(context.Context, client.QueryParams) ([]client.Collection, error)
+	GetCollectionPhotos(context.Context, string, client.QueryParams) ([]client.Photo, error)
+	GetTopicPhotos(context.Context, string, client.QueryParams) ([]client.Photo, error)
+	GetUserPhotos(context.Context, string, client.QueryParams) ([]client.Photo, error)
+	GetUserLikedPhotos(context.Context, string, client.QueryParams) ([]client.Photo, error)
+	GetUserCollections(context.Context, string, client.QueryParams) ([]client.Collection, error)
+	SearchPhotos(context.Context, client.QueryParams) (*client.PhotoSearchResult, error)
+	SearchCollections(context.Context, client.QueryParams) (*client.CollectionSearchResult, error)
+	SearchUsers(context.Context, client.QueryParams) (*client.UserSearchResult, error)
+}
+
+// Streamer streams results using a Client
+type Streamer struct {
+	client Client
+	// PerPage is the number of results requested per page, from 1 to 30
+	PerPage int
+	// Prefetch is the number of pages requested ahead of the page being consumed, at least 1
+	Prefetch int
+	// MaxPages bounds the number of pages requested, unless it's 0
+	MaxPages int
+}
+
+// New constructs a Streamer requesting pages of DefaultPerPage results, one page ahead
+func New(c Client) *Streamer {
+	return &Streamer{client: c, PerPage: DefaultPerPage, Prefetch: 1}
+}
+
+// Photos streams the editorial feed of photos. queryParams, e.g. `order_by`, are passed
+// to each request; `page` and `per_page` are set by the Streamer.
+func (s *Streamer) Photos(ctx context.Context, queryParams client.QueryParams) (<-chan client.Photo, <-chan error) {
+	return s.photos(ctx, list(photoList(s.client), queryParams, s.perPage()))
+}
+
+// CollectionPhotos streams a collection's photos
+func (s *Streamer) CollectionPhotos(ctx context.Context, id string, queryParams client.QueryParams) (<-chan client.Photo, <-chan error) {
+	return s.photos(ctx, list(collectionPhotos(s.client, id), queryParams, s.perPage()))
+}
+
+// TopicPhotos streams a topic's photos
+func (s *Streamer) TopicPhotos(ctx context.Context, idOrSlug string, queryParams client.QueryParams) (<-chan client.Photo, <-chan error) {
+	return s.photos(ctx, list(topicPhotos(s.client, idOrSlug), queryParams, s.perPage()))
+}
+
+// UserPhotos streams the photos uploaded by a user
+func (s *Streamer) UserPhotos(ctx context.Context, username string, queryParams client.QueryParams) (<-chan client.Photo, <-chan error) {
+	return s.photos(ctx, list(userPhotos(s.client, username), queryParams, s.perPage()))
+}
+
+// UserLikedPhotos streams the photos liked by a user
+func (s *Streamer) UserLikedPhotos(ctx context.Context, username string, queryParams client.QueryParams) (<-chan client.Photo, <-chan error) {
+	return s.photos(ctx, list(userLikedPhotos(s.client, username), queryParams, s.perPage()))
+}
+
+// SearchPhotos streams every result of a photo search
+func (s *Streamer) SearchPhotos(ctx context.Context, searchQuery string, queryParams client.QueryParams) (<-chan client.Photo, <-chan error) {
+	return s.photos(ctx, search(photoSearch(s.client), searchQuery, queryParams, s.perPage()))
+}
+
+// Collections streams every collection
+func (s *Streamer) Collections(ctx context.Context, queryParams client.QueryParams) (<-chan client.Collection, <-chan error) {
+	return s.collections(ctx, list(collectionList(s.client), queryParams, s.perPage()))
+}
+
+// UserCollections streams the collections created by a user
+func (s *Streamer) UserCollections(ctx context.Context, username string, queryParams client.QueryParams) (<-chan client.Collection, <-chan error) {
+	return s.collections(ctx, list(userCollections(s.client, username), queryParams, s.perPage()))
+}
+
+// SearchCollections streams every result of a collection search
+func (s *Streamer) SearchCollections(ctx context.Context, searchQuery string, queryParams client.QueryParams) (<-chan client.Collection, <-chan error) {
+	return s.collections(ctx, search(collectionSearch(s.client), searchQuery, queryParams, s.perPage()))
+}
+
+// SearchUsers streams every result of a user search
+func (s *Streamer) SearchUsers(ctx context.Context, searchQuery string, queryParams client.QueryParams) (<-chan client.User, <-chan error) {
+	return s.users(ctx, search(userSearch(s.client), searchQuery, queryParams, s.perPage()))
+}
+
+func (s *Streamer) photos(ctx context.Context, p fetchFunc) (<-chan client.Photo, <-chan error) {
+	out := make(chan client.Photo)
+	errc := make(chan error, 1)
+	go s.forward(ctx, p, errc, func() { close(out) }, func(page interface{}) bool {
+		for _, pic := range page.([]client.Photo) {
+			select {
+			case out <- pic:
+			case <-ctx.Done():
+				return false
+			}
+		}
+		return true
+	})
+	return out, errc
+}
+
+func (s *Streamer) collections(ctx context.Context, p fetchFunc) (<-chan client.Collection, <-chan error) {
+	out := make(chan client.Collection)
+	errc := make(chan error, 1)
+	go s.forward(ctx, p, errc, func() { close(out) }, func(page interface{}) bool {
+		for _, c := range page.([]client.Collection) {
+			select {
+			case out <- c:
+			case <-ctx.Done():
+				return false
+			}
+		}
+		return true
+	})
+	return out, errc
+}
+
+func (s *Streamer) users(ctx context.Context, p fetchFunc) (<-chan client.User, <-chan error) {
+	out := make(chan client.User)
+	errc := make(chan error, 1)
+	go s.forward(ctx, p, errc, func() { close(out) }, func(page interface{}) bool {
+		for _, u := range page.([]client.User) {
+			select {
+			case out <- u:
+			case <-ctx.Done():
+				return false
+			}
+		}
+		return true
+	})
+	return out, errc
+}
+
+func (s *Streamer) perPage() int {
+	return validPerPage(s.PerPage)
+}
+
+// pages requests pages in the background, sending them on the returned channel.
+// It stops after the last page, on error, or once ctx is done, then closes the channel;
+// *err is set before the channel is closed.
+func (s *Streamer) pages(ctx context.Context, fetch fetchFunc, err *error) <-chan interface{} {
+	// the page being sent waits for the consumer, so the buffer holds the pages beyond it
+	buffer := s.Prefetch - 1
+	if buffer < 0 {
+		buffer = 0
+	}
+	pages := make(chan interface{}, buffer)
+	go func() {
+		defer close(pages)
+		for number := 1; s.MaxPages == 0 || number <= s.MaxPages; number++ {
+			if *err = ctx.Err(); *err != nil {
+				return
+			}
+			p, fetchErr := fetch(ctx, number)
+			if fetchErr != nil {
+				*err = fetchErr
+				return
+			}
+			select {
+			case pages <- p.results:
+			case <-ctx.Done():
+				*err = ctx.Err()
+				return
+			}
+			if p.last(number, s.perPage()) {
+				return
+			}
+		}
+	}()
+	return pages
+}
+
+// forward calls send with each page, then calls closeOut and reports the stream's error on errc.
+// send returns false if ctx is done before the page's results were all sent.
+func (s *Streamer) forward(ctx context.Context, fetch fetchFunc, errc chan<- error, closeOut func(), send func(page interface{}) bool) {
+	var err error
+	pages := s.pages(ctx, fetch, &err)
+	stopped := false
+	for page := range pages {
+		if !send(page) {
+			stopped = true
+			break
+		}
+	}
+	// wait for the page requests to stop, which they do once ctx is done
+	for range pages {
+	}
+	closeOut()
+	if err == nil && stopped {
+		err = ctx.Err()
+	}
+	if err != nil {
+		errc <- err
+	}
+	close(errc)
+}
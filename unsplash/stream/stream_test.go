@@ -0,0 +1,350 @@
+package stream
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/eddogola/unsplash-go/unsplash/client"
+)
+
+// fakeClient serves total results, counting the requests made, and failing the page failPage.
+// Pages after the first are shifted back by shift results, as if results were added since.
+type fakeClient struct {
+	Client
+	total    int
+	failPage int
+	shift    int
+	delay    time.Duration
+
+	mu          sync.Mutex
+	requests    int
+	params      []client.QueryParams
+	inFlight    int
+	maxInFlight int
+}
+
+func (f *fakeClient) page(qp client.QueryParams) ([]client.Photo, error) {
+	f.mu.Lock()
+	f.requests++
+	f.params = append(f.params, qp)
+	f.inFlight++
+	if f.inFlight > f.maxInFlight {
+		f.maxInFlight = f.inFlight
+	}
+	f.mu.Unlock()
+	time.Sleep(f.delay)
+	f.mu.Lock()
+	f.inFlight--
+	f.mu.Unlock()
+
+	page, _ := strconv.Atoi(qp["page"])
+	perPage, _ := strconv.Atoi(qp["per_page"])
+	if page == f.failPage {
+		return nil, errors.New("server error")
+	}
+	start := (page - 1) * perPage
+	if page > 1 {
+		start -= f.shift
+	}
+	var pics []client.Photo
+	for i := start; i < start+perPage && i < f.total; i++ {
+		pics = append(pics, client.Photo{ID: fmt.Sprint(i)})
+	}
+	return pics, nil
+}
+
+func (f *fakeClient) count() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.requests
+}
+
+func (f *fakeClient) GetTopicPhotos(ctx context.Context, idOrSlug string, qp client.QueryParams) ([]client.Photo, error) {
+	return f.page(qp)
+}
+
+func (f *fakeClient) SearchPhotos(ctx context.Context, qp client.QueryParams) (*client.PhotoSearchResult, error) {
+	pics, err := f.page(qp)
+	if err != nil {
+		return nil, err
+	}
+	perPage, _ := strconv.Atoi(qp["per_page"])
+	return &client.PhotoSearchResult{Total: f.total, TotalPages: (f.total + perPage - 1) / perPage, Results: pics}, nil
+}
+
+func (f *fakeClient) SearchUsers(ctx context.Context, qp client.QueryParams) (*client.UserSearchResult, error) {
+	pics, err := f.page(qp)
+	if err != nil {
+		return nil, err
+	}
+	res := &client.UserSearchResult{Total: f.total, TotalPages: 1}
+	for _, pic := range pics {
+		res.Results = append(res.Results, client.User{ID: pic.ID})
+	}
+	return res, nil
+}
+
+func collect(pics <-chan client.Photo, errs <-chan error) ([]client.Photo, error) {
+	var res []client.Photo
+	for pic := range pics {
+		res = append(res, pic)
+	}
+	return res, <-errs
+}
+
+func TestStream(t *testing.T) {
+	t.Run("every page is streamed in order", func(t *testing.T) {
+		f := &fakeClient{total: 25}
+		s := New(f)
+		s.PerPage = 10
+		pics, err := collect(s.TopicPhotos(context.Background(), "nature", client.QueryParams{"orientation": "portrait", "page": "7"}))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(pics) != 25 {
+			t.Fatalf("expected 25 photos but got %d", len(pics))
+		}
+		for i, pic := range pics {
+			if pic.ID != fmt.Sprint(i) {
+				t.Fatalf("expected photo %d but got %s", i, pic.ID)
+			}
+		}
+		if f.count() != 3 {
+			t.Errorf("expected 3 requests but got %d", f.count())
+		}
+		for i, qp := range f.params {
+			if qp["orientation"] != "portrait" || qp["page"] != fmt.Sprint(i+1) || qp["per_page"] != "10" {
+				t.Errorf("unexpected query parameters %v", qp)
+			}
+		}
+	})
+
+	t.Run("search results end on the last page", func(t *testing.T) {
+		f := &fakeClient{total: 60}
+		pics, err := collect(New(f).SearchPhotos(context.Background(), "forest", nil))
+		if err != nil || len(pics) != 60 {
+			t.Fatalf("expected 60 photos but got %d, %v", len(pics), err)
+		}
+		// a full last page doesn't need an empty page to be requested
+		if f.count() != 2 {
+			t.Errorf("expected 2 requests but got %d", f.count())
+		}
+		if f.params[0]["query"] != "forest" {
+			t.Errorf("expected the search query to be set but got %v", f.params[0])
+		}
+
+		users, errs := New(&fakeClient{total: 3}).SearchUsers(context.Background(), "jane", nil)
+		n := 0
+		for range users {
+			n++
+		}
+		if err := <-errs; err != nil || n != 3 {
+			t.Errorf("expected 3 users but got %d, %v", n, err)
+		}
+	})
+
+	t.Run("max pages", func(t *testing.T) {
+		f := &fakeClient{total: 100}
+		s := New(f)
+		s.PerPage, s.MaxPages = 10, 2
+		pics, err := collect(s.TopicPhotos(context.Background(), "nature", nil))
+		if err != nil || len(pics) != 20 {
+			t.Errorf("expected 20 photos but got %d, %v", len(pics), err)
+		}
+	})
+
+	t.Run("the next page is prefetched, and no more", func(t *testing.T) {
+		f := &fakeClient{total: 100}
+		s := New(f)
+		s.PerPage = 10
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		pics, errs := s.TopicPhotos(ctx, "nature", nil)
+		<-pics
+		deadline := time.Now().Add(time.Second)
+		for f.count() < 2 && time.Now().Before(deadline) {
+			time.Sleep(time.Millisecond)
+		}
+		time.Sleep(20 * time.Millisecond)
+		if f.count() != 2 {
+			t.Errorf("expected the next page to be prefetched while the consumer waits, got %d requests", f.count())
+		}
+		cancel()
+		for range pics {
+		}
+		if err := <-errs; err != context.Canceled {
+			t.Errorf("expected context.Canceled but got %v", err)
+		}
+	})
+
+	t.Run("errors end the stream", func(t *testing.T) {
+		f := &fakeClient{total: 100, failPage: 3}
+		s := New(f)
+		s.PerPage = 10
+		pics, err := collect(s.TopicPhotos(context.Background(), "nature", nil))
+		if err == nil || err.Error() != "server error" {
+			t.Errorf("expected the request's error but got %v", err)
+		}
+		if len(pics) != 20 {
+			t.Errorf("expected the pages before the error but got %d photos", len(pics))
+		}
+		if f.count() != 3 {
+			t.Errorf("expected no requests after the error but got %d", f.count())
+		}
+	})
+
+	t.Run("cancelling stops the stream", func(t *testing.T) {
+		f := &fakeClient{total: 1000}
+		ctx, cancel := context.WithCancel(context.Background())
+		pics, errs := New(f).TopicPhotos(ctx, "nature", nil)
+		for i := 0; i < 45; i++ {
+			<-pics
+		}
+		cancel()
+		rest := 0
+		for range pics {
+			rest++
+		}
+		if err := <-errs; err != context.Canceled {
+			t.Errorf("expected context.Canceled but got %v", err)
+		}
+		if _, ok := <-errs; ok {
+			t.Error("expected the errors channel to be closed")
+		}
+		if n := f.count(); n > 3 {
+			t.Errorf("expected requests to stop but got %d", n)
+		}
+	})
+}
+
+// redirectTransport sends every request to the test server
+type redirectTransport struct {
+	target *url.URL
+}
+
+func (t redirectTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	req.URL.Scheme = t.target.Scheme
+	req.URL.Host = t.target.Host
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+func TestFetcher(t *testing.T) {
+	t.Run("pages after the first are requested concurrently", func(t *testing.T) {
+		f := &fakeClient{total: 95, delay: 20 * time.Millisecond}
+		fetcher := NewFetcher(f)
+		fetcher.PerPage, fetcher.Concurrency = 10, 3
+		pics, err := fetcher.SearchPhotos(context.Background(), "forest", client.QueryParams{"color": "green"})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(pics) != 95 {
+			t.Fatalf("expected 95 photos but got %d", len(pics))
+		}
+		for i, pic := range pics {
+			if pic.ID != fmt.Sprint(i) {
+				t.Fatalf("expected photos in order, got %s at %d", pic.ID, i)
+			}
+		}
+		if f.count() != 10 {
+			t.Errorf("expected 10 requests but got %d", f.count())
+		}
+		if f.maxInFlight != 3 {
+			t.Errorf("expected 3 requests at once but got %d", f.maxInFlight)
+		}
+		for _, qp := range f.params {
+			if qp["query"] != "forest" || qp["color"] != "green" {
+				t.Errorf("unexpected query parameters %v", qp)
+			}
+		}
+	})
+
+	t.Run("results shifting between pages are returned once", func(t *testing.T) {
+		f := &fakeClient{total: 50, shift: 2}
+		fetcher := NewFetcher(f)
+		fetcher.PerPage = 10
+		pics, err := fetcher.SearchPhotos(context.Background(), "forest", nil)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(pics) != 48 {
+			t.Fatalf("expected 48 photos but got %d", len(pics))
+		}
+		for i, pic := range pics {
+			if pic.ID != fmt.Sprint(i) {
+				t.Fatalf("expected photos in order, got %s at %d", pic.ID, i)
+			}
+		}
+	})
+
+	t.Run("budget and errors", func(t *testing.T) {
+		f := &fakeClient{total: 1000}
+		fetcher := NewFetcher(f)
+		fetcher.PerPage, fetcher.MaxPages = 10, 5
+		pics, err := fetcher.SearchPhotos(context.Background(), "forest", nil)
+		if err != nil || len(pics) != 50 || f.count() != 5 {
+			t.Errorf("expected 50 photos in 5 requests but got %d in %d, %v", len(pics), f.count(), err)
+		}
+
+		f = &fakeClient{total: 1000, failPage: 4}
+		fetcher = NewFetcher(f)
+		fetcher.Concurrency = 1
+		if _, err := fetcher.SearchPhotos(context.Background(), "forest", nil); err == nil || err.Error() != "server error" {
+			t.Errorf("expected the request's error but got %v", err)
+		}
+		if f.count() != 4 {
+			t.Errorf("expected requests to stop after the error but got %d", f.count())
+		}
+	})
+
+	t.Run("pages of endpoints without totals are requested one at a time", func(t *testing.T) {
+		f := &fakeClient{total: 45, delay: 5 * time.Millisecond}
+		fetcher := NewFetcher(f)
+		fetcher.PerPage = 10
+		pics, err := fetcher.TopicPhotos(context.Background(), "nature", nil)
+		if err != nil || len(pics) != 45 {
+			t.Fatalf("expected 45 photos but got %d, %v", len(pics), err)
+		}
+		if f.maxInFlight != 1 || f.count() != 5 {
+			t.Errorf("expected 5 sequential requests but got %d, %d at once", f.count(), f.maxInFlight)
+		}
+	})
+
+	t.Run("list endpoints report totals in X-Total", func(t *testing.T) {
+		var mu sync.Mutex
+		requests := 0
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			mu.Lock()
+			requests++
+			mu.Unlock()
+			page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+			var pics []client.Photo
+			for i := (page - 1) * 30; i < page*30 && i < 90; i++ {
+				pics = append(pics, client.Photo{ID: fmt.Sprint(i)})
+			}
+			w.Header().Set("X-Total", "90")
+			json.NewEncoder(w).Encode(pics)
+		}))
+		defer ts.Close()
+		target, _ := url.Parse(ts.URL)
+		c := client.New("id", &http.Client{Transport: redirectTransport{target}}, client.NewConfig())
+
+		pics, err := NewFetcher(c).UserPhotos(context.Background(), "jane", nil)
+		if err != nil || len(pics) != 90 {
+			t.Fatalf("expected 90 photos but got %d, %v", len(pics), err)
+		}
+		// the last page is full, but the total tells it's the last
+		if requests != 3 {
+			t.Errorf("expected 3 requests but got %d", requests)
+		}
+	})
+}
@@ -0,0 +1,117 @@
+package tui
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// Run runs the browser in the terminal until the user quits.
+// The terminal is switched to raw mode using `stty`, so Run needs a Unix terminal.
+func (b *Browser) Run() error {
+	tty, err := os.OpenFile("/dev/tty", os.O_RDWR, 0)
+	if err != nil {
+		return fmt.Errorf("the browser needs an interactive terminal: %v", err)
+	}
+	defer tty.Close()
+
+	state, err := stty(tty, "-g")
+	if err != nil {
+		return fmt.Errorf("cannot read the terminal's state: %v", err)
+	}
+	if _, err := stty(tty, "raw", "-echo"); err != nil {
+		return fmt.Errorf("cannot switch the terminal to raw mode: %v", err)
+	}
+	defer stty(tty, strings.TrimSpace(state))
+
+	// switch to the alternate screen, hiding the cursor
+	fmt.Fprint(tty, "\x1b[?1049h\x1b[?25l")
+	defer fmt.Fprint(tty, "\x1b[?25h\x1b[?1049l")
+
+	draw := func() {
+		width, height := terminalSize(tty)
+		view := strings.Replace(b.View(width, height), "\n", "\x1b[K\r\n", -1)
+		fmt.Fprint(tty, "\x1b[H"+view+"\x1b[K\x1b[J")
+	}
+	b.redraw = draw
+	defer func() { b.redraw = nil }()
+
+	keys := bufio.NewReader(tty)
+	for !b.quit {
+		draw()
+		k, err := ReadKey(keys)
+		if err != nil {
+			return err
+		}
+		b.Handle(k)
+	}
+	return nil
+}
+
+// ReadKey reads a key press from a terminal in raw mode
+func ReadKey(r *bufio.Reader) (Key, error) {
+	c, _, err := r.ReadRune()
+	if err != nil {
+		return "", err
+	}
+	switch c {
+	case '\r', '\n':
+		return KeyEnter, nil
+	case 127, '\b':
+		return KeyBackspace, nil
+	case 3:
+		return KeyCtrlC, nil
+	case '\x1b':
+		// arrow keys are sent as escape sequences, e.g. "\x1b[A"; a lone escape is the escape key
+		if r.Buffered() == 0 {
+			return KeyEsc, nil
+		}
+		if next, _ := r.Peek(1); next[0] != '[' && next[0] != 'O' {
+			return KeyEsc, nil
+		}
+		r.ReadByte()
+		code, err := r.ReadByte()
+		if err != nil {
+			return "", err
+		}
+		switch code {
+		case 'A':
+			return KeyUp, nil
+		case 'B':
+			return KeyDown, nil
+		case 'C':
+			return KeyRight, nil
+		case 'D':
+			return KeyLeft, nil
+		}
+		// skip the rest of sequences not handled, e.g. "\x1b[5~"
+		for code >= '0' && code <= '9' || code == ';' {
+			if code, err = r.ReadByte(); err != nil {
+				return "", err
+			}
+		}
+		return "", nil
+	}
+	return Key(string(c)), nil
+}
+
+// stty runs `stty` with args on the terminal, returning its output
+func stty(tty *os.File, args ...string) (string, error) {
+	cmd := exec.Command("stty", args...)
+	cmd.Stdin = tty
+	out, err := cmd.Output()
+	return string(out), err
+}
+
+// terminalSize returns the terminal's width and height, defaulting to 80 by 24
+func terminalSize(tty *os.File) (width, height int) {
+	out, err := stty(tty, "size")
+	if err == nil {
+		if _, err := fmt.Sscan(out, &height, &width); err == nil && width > 0 && height > 0 {
+			return width, height
+		}
+	}
+	return 80, 24
+}
@@ -0,0 +1,449 @@
+// Package tui is an interactive terminal browser for Unsplash photos, collections and topics,
+// built on the services of package unsplash.
+//
+// Results are listed a page at a time, with keyboard navigation between pages and into
+// collections and topics. A detail pane shows the selected photo's EXIF data, location and stats.
+// When the Browser is created with a private client, photos can also be liked and added to
+// collections. Pages and photo details are cached, so going back does not refetch them.
+package tui
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/eddogola/unsplash-go/unsplash"
+	"github.com/eddogola/unsplash-go/unsplash/client"
+)
+
+// Key defines a key press, either a named key or the character typed
+type Key string
+
+// Named keys
+const (
+	KeyUp        Key = "up"
+	KeyDown      Key = "down"
+	KeyLeft      Key = "left"
+	KeyRight     Key = "right"
+	KeyEnter     Key = "enter"
+	KeyEsc       Key = "esc"
+	KeyBackspace Key = "backspace"
+	KeyCtrlC     Key = "ctrl+c"
+)
+
+// helpText lists the keys handled by the Browser
+const helpText = "↑/↓ move  ←/→ page  enter open  esc back  / search  t topics  c collections  " +
+	"L like  a add to collection  d download  q quit"
+
+// Browser holds the state of the terminal browser
+type Browser struct {
+	u       *unsplash.Unsplash
+	private bool
+	// PerPage is the number of results requested per page
+	PerPage int
+	// DownloadDir is the directory photos are downloaded to
+	DownloadDir string
+
+	stack   []*list            // lists opened, the last one is shown
+	details map[string]*detail // cached photo details, by photo ID
+	shown   string             // ID of the photo shown in the detail pane
+	input   *input             // text being typed, nil if none
+	status  string
+	quit    bool
+	// redraw, if set, is called to show the status before slow requests
+	redraw func()
+}
+
+// list defines a paginated list of results
+type list struct {
+	title string
+	// fetch requests a page of items, returning them along with the total number of pages, 0 if unknown
+	fetch  func(qp client.QueryParams) ([]item, int, error)
+	pages  map[int][]item
+	page   int
+	total  int
+	cursor int
+}
+
+// item defines a listed photo, collection or topic
+type item struct {
+	photo      *client.Photo
+	collection *client.Collection
+	topic      *client.Topic
+}
+
+// detail holds the full details of a photo, shown in the detail pane
+type detail struct {
+	photo *client.Photo
+	stats *client.PhotoStats
+}
+
+// input defines a text prompt, calling done with the text entered
+type input struct {
+	prompt string
+	text   []rune
+	done   func(string)
+}
+
+// New constructs a Browser using u. private should be true if u's client is authorised for
+// private actions, enabling likes and adding photos to collections.
+func New(u *unsplash.Unsplash, private bool) *Browser {
+	return &Browser{
+		u:           u,
+		private:     private,
+		PerPage:     20,
+		DownloadDir: ".",
+		details:     make(map[string]*detail),
+		status:      "? for help",
+	}
+}
+
+// Quit reports whether the user asked to quit
+func (b *Browser) Quit() bool {
+	return b.quit
+}
+
+// Editorial opens the list of photos in the editorial feed
+func (b *Browser) Editorial() error {
+	return b.push("Editorial", func(qp client.QueryParams) ([]item, int, error) {
+		pics, err := b.u.Photos.All(qp)
+		return photoItems(pics), 0, err
+	})
+}
+
+// Search opens the list of photos matching query
+func (b *Browser) Search(query string) error {
+	return b.push("Search “"+query+"”", func(qp client.QueryParams) ([]item, int, error) {
+		res, err := b.u.Photos.Search(query, qp)
+		if err != nil {
+			return nil, 0, err
+		}
+		return photoItems(res.Results), res.TotalPages, nil
+	})
+}
+
+// Topics opens the list of topics
+func (b *Browser) Topics() error {
+	return b.push("Topics", func(qp client.QueryParams) ([]item, int, error) {
+		topics, err := b.u.Topics.All(qp)
+		items := make([]item, len(topics))
+		for i := range topics {
+			items[i] = item{topic: &topics[i]}
+		}
+		return items, 0, err
+	})
+}
+
+// Collections opens the list of all collections
+func (b *Browser) Collections() error {
+	return b.push("Collections", func(qp client.QueryParams) ([]item, int, error) {
+		collections, err := b.u.Collections.All(qp)
+		items := make([]item, len(collections))
+		for i := range collections {
+			items[i] = item{collection: &collections[i]}
+		}
+		return items, 0, err
+	})
+}
+
+func photoItems(pics []client.Photo) []item {
+	items := make([]item, len(pics))
+	for i := range pics {
+		items[i] = item{photo: &pics[i]}
+	}
+	return items
+}
+
+// push loads the first page of a new list, showing it if it loads
+func (b *Browser) push(title string, fetch func(client.QueryParams) ([]item, int, error)) error {
+	l := &list{title: title, fetch: fetch, pages: make(map[int][]item)}
+	if err := b.load(l, 1); err != nil {
+		return err
+	}
+	b.stack = append(b.stack, l)
+	return nil
+}
+
+// load shows the given page of l, fetching it unless it's cached
+func (b *Browser) load(l *list, page int) error {
+	if _, ok := l.pages[page]; !ok {
+		b.setStatus(fmt.Sprintf("Loading %s, page %d…", l.title, page))
+		items, total, err := l.fetch(client.QueryParams{
+			"page":     fmt.Sprint(page),
+			"per_page": fmt.Sprint(b.PerPage),
+		})
+		if err != nil {
+			b.status = "Error: " + err.Error()
+			return err
+		}
+		l.pages[page] = items
+		if total > 0 {
+			l.total = total
+		}
+	}
+	l.page, l.cursor = page, 0
+	b.status = ""
+	return nil
+}
+
+func (b *Browser) setStatus(status string) {
+	b.status = status
+	if b.redraw != nil {
+		b.redraw()
+	}
+}
+
+// current returns the list shown, nil if none
+func (b *Browser) current() *list {
+	if len(b.stack) == 0 {
+		return nil
+	}
+	return b.stack[len(b.stack)-1]
+}
+
+// selected returns the item under the cursor, nil if none
+func (b *Browser) selected() *item {
+	l := b.current()
+	if l == nil || l.cursor >= len(l.pages[l.page]) {
+		return nil
+	}
+	return &l.pages[l.page][l.cursor]
+}
+
+// hasNext reports whether there may be a page after the current one
+func (l *list) hasNext(perPage int) bool {
+	if l.total > 0 {
+		return l.page < l.total
+	}
+	return len(l.pages[l.page]) >= perPage
+}
+
+// Handle updates the Browser in response to a key press
+func (b *Browser) Handle(k Key) {
+	if b.input != nil {
+		b.handleInput(k)
+		return
+	}
+	l := b.current()
+	switch k {
+	case "q", KeyCtrlC:
+		b.quit = true
+	case "?":
+		b.status = helpText
+	case KeyUp, "k":
+		if l != nil && l.cursor > 0 {
+			l.cursor--
+		}
+	case KeyDown, "j":
+		if l != nil && l.cursor < len(l.pages[l.page])-1 {
+			l.cursor++
+		}
+	case KeyRight, "n", "l":
+		if l == nil {
+			return
+		}
+		if !l.hasNext(b.PerPage) {
+			b.status = "Last page"
+			return
+		}
+		b.load(l, l.page+1)
+	case KeyLeft, "p", "h":
+		if l == nil {
+			return
+		}
+		if l.page <= 1 {
+			b.status = "First page"
+			return
+		}
+		b.load(l, l.page-1)
+	case KeyEnter:
+		b.open()
+	case KeyEsc, KeyBackspace:
+		if len(b.stack) > 1 {
+			b.stack = b.stack[:len(b.stack)-1]
+			b.status = ""
+		}
+	case "/":
+		b.prompt("Search photos: ", func(query string) {
+			if query != "" {
+				b.Search(query)
+			}
+		})
+	case "t":
+		b.Topics()
+	case "c":
+		b.Collections()
+	case "L":
+		b.toggleLike()
+	case "a":
+		b.addToCollection()
+	case "d":
+		b.download()
+	}
+}
+
+func (b *Browser) prompt(prompt string, done func(string)) {
+	b.input = &input{prompt: prompt, done: done}
+}
+
+func (b *Browser) handleInput(k Key) {
+	in := b.input
+	switch k {
+	case KeyEnter:
+		b.input = nil
+		in.done(strings.TrimSpace(string(in.text)))
+	case KeyEsc, KeyCtrlC:
+		b.input = nil
+	case KeyBackspace:
+		if len(in.text) > 0 {
+			in.text = in.text[:len(in.text)-1]
+		}
+	default:
+		if r := []rune(string(k)); len(r) == 1 {
+			in.text = append(in.text, r[0])
+		}
+	}
+}
+
+// open shows the selected photo's details, or opens the photos of the selected collection or topic
+func (b *Browser) open() {
+	it := b.selected()
+	switch {
+	case it == nil:
+	case it.photo != nil:
+		b.showDetail(it.photo)
+	case it.collection != nil:
+		c := it.collection
+		b.push(c.Title, func(qp client.QueryParams) ([]item, int, error) {
+			pics, err := b.u.Collections.Photos(c.ID, qp)
+			return photoItems(pics), 0, err
+		})
+	case it.topic != nil:
+		t := it.topic
+		b.push(t.Title, func(qp client.QueryParams) ([]item, int, error) {
+			pics, err := b.u.Topics.Photos(t.Slug, qp)
+			return photoItems(pics), 0, err
+		})
+	}
+}
+
+// showDetail shows pic in the detail pane, fetching its full details and stats unless cached.
+// Listed photos do not include EXIF data or locations, so the photo is requested again.
+func (b *Browser) showDetail(pic *client.Photo) {
+	if _, ok := b.details[pic.ID]; !ok {
+		b.setStatus("Loading photo " + pic.ID + "…")
+		full, err := b.u.Photos.Get(pic.ID)
+		if err != nil {
+			b.status = "Error: " + err.Error()
+			return
+		}
+		d := &detail{photo: full}
+		// stats are optional, the pane is shown without them if they cannot be fetched
+		if stats, err := b.u.Photos.Stats(pic.ID, nil); err == nil {
+			d.stats = stats
+		}
+		b.details[pic.ID] = d
+	}
+	b.shown = pic.ID
+	b.status = ""
+}
+
+// selectedPhoto returns the selected photo, setting the status if no photo is selected
+func (b *Browser) selectedPhoto() *client.Photo {
+	it := b.selected()
+	if it == nil || it.photo == nil {
+		b.status = "Select a photo first"
+		return nil
+	}
+	return it.photo
+}
+
+func (b *Browser) requirePrivate(action string) bool {
+	if !b.private {
+		b.status = "Log in with `unsplash auth login` to " + action
+	}
+	return b.private
+}
+
+// toggleLike likes the selected photo, or removes the like if the user already liked it
+func (b *Browser) toggleLike() {
+	pic := b.selectedPhoto()
+	if pic == nil || !b.requirePrivate("like photos") {
+		return
+	}
+	liked := pic.LikedByUser
+	var err error
+	if liked {
+		err = b.u.Photos.Unlike(pic.ID)
+	} else {
+		_, err = b.u.Photos.Like(pic.ID)
+	}
+	if err != nil {
+		b.status = "Error: " + err.Error()
+		return
+	}
+	b.updatePhoto(pic.ID, func(p *client.Photo) {
+		p.LikedByUser = !liked
+		if liked {
+			p.Likes--
+		} else {
+			p.Likes++
+		}
+	})
+	if liked {
+		b.status = "Removed like from " + pic.ID
+	} else {
+		b.status = "Liked " + pic.ID
+	}
+}
+
+// updatePhoto applies update to every cached copy of the photo with the given ID
+func (b *Browser) updatePhoto(id string, update func(*client.Photo)) {
+	for _, l := range b.stack {
+		for _, items := range l.pages {
+			for _, it := range items {
+				if it.photo != nil && it.photo.ID == id {
+					update(it.photo)
+				}
+			}
+		}
+	}
+	if d, ok := b.details[id]; ok {
+		update(d.photo)
+	}
+}
+
+// addToCollection prompts for a collection ID, then adds the selected photo to it
+func (b *Browser) addToCollection() {
+	pic := b.selectedPhoto()
+	if pic == nil || !b.requirePrivate("add photos to collections") {
+		return
+	}
+	b.prompt("Add "+pic.ID+" to collection ID: ", func(id string) {
+		if id == "" {
+			return
+		}
+		res, err := b.u.Collections.AddPhoto(id, map[string]string{"collection_id": id, "photo_id": pic.ID})
+		if err != nil {
+			b.status = "Error: " + err.Error()
+			return
+		}
+		b.status = fmt.Sprintf("Added %s to “%s”", pic.ID, res.Collection.Title)
+	})
+}
+
+// download saves the selected photo to DownloadDir, tracking the download
+func (b *Browser) download() {
+	pic := b.selectedPhoto()
+	if pic == nil {
+		return
+	}
+	path := filepath.Join(b.DownloadDir, pic.ID+".jpg")
+	b.setStatus("Downloading " + pic.ID + "…")
+	n, err := b.u.Photos.DownloadToFile(pic, path, nil, nil)
+	if err != nil {
+		b.status = "Error: " + err.Error()
+		return
+	}
+	b.status = fmt.Sprintf("Downloaded %s (%d KB)", path, n/1024)
+}
@@ -0,0 +1,206 @@
+package tui
+
+import (
+	"bufio"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/eddogola/unsplash-go/unsplash"
+	"github.com/eddogola/unsplash-go/unsplash/client"
+)
+
+// redirectTransport sends every request to the test server
+type redirectTransport struct {
+	target *url.URL
+}
+
+func (t redirectTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	req.URL.Scheme, req.URL.Host = t.target.Scheme, t.target.Host
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+// fakeAPI serves search results, collections and photos, recording requests
+type fakeAPI struct {
+	mu       sync.Mutex
+	requests []string
+}
+
+func (f *fakeAPI) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	f.requests = append(f.requests, r.Method+" "+r.URL.Path+"?"+r.URL.Query().Get("page"))
+	f.mu.Unlock()
+
+	page := r.URL.Query().Get("page")
+	switch {
+	case r.URL.Path == "/search/photos":
+		fmt.Fprintf(w, `{"total": 4, "total_pages": 2, "results": [{"id": "p%s-1", "user": {"username": "jane"}}, {"id": "p%s-2"}]}`, page, page)
+	case r.URL.Path == "/collections/":
+		w.Write([]byte(`[{"id": "c1", "title": "Forests", "total_photos": 1}]`))
+	case r.URL.Path == "/collections/c1/photos":
+		w.Write([]byte(`[{"id": "inc1", "description": "pine trees"}]`))
+	case r.URL.Path == "/photos/p1-2/statistics":
+		w.Write([]byte(`{"downloads": {"total": 12}, "views": {"total": 340}}`))
+	case r.URL.Path == "/photos/p1-2/like":
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"photo": {"id": "p1-2"}}`))
+	case r.URL.Path == "/photos/p1-2":
+		w.Write([]byte(`{"id": "p1-2", "width": 4000, "height": 3000, "color": "#0c2640", "likes": 7,
+			"exif": {"make": "Canon", "model": "Canon EOS R5", "exposure_time": "1/250", "aperture": "4.0", "focal_length": "35.0", "iso": 200},
+			"location": {"name": "Kyoto, Japan", "position": {"latitude": 35.0116, "longitude": 135.7681}},
+			"user": {"name": "Jane Doe", "username": "jane"}}`))
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func (f *fakeAPI) count(prefix string) int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	n := 0
+	for _, req := range f.requests {
+		if strings.HasPrefix(req, prefix) {
+			n++
+		}
+	}
+	return n
+}
+
+func newTestBrowser(t *testing.T, private bool) (*Browser, *fakeAPI) {
+	api := &fakeAPI{}
+	ts := httptest.NewServer(api)
+	t.Cleanup(ts.Close)
+	target, _ := url.Parse(ts.URL)
+	c := client.New("id", &http.Client{Transport: redirectTransport{target}}, client.NewConfig())
+	if private {
+		c.Private, c.AuthScopes = true, client.NewAuthScopes(client.WriteLikesScope)
+	}
+	b := New(unsplash.New(c), private)
+	b.PerPage = 2
+	return b, api
+}
+
+func TestPaging(t *testing.T) {
+	b, api := newTestBrowser(t, false)
+	if err := b.Search("kyoto"); err != nil {
+		t.Fatal(err)
+	}
+	view := b.View(80, 20)
+	if !strings.Contains(view, "page 1 of 2") || !strings.Contains(view, "p1-1") {
+		t.Errorf("expected the first page of results, got\n%s", view)
+	}
+
+	b.Handle(KeyRight)
+	if !strings.Contains(b.View(80, 20), "p2-1") {
+		t.Errorf("expected the second page of results, got\n%s", b.View(80, 20))
+	}
+	b.Handle(KeyRight)
+	if b.status != "Last page" {
+		t.Errorf("expected paging past the last page to be refused, got status %q", b.status)
+	}
+	b.Handle(KeyLeft)
+	b.Handle(KeyRight)
+	if n := api.count("GET /search/photos"); n != 2 {
+		t.Errorf("expected pages to be cached, got %d search requests", n)
+	}
+}
+
+func TestNavigation(t *testing.T) {
+	b, _ := newTestBrowser(t, false)
+	b.Handle("c")
+	b.Handle(KeyEnter)
+	if l := b.current(); l.title != "Forests" || l.pages[1][0].photo.ID != "inc1" {
+		t.Fatalf("expected the collection's photos to be opened, got %q", l.title)
+	}
+	b.Handle(KeyEsc)
+	if b.current().title != "Collections" {
+		t.Errorf("expected going back to the collections, got %q", b.current().title)
+	}
+
+	// searching from the prompt
+	for _, k := range []Key{"/", "k", "y", "o", KeyBackspace, "o", "t", "o", KeyEnter} {
+		b.Handle(k)
+	}
+	if b.current().title != "Search “kyoto”" {
+		t.Errorf("expected a search for kyoto, got %q", b.current().title)
+	}
+}
+
+func TestDetailPane(t *testing.T) {
+	b, api := newTestBrowser(t, false)
+	b.Search("kyoto")
+	b.Handle(KeyDown)
+	b.Handle(KeyEnter)
+
+	for _, width := range []int{80, 120} {
+		view := b.View(width, 30)
+		for _, s := range []string{"Canon EOS R5", "1/250s", "ƒ/4", "35mm", "ISO 200", "Kyoto, Japan (35.0116, 135.7681)", "12 downloads", "@jane"} {
+			if !strings.Contains(view, s) {
+				t.Errorf("width %d: expected detail pane to contain %q, got\n%s", width, s, view)
+			}
+		}
+		for i, line := range strings.Split(view, "\n") {
+			if n := visibleLen(line); n > width {
+				t.Errorf("width %d: line %d is %d wide", width, i, n)
+			}
+		}
+	}
+
+	b.Handle(KeyUp)
+	b.Handle(KeyDown)
+	b.Handle(KeyEnter)
+	if n := api.count("GET /photos/p1-2?"); n != 1 {
+		t.Errorf("expected photo details to be cached, got %d requests", n)
+	}
+}
+
+func TestPrivateActions(t *testing.T) {
+	b, api := newTestBrowser(t, false)
+	b.Search("kyoto")
+	b.Handle(KeyDown)
+	b.Handle("L")
+	if !strings.Contains(b.status, "auth login") || api.count("POST") != 0 {
+		t.Errorf("expected liking to require logging in, got status %q", b.status)
+	}
+
+	b, api = newTestBrowser(t, true)
+	b.Search("kyoto")
+	b.Handle(KeyDown)
+	b.Handle("L")
+	if api.count("POST /photos/p1-2/like") != 1 || !b.selected().photo.LikedByUser {
+		t.Errorf("expected the photo to be liked, got status %q", b.status)
+	}
+	if !strings.Contains(b.View(80, 20), "♥ p1-2") {
+		t.Errorf("expected the like to be shown in the list, got\n%s", b.View(80, 20))
+	}
+}
+
+func TestReadKey(t *testing.T) {
+	r := bufio.NewReader(strings.NewReader("\x1b[Aj\r\x1b[5~q\x7f"))
+	var keys []Key
+	for i := 0; i < 6; i++ {
+		k, err := ReadKey(r)
+		if err != nil {
+			t.Fatal(err)
+		}
+		keys = append(keys, k)
+	}
+	expected := []Key{KeyUp, "j", KeyEnter, "", "q", KeyBackspace}
+	if fmt.Sprint(keys) != fmt.Sprint(expected) {
+		t.Errorf("expected %q, got %q", expected, keys)
+	}
+}
+
+func TestFit(t *testing.T) {
+	if got := fit("\x1b[1mhello world\x1b[0m", 6); got != "\x1b[1mhello…\x1b[0m" {
+		t.Errorf("expected truncated text keeping escape sequences, got %q", got)
+	}
+	if got := fit("héllo", 5); got != "héllo" {
+		t.Errorf("expected text that fits to be unchanged, got %q", got)
+	}
+}
@@ -0,0 +1,271 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/eddogola/unsplash-go/unsplash/client"
+	"github.com/eddogola/unsplash-go/unsplash/colors"
+	"github.com/eddogola/unsplash-go/unsplash/exif"
+)
+
+// splitWidth is the minimum screen width at which the detail pane is shown beside the list,
+// rather than below it
+const splitWidth = 100
+
+// View returns the screen, width columns by height rows, as lines separated by "\n"
+func (b *Browser) View(width, height int) string {
+	if width < 20 || height < 5 {
+		return "window too small"
+	}
+	var lines []string
+	l := b.current()
+	header := "Unsplash"
+	if l != nil {
+		header += " › " + l.title + fmt.Sprintf(" — page %d", l.page)
+		if l.total > 0 {
+			header += fmt.Sprintf(" of %d", l.total)
+		}
+	}
+	lines = append(lines, "\x1b[1m"+fit(header, width)+"\x1b[0m")
+
+	body := height - 3 // header, and the status and help lines
+	listWidth, paneLines := width, b.detailLines(width)
+	if width >= splitWidth {
+		listWidth = width / 2
+		paneLines = b.detailLines(width - listWidth - 3)
+	}
+	rows := b.listLines(listWidth, body)
+	if width >= splitWidth {
+		for i := 0; i < body; i++ {
+			left, right := "", ""
+			if i < len(rows) {
+				left = rows[i]
+			}
+			if i < len(paneLines) {
+				right = paneLines[i]
+			}
+			lines = append(lines, pad(left, listWidth)+" │ "+right)
+		}
+	} else {
+		// detail pane below the list, when a photo is open
+		if keep := body - len(paneLines) - 1; len(paneLines) > 0 && len(rows) > keep {
+			if keep < 1 {
+				keep = 1
+			}
+			rows = rows[:keep]
+		}
+		lines = append(lines, rows...)
+		if len(paneLines) > 0 {
+			lines = append(lines, strings.Repeat("─", width))
+			lines = append(lines, paneLines...)
+		}
+		for len(lines) < height-2 {
+			lines = append(lines, "")
+		}
+		lines = lines[:height-2]
+	}
+
+	if b.input != nil {
+		lines = append(lines, fit(b.input.prompt+string(b.input.text)+"█", width))
+	} else {
+		lines = append(lines, fit(b.status, width))
+	}
+	lines = append(lines, "\x1b[2m"+fit(helpText, width)+"\x1b[0m")
+	return strings.Join(lines, "\n")
+}
+
+// listLines returns the rows of the current page, scrolled to keep the cursor visible
+func (b *Browser) listLines(width, height int) []string {
+	l := b.current()
+	if l == nil {
+		return []string{"Press / to search photos, t for topics or c for collections"}
+	}
+	items := l.pages[l.page]
+	if len(items) == 0 {
+		return []string{"No results"}
+	}
+	start := 0
+	if l.cursor >= height {
+		start = l.cursor - height + 1
+	}
+	var rows []string
+	for i := start; i < len(items) && i < start+height; i++ {
+		row := "  " + fit(items[i].label(), width-2)
+		if i == l.cursor {
+			row = "\x1b[7m› " + fit(items[i].label(), width-2) + "\x1b[0m"
+		}
+		rows = append(rows, row)
+	}
+	return rows
+}
+
+func (it item) label() string {
+	switch {
+	case it.photo != nil:
+		p := it.photo
+		desc := p.Description
+		if desc == "" {
+			desc = p.AltDescription
+		}
+		like := " "
+		if p.LikedByUser {
+			like = "♥"
+		}
+		return fmt.Sprintf("%s %-11s %-16s %s", like, p.ID, "@"+p.User.Username, desc)
+	case it.collection != nil:
+		c := it.collection
+		return fmt.Sprintf("%-8s %4d photos  %s", c.ID, c.TotalPhotos, c.Title)
+	case it.topic != nil:
+		t := it.topic
+		return fmt.Sprintf("%-20s %5d photos  %s", t.Slug, t.TotalPhotos, t.Title)
+	}
+	return ""
+}
+
+// detailLines returns the lines of the detail pane, empty if no photo is open
+func (b *Browser) detailLines(width int) []string {
+	d, ok := b.details[b.shown]
+	if !ok {
+		return nil
+	}
+	p := d.photo
+	var lines []string
+	add := func(format string, args ...interface{}) {
+		lines = append(lines, fit(fmt.Sprintf(format, args...), width))
+	}
+
+	title := p.Description
+	if title == "" {
+		title = p.AltDescription
+	}
+	if title == "" {
+		title = p.ID
+	}
+	add("\x1b[1m%s\x1b[0m", title)
+	add("by %s (@%s)", p.User.Name, p.User.Username)
+	colorName := ""
+	if c, err := colors.PhotoColor(p); err == nil {
+		colorName = " " + colors.Name(c)
+	}
+	add("%d × %d  %s%s", p.Width, p.Height, p.Color, colorName)
+	liked := ""
+	if p.LikedByUser {
+		liked = "  ♥ liked by you"
+	}
+	add("%d likes%s", p.Likes, liked)
+	if d.stats != nil {
+		add("%d downloads  %d views", d.stats.Downloads.Total, d.stats.Views.Total)
+	}
+
+	lines = append(lines, "")
+	// fields that could not be parsed are left empty, so parsing errors are not shown
+	e, _ := exif.Parse(p)
+	if camera := e.Camera(); camera != "" {
+		add("Camera    %s", camera)
+	}
+	var settings []string
+	if !e.ExposureTime.IsZero() {
+		settings = append(settings, e.ExposureTime.String())
+	}
+	if e.FNumber > 0 {
+		settings = append(settings, fmt.Sprintf("ƒ/%g", e.FNumber))
+	}
+	if e.FocalLength > 0 {
+		settings = append(settings, fmt.Sprintf("%gmm", e.FocalLength))
+	}
+	if e.ISO > 0 {
+		settings = append(settings, fmt.Sprintf("ISO %d", e.ISO))
+	}
+	if len(settings) > 0 {
+		add("Settings  %s", strings.Join(settings, "  "))
+	}
+	if loc := location(p); loc != "" {
+		add("Location  %s", loc)
+	}
+	if len(p.Tags) > 0 {
+		var tags []string
+		for _, t := range p.Tags {
+			tags = append(tags, t.Title)
+		}
+		add("Tags      %s", strings.Join(tags, ", "))
+	}
+	add("%s", p.Links.HTML)
+	return lines
+}
+
+// location describes where the photo was taken, including coordinates if known
+func location(p *client.Photo) string {
+	loc := p.Location.Name
+	if loc == "" {
+		var parts []string
+		for _, s := range []string{p.Location.City, p.Location.Country} {
+			if s != "" {
+				parts = append(parts, s)
+			}
+		}
+		loc = strings.Join(parts, ", ")
+	}
+	if pos := p.Location.Position; pos.Latitude != 0 || pos.Longitude != 0 {
+		loc = strings.TrimSpace(fmt.Sprintf("%s (%.4f, %.4f)", loc, pos.Latitude, pos.Longitude))
+	}
+	return loc
+}
+
+// fit truncates s to width visible runes, ending truncated text with an ellipsis.
+// Escape sequences are kept, and not counted.
+func fit(s string, width int) string {
+	if width <= 0 {
+		return ""
+	}
+	if visibleLen(s) <= width {
+		return s
+	}
+	var b strings.Builder
+	n, escape := 0, false
+	for _, r := range s {
+		switch {
+		case r == '\x1b':
+			escape = true
+			b.WriteRune(r)
+		case escape:
+			escape = !isLetter(r)
+			b.WriteRune(r)
+		case n < width-1:
+			b.WriteRune(r)
+			n++
+		case n == width-1:
+			b.WriteRune('…')
+			n++
+		}
+	}
+	return b.String()
+}
+
+// pad pads s with spaces to width visible runes
+func pad(s string, width int) string {
+	if n := visibleLen(s); n < width {
+		return s + strings.Repeat(" ", width-n)
+	}
+	return s
+}
+
+// visibleLen returns the number of runes in s, not counting escape sequences
+func visibleLen(s string) int {
+	n, escape := 0, false
+	for _, r := range s {
+		switch {
+		case r == '\x1b':
+			escape = true
+		case escape:
+			escape = !isLetter(r)
+		default:
+			n++
+		}
+	}
+	return n
+}
+
+func isLetter(r rune) bool {
+	return (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z')
+}
@@ -10,6 +10,8 @@ type Unsplash struct {
 	Photos      *PhotosService
 	Collections *CollectionsService
 	Topics      *TopicsService
+	Stats       *StatsService
+	Search      *SearchService
 	client      *client.Client
 }
 
@@ -20,5 +22,7 @@ func New(c *client.Client) *Unsplash {
 	unsplash.Photos = &PhotosService{client: unsplash.client}
 	unsplash.Collections = &CollectionsService{client: unsplash.client}
 	unsplash.Topics = &TopicsService{client: unsplash.client}
+	unsplash.Stats = &StatsService{client: unsplash.client}
+	unsplash.Search = &SearchService{client: unsplash.client}
 	return unsplash
 }
@@ -12,10 +12,15 @@ Under the hood, Unsplash uses Imgix, a powerful image manipulation service to pr
 
 import (
 	"fmt"
+	"net/url"
 
 	"github.com/eddogola/unsplash-go/unsplash/client"
 )
 
+// CropFocalPoint is the Crop value used to crop an image around the focal point
+// set using FocalPointX and FocalPointY
+const CropFocalPoint = "focalpoint"
+
 // ResizeOptions defines parameters that resize a photo
 // Resizing is done using Imgix()
 type ResizeOptions struct {
@@ -30,6 +35,21 @@ type ResizeOptions struct {
 	// https://docs.imgix.com/apis/rendering/size/fit
 	DevicePixelRatio string // min-value is 1, max-value is 5
 	//https://docs.imgix.com/apis/rendering/pixel-density/dpr
+	AspectRatio string // in the form `w:h`, used with Fit set to `crop`
+	// https://docs.imgix.com/apis/rendering/size/ar
+	Rect string // source rectangle to crop the image to, in the form `x,y,w,h`
+	// https://docs.imgix.com/apis/rendering/size/rect
+	Blur string // min-value is 0, max-value is 2000
+	// https://docs.imgix.com/apis/rendering/stylize/blur
+	Saturation string // min-value is -100, max-value is 100
+	// https://docs.imgix.com/apis/rendering/adjustment/sat
+	Background string // hex color, with or without a leading `#`
+	// https://docs.imgix.com/apis/rendering/background/bg
+	Padding string // padding in pixels around the image
+	// https://docs.imgix.com/apis/rendering/border-and-padding/pad
+	FocalPointX string // min-value is 0, max-value is 1
+	FocalPointY string // min-value is 0, max-value is 1
+	// https://docs.imgix.com/apis/rendering/focalpoint-crop
 }
 
 // NewDefaultResizeOptions takes in width and height to return a new default ResizeOptions
@@ -42,8 +62,9 @@ func NewDefaultResizeOptions(w, h int) *ResizeOptions {
 		Auto:   "format"}
 }
 
-func (rOptions ResizeOptions) String() string {
-	var result string
+// Params returns the options as imgix query parameters, leaving out options that are not set.
+// If a focal point is set without Crop, the image is cropped around the focal point.
+func (rOptions ResizeOptions) Params() url.Values {
 	options := map[string]string{
 		"w":    rOptions.Width,
 		"h":    rOptions.Height,
@@ -53,17 +74,77 @@ func (rOptions ResizeOptions) String() string {
 		"q":    rOptions.Quality,
 		"fit":  rOptions.Fit,
 		"dpr":  rOptions.DevicePixelRatio,
+		"ar":   rOptions.AspectRatio,
+		"rect": rOptions.Rect,
+		"blur": rOptions.Blur,
+		"sat":  rOptions.Saturation,
+		"bg":   trimHash(rOptions.Background),
+		"pad":  rOptions.Padding,
+		"fp-x": rOptions.FocalPointX,
+		"fp-y": rOptions.FocalPointY,
 	}
+	if (rOptions.FocalPointX != "" || rOptions.FocalPointY != "") && rOptions.Crop == "" {
+		options["crop"] = CropFocalPoint
+		if rOptions.Fit == "" {
+			options["fit"] = "crop"
+		}
+	}
+
+	params := make(url.Values)
 	for key, val := range options {
 		if val != "" {
-			result += fmt.Sprintf("&%v=%v", key, val)
+			params.Set(key, val)
 		}
 	}
-	return result
+	return params
+}
+
+// String returns the options as query parameters sorted by key, each preceded by `&`
+func (rOptions ResizeOptions) String() string {
+	params := rOptions.Params()
+	if len(params) == 0 {
+		return ""
+	}
+	return "&" + params.Encode()
+}
+
+// BuildURL validates the options, then applies them to link, an imgix URL such as Photo.URLs.Raw.
+// Parameters already in link are kept, unless they are overridden by the options, so the `ixid`
+// parameter Unsplash uses to track photo usage is preserved.
+func BuildURL(link string, rOptions ResizeOptions) (string, error) {
+	if err := rOptions.Validate(); err != nil {
+		return "", err
+	}
+	return applyParams(link, rOptions.Params())
+}
+
+// BuildPhotoURL validates the options, then applies them to the Photo's raw URL
+func BuildPhotoURL(pic *client.Photo, rOptions ResizeOptions) (string, error) {
+	return BuildURL(pic.URLs.Raw, rOptions)
 }
 
 // GetResizedPhotoURL takes a picture and a ResizeOptions object, dynamically resizes Photo.URLs.Raw using the options
-// and returns the resulting URL
+// and returns the resulting URL.
+// Unlike BuildPhotoURL, the options are not validated.
 func GetResizedPhotoURL(pic *client.Photo, rOptions ResizeOptions) string {
-	return pic.URLs.Raw + rOptions.String()
+	link, err := applyParams(pic.URLs.Raw, rOptions.Params())
+	if err != nil {
+		// not a valid URL, fall back to appending the options
+		return pic.URLs.Raw + rOptions.String()
+	}
+	return link
+}
+
+// applyParams sets params in link's query, overriding parameters with the same keys
+func applyParams(link string, params url.Values) (string, error) {
+	URL, err := url.Parse(link)
+	if err != nil {
+		return "", err
+	}
+	q := URL.Query()
+	for key, vals := range params {
+		q[key] = vals
+	}
+	URL.RawQuery = q.Encode()
+	return URL.String(), nil
 }
@@ -0,0 +1,71 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/eddogola/unsplash-go/unsplash/client"
+)
+
+func TestBuildURL(t *testing.T) {
+	raw := "https://images.unsplash.com/photo-1?ixid=abc&w=1080&fm=jpg"
+
+	t.Run("options override existing params and keep ixid", func(t *testing.T) {
+		got, err := BuildURL(raw, ResizeOptions{Width: "400", ImageFormat: "webp", Quality: "80"})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		expected := "https://images.unsplash.com/photo-1?fm=webp&ixid=abc&q=80&w=400"
+		if got != expected {
+			t.Errorf("expected %v but got %v", expected, got)
+		}
+	})
+
+	t.Run("focal point crops around the focal point", func(t *testing.T) {
+		got, err := BuildURL(raw, ResizeOptions{FocalPointX: "0.3", FocalPointY: "0.6", Background: "#fff"})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		expected := "https://images.unsplash.com/photo-1?bg=fff&crop=focalpoint&fit=crop&fm=jpg&fp-x=0.3&fp-y=0.6&ixid=abc&w=1080"
+		if got != expected {
+			t.Errorf("expected %v but got %v", expected, got)
+		}
+	})
+
+	t.Run("invalid options", func(t *testing.T) {
+		invalid := []ResizeOptions{
+			{DevicePixelRatio: "6"},
+			{DevicePixelRatio: "0.5"},
+			{Quality: "101"},
+			{Saturation: "-101"},
+			{AspectRatio: "16-9"},
+			{Rect: "0,0,100"},
+			{Background: "#ggg"},
+			{ImageFormat: "bmp"},
+			{Crop: "faces,middle"},
+			{Width: "-1"},
+		}
+		for _, opts := range invalid {
+			if _, err := BuildURL(raw, opts); err == nil {
+				t.Errorf("expected an error for %+v", opts)
+			} else if _, ok := err.(ErrInvalidOption); !ok {
+				t.Errorf("expected an ErrInvalidOption but got %T", err)
+			}
+		}
+	})
+
+	t.Run("resized photo URL is deterministic", func(t *testing.T) {
+		var p client.Photo
+		p.URLs.Raw = raw
+		opts := *NewDefaultResizeOptions(200, 100)
+		first := GetResizedPhotoURL(&p, opts)
+		for i := 0; i < 10; i++ {
+			if got := GetResizedPhotoURL(&p, opts); got != first {
+				t.Fatalf("expected %v but got %v", first, got)
+			}
+		}
+		expected := "https://images.unsplash.com/photo-1?auto=format&fm=jpg&h=100&ixid=abc&w=200"
+		if first != expected {
+			t.Errorf("expected %v but got %v", expected, first)
+		}
+	})
+}
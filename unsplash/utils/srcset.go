@@ -0,0 +1,162 @@
+package utils
+
+import (
+	"fmt"
+	"html"
+	"sort"
+	"strings"
+
+	"github.com/eddogola/unsplash-go/unsplash/client"
+)
+
+// DefaultWidths are the breakpoints used in responsive images when none are provided
+var DefaultWidths = []int{320, 640, 960, 1280, 1920}
+
+// DefaultFormats are the image formats used in responsive images when none are provided.
+// The last format is the one used by browsers that support none of the others.
+var DefaultFormats = []string{"avif", "webp", "jpg"}
+
+var mimeTypes = map[string]string{
+	"avif": "image/avif",
+	"webp": "image/webp",
+	"jpg":  "image/jpeg",
+	"pjpg": "image/jpeg",
+	"png":  "image/png",
+	"gif":  "image/gif",
+}
+
+// MIMEType returns the MIME type of images in an imgix format, e.g. `image/webp` for `webp`,
+// or an empty string if the format isn't an image format supported in responsive images
+func MIMEType(format string) string {
+	return mimeTypes[strings.ToLower(format)]
+}
+
+// PictureOptions defines how a responsive image is generated
+type PictureOptions struct {
+	// Widths are the image widths, in pixels, listed in every srcset
+	Widths []int
+	// Formats are the image formats offered, in order of preference
+	Formats []string
+	// Sizes is the `sizes` attribute, telling browsers how wide the image is displayed.
+	// Defaults to `100vw`.
+	Sizes string
+	// Base holds options applied to every image URL, e.g. Quality or Crop.
+	// Its Width and ImageFormat are set for every URL generated.
+	Base ResizeOptions
+	// Lazy adds `loading="lazy"` to the image
+	Lazy bool
+}
+
+// PictureSource defines a `<source>` element offering the image in one format
+type PictureSource struct {
+	Type   string
+	Srcset string
+}
+
+// Picture holds the attributes of a responsive image
+type Picture struct {
+	Sources []PictureSource
+	Src     string // the fallback image, used by browsers that do not support srcset
+	Srcset  string // srcset of the fallback format
+	Sizes   string
+	Width   int
+	Height  int
+	Alt     string
+	Lazy    bool
+}
+
+// Srcset returns a `srcset` attribute value listing the photo at each of the given widths.
+// rOptions are applied to every URL, with the width set for each.
+func Srcset(pic *client.Photo, widths []int, rOptions ResizeOptions) (string, error) {
+	candidates := make([]string, 0, len(widths))
+	for _, w := range widths {
+		rOptions.Width = fmt.Sprint(w)
+		link, err := BuildPhotoURL(pic, rOptions)
+		if err != nil {
+			return "", err
+		}
+		candidates = append(candidates, fmt.Sprintf("%s %dw", link, w))
+	}
+	return strings.Join(candidates, ", "), nil
+}
+
+// NewPicture generates srcsets of the photo in every format in opts, for use in a `<picture>` element.
+// The image's width and height are set from Photo.Width and Photo.Height to avoid layout shift,
+// and its alt text from Photo.AltDescription.
+func NewPicture(pic *client.Photo, opts PictureOptions) (*Picture, error) {
+	widths := append([]int(nil), opts.Widths...)
+	if len(widths) == 0 {
+		widths = append(widths, DefaultWidths...)
+	}
+	sort.Ints(widths)
+	formats := opts.Formats
+	if len(formats) == 0 {
+		formats = DefaultFormats
+	}
+	sizes := opts.Sizes
+	if sizes == "" {
+		sizes = "100vw"
+	}
+
+	p := &Picture{
+		Sizes:  sizes,
+		Width:  pic.Width,
+		Height: pic.Height,
+		Alt:    pic.AltDescription,
+		Lazy:   opts.Lazy,
+	}
+	for i, format := range formats {
+		mimeType := MIMEType(format)
+		if mimeType == "" {
+			return nil, ErrInvalidOption{"fm", format, "not a supported responsive image format"}
+		}
+		format = strings.ToLower(format)
+		rOptions := opts.Base
+		rOptions.ImageFormat = format
+		srcset, err := Srcset(pic, widths, rOptions)
+		if err != nil {
+			return nil, err
+		}
+		if i < len(formats)-1 {
+			p.Sources = append(p.Sources, PictureSource{Type: mimeType, Srcset: srcset})
+			continue
+		}
+		// the last format is the fallback, used in the <img> element
+		p.Srcset = srcset
+		rOptions.Width = fmt.Sprint(widths[len(widths)-1])
+		p.Src, err = BuildPhotoURL(pic, rOptions)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return p, nil
+}
+
+// HTML returns the `<picture>` element, with attribute values escaped
+func (p *Picture) HTML() string {
+	var b strings.Builder
+	b.WriteString("<picture>")
+	for _, source := range p.Sources {
+		fmt.Fprintf(&b, `<source type="%s" srcset="%s" sizes="%s">`,
+			html.EscapeString(source.Type), html.EscapeString(source.Srcset), html.EscapeString(p.Sizes))
+	}
+	fmt.Fprintf(&b, `<img src="%s" srcset="%s" sizes="%s" alt="%s"`,
+		html.EscapeString(p.Src), html.EscapeString(p.Srcset), html.EscapeString(p.Sizes), html.EscapeString(p.Alt))
+	if p.Width > 0 && p.Height > 0 {
+		fmt.Fprintf(&b, ` width="%d" height="%d"`, p.Width, p.Height)
+	}
+	if p.Lazy {
+		b.WriteString(` loading="lazy"`)
+	}
+	b.WriteString("></picture>")
+	return b.String()
+}
+
+// PictureHTML returns a `<picture>` element displaying the photo, generated using NewPicture
+func PictureHTML(pic *client.Photo, opts PictureOptions) (string, error) {
+	p, err := NewPicture(pic, opts)
+	if err != nil {
+		return "", err
+	}
+	return p.HTML(), nil
+}
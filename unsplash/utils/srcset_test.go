@@ -0,0 +1,66 @@
+package utils
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/eddogola/unsplash-go/unsplash/client"
+)
+
+func TestPictureHTML(t *testing.T) {
+	var p client.Photo
+	p.Width = 4000
+	p.Height = 3000
+	p.AltDescription = `a "quoted" <cat>`
+	p.URLs.Raw = "https://images.unsplash.com/photo-1?ixid=abc"
+
+	t.Run("srcset", func(t *testing.T) {
+		got, err := Srcset(&p, []int{400, 800}, ResizeOptions{ImageFormat: "webp"})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		expected := "https://images.unsplash.com/photo-1?fm=webp&ixid=abc&w=400 400w, " +
+			"https://images.unsplash.com/photo-1?fm=webp&ixid=abc&w=800 800w"
+		if got != expected {
+			t.Errorf("expected %v but got %v", expected, got)
+		}
+	})
+
+	t.Run("picture element", func(t *testing.T) {
+		got, err := PictureHTML(&p, PictureOptions{Widths: []int{800, 400}, Sizes: "50vw", Lazy: true})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		for _, expected := range []string{
+			`<source type="image/avif" srcset="https://images.unsplash.com/photo-1?fm=avif&amp;ixid=abc&amp;w=400 400w, `,
+			`<source type="image/webp"`,
+			`<img src="https://images.unsplash.com/photo-1?fm=jpg&amp;ixid=abc&amp;w=800"`,
+			`sizes="50vw"`,
+			`alt="a &#34;quoted&#34; &lt;cat&gt;"`,
+			`width="4000" height="3000" loading="lazy"></picture>`,
+		} {
+			if !strings.Contains(got, expected) {
+				t.Errorf("expected %v to contain %v", got, expected)
+			}
+		}
+	})
+
+	t.Run("formats ignore case", func(t *testing.T) {
+		got, err := PictureHTML(&p, PictureOptions{Widths: []int{400}, Formats: []string{"WEBP", "JPG"}})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		for _, expected := range []string{`<source type="image/webp" srcset="https://images.unsplash.com/photo-1?fm=webp`, `fm=jpg`} {
+			if !strings.Contains(got, expected) {
+				t.Errorf("expected %v to contain %v", got, expected)
+			}
+		}
+	})
+
+	t.Run("unsupported format", func(t *testing.T) {
+		_, err := PictureHTML(&p, PictureOptions{Formats: []string{"bmp"}})
+		if _, ok := err.(ErrInvalidOption); !ok {
+			t.Errorf("expected an ErrInvalidOption but got %v", err)
+		}
+	})
+}
@@ -0,0 +1,161 @@
+package utils
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ErrInvalidOption is raised when a resize option is outside the range, or not one of the values,
+// accepted by imgix
+type ErrInvalidOption struct {
+	Param  string
+	Value  string
+	Reason string
+}
+
+func (e ErrInvalidOption) Error() string {
+	return fmt.Sprintf("invalid value %q for imgix parameter `%s`: %s", e.Value, e.Param, e.Reason)
+}
+
+var (
+	imageFormats = []string{"avif", "blurhash", "gif", "jp2", "jpg", "json", "jxr", "mp4", "pjpg", "png", "png8", "png32", "webm", "webp"}
+	fitModes     = []string{"clamp", "clip", "crop", "facearea", "fill", "fillmax", "max", "min", "scale"}
+	cropModes    = []string{"top", "bottom", "left", "right", "faces", "focalpoint", "edges", "entropy"}
+	autoModes    = []string{"compress", "enhance", "format", "redeye"}
+)
+
+// Validate checks that the options are within the ranges accepted by imgix
+func (rOptions ResizeOptions) Validate() error {
+	checks := []struct {
+		param, value string
+		check        func(string) string
+	}{
+		{"w", rOptions.Width, numberIn(0, -1, false)},
+		{"h", rOptions.Height, numberIn(0, -1, false)},
+		{"dpr", rOptions.DevicePixelRatio, numberIn(1, 5, true)},
+		{"q", rOptions.Quality, integerIn(0, 100)},
+		{"blur", rOptions.Blur, integerIn(0, 2000)},
+		{"sat", rOptions.Saturation, integerIn(-100, 100)},
+		{"pad", rOptions.Padding, integerIn(0, -1)},
+		{"fp-x", rOptions.FocalPointX, numberIn(0, 1, true)},
+		{"fp-y", rOptions.FocalPointY, numberIn(0, 1, true)},
+		{"fm", rOptions.ImageFormat, oneOf(imageFormats)},
+		{"fit", rOptions.Fit, oneOf(fitModes)},
+		{"crop", rOptions.Crop, listOf(cropModes)},
+		{"auto", rOptions.Auto, listOf(autoModes)},
+		{"ar", rOptions.AspectRatio, aspectRatio},
+		{"rect", rOptions.Rect, rect},
+		{"bg", trimHash(rOptions.Background), hexColor},
+	}
+	for _, c := range checks {
+		if c.value == "" {
+			continue
+		}
+		if reason := c.check(c.value); reason != "" {
+			return ErrInvalidOption{c.param, c.value, reason}
+		}
+	}
+	return nil
+}
+
+// numberIn checks that a value is a number greater than min, or equal to it if inclusive.
+// The value must also be at most max, unless max is negative.
+func numberIn(min, max float64, inclusive bool) func(string) string {
+	return func(val string) string {
+		n, err := strconv.ParseFloat(val, 64)
+		if err != nil {
+			return "not a number"
+		}
+		if n < min || (!inclusive && n == min) {
+			return fmt.Sprintf("must be greater than %v", min)
+		}
+		if max >= 0 && n > max {
+			return fmt.Sprintf("must be at most %v", max)
+		}
+		return ""
+	}
+}
+
+// integerIn checks that a value is an integer between min and max inclusive.
+// max is not checked if it's negative.
+func integerIn(min, max int) func(string) string {
+	return func(val string) string {
+		n, err := strconv.Atoi(val)
+		if err != nil {
+			return "not an integer"
+		}
+		if n < min || (max >= 0 && n > max) {
+			if max < 0 {
+				return fmt.Sprintf("must be at least %d", min)
+			}
+			return fmt.Sprintf("must be between %d and %d", min, max)
+		}
+		return ""
+	}
+}
+
+func oneOf(allowed []string) func(string) string {
+	return func(val string) string {
+		for _, a := range allowed {
+			if val == a {
+				return ""
+			}
+		}
+		return "must be one of " + strings.Join(allowed, ", ")
+	}
+}
+
+// listOf checks a comma separated list of values
+func listOf(allowed []string) func(string) string {
+	return func(val string) string {
+		for _, v := range strings.Split(val, ",") {
+			if reason := oneOf(allowed)(v); reason != "" {
+				return reason
+			}
+		}
+		return ""
+	}
+}
+
+func aspectRatio(val string) string {
+	parts := strings.Split(val, ":")
+	if len(parts) != 2 {
+		return "must be in the form w:h"
+	}
+	for _, p := range parts {
+		if reason := numberIn(0, -1, false)(p); reason != "" {
+			return "must be in the form w:h, with positive numbers"
+		}
+	}
+	return ""
+}
+
+func rect(val string) string {
+	parts := strings.Split(val, ",")
+	if len(parts) != 4 {
+		return "must be in the form x,y,w,h"
+	}
+	for _, p := range parts {
+		if reason := numberIn(0, -1, true)(p); reason != "" {
+			return "must be in the form x,y,w,h, with non-negative numbers"
+		}
+	}
+	return ""
+}
+
+func hexColor(val string) string {
+	switch len(val) {
+	case 3, 4, 6, 8:
+	default:
+		return "must be a 3, 4, 6 or 8 digit hex color"
+	}
+	if _, err := strconv.ParseUint(val, 16, 32); err != nil {
+		return "must be a hex color"
+	}
+	return ""
+}
+
+func trimHash(color string) string {
+	return strings.TrimPrefix(color, "#")
+}